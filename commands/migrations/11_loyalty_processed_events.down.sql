@@ -0,0 +1 @@
+DROP TABLE IF EXISTS loyalty_app.processed_events;
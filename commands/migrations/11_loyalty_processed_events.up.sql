@@ -0,0 +1,7 @@
+-- events from kafka applied to loyalty accounts, a redelivered event is skipped.
+-- event is saved in the same transaction as the balance update
+CREATE TABLE IF NOT EXISTS loyalty_app.processed_events
+(
+    event_id     text PRIMARY KEY, -- id of the event or topic/partition/offset of kafka message
+    processed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
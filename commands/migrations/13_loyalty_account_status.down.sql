@@ -0,0 +1 @@
+ALTER TABLE loyalty_app.accounts DROP COLUMN IF EXISTS frozen_until, DROP COLUMN IF EXISTS status;
@@ -0,0 +1,6 @@
+-- accounts are frozen while user is blocked and closed when user is deleted in sso,
+-- operations with frozen and closed accounts are rejected
+ALTER TABLE loyalty_app.accounts
+    ADD COLUMN IF NOT EXISTS status text NOT NULL DEFAULT 'active'
+        CONSTRAINT account_status CHECK (status IN ('active', 'frozen', 'closed')),
+    ADD COLUMN IF NOT EXISTS frozen_until TIMESTAMPTZ; -- frozen account is active after, null - until unfrozen
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS loyalty_app.loyalty_transactions_account_created_idx;
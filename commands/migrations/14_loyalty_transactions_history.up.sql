@@ -0,0 +1,3 @@
+-- transaction history is read page by page from newest to oldest
+CREATE INDEX IF NOT EXISTS loyalty_transactions_account_created_idx
+    ON loyalty_app.loyalty_transactions (account_uuid, created_at DESC, id DESC);
@@ -0,0 +1,2 @@
+-- lets support staff read transaction history of any loyalty account
+INSERT INTO role_permissions(role, permission) VALUES ('admin', 'loyalty:transactions:read:any') ON CONFLICT DO NOTHING;
@@ -0,0 +1,14 @@
+-- keys of POST /loyalty requests, a repeated request returns result of the operation
+-- instead of applying it again. Key is saved in the same transaction as the operation,
+-- so it's not saved if the operation fails.
+CREATE TABLE IF NOT EXISTS loyalty_app.idempotency_keys
+(
+    requested_by   uuid NOT NULL, -- user sent the request, keys of different users don't clash
+    key            text NOT NULL,
+    request_hash   text NOT NULL, -- hash of the operation, the same key with other operation is rejected
+    transaction_id uuid REFERENCES loyalty_app.loyalty_transactions (id),
+    account_uuid   uuid,
+    balance        DECIMAL(12,0), -- balance after the operation
+    created_at     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (requested_by, key)
+);
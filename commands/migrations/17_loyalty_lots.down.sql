@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS loyalty_app.lots;
+-- postgres can't drop enum value, 'e' is kept in operation_type
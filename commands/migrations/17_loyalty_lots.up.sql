@@ -0,0 +1,26 @@
+-- 'e' - points written off when they expire
+ALTER TYPE operation_type ADD VALUE IF NOT EXISTS 'e';
+
+-- deposited points are tracked as lots, withdraw consumes lots expiring first,
+-- expired remaining points are written off. Sum of remaining points of account lots
+-- equals account balance.
+CREATE TABLE IF NOT EXISTS loyalty_app.lots
+(
+    id             uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_uuid   uuid NOT NULL REFERENCES loyalty_app.accounts (uuid),
+    transaction_id uuid REFERENCES loyalty_app.loyalty_transactions (id), -- deposit created the lot
+    amount         integer NOT NULL CHECK (amount > 0),
+    remaining      integer NOT NULL CONSTRAINT lot_remaining CHECK (remaining >= 0 AND remaining <= amount),
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at     TIMESTAMPTZ -- null - points never expire
+);
+CREATE INDEX IF NOT EXISTS lots_account_open_idx
+    ON loyalty_app.lots (account_uuid, expires_at, created_at) WHERE remaining > 0;
+CREATE INDEX IF NOT EXISTS lots_expires_at_idx
+    ON loyalty_app.lots (expires_at) WHERE remaining > 0;
+
+-- points earned before lots were introduced were granted without expiry and never expire
+INSERT INTO loyalty_app.lots (account_uuid, amount, remaining, expires_at)
+SELECT uuid, balance, balance, NULL
+FROM loyalty_app.accounts
+WHERE balance > 0;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS loyalty_app.holds;
+ALTER TABLE loyalty_app.accounts
+    DROP CONSTRAINT IF EXISTS not_negative_available,
+    DROP COLUMN IF EXISTS available,
+    DROP COLUMN IF EXISTS held;
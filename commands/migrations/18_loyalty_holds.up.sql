@@ -0,0 +1,26 @@
+-- held points are reserved by active holds, they stay in balance until hold is captured,
+-- available balance (balance - held) must not be negative
+ALTER TABLE loyalty_app.accounts
+    ADD COLUMN IF NOT EXISTS held DECIMAL(12,0) NOT NULL DEFAULT 0
+        CONSTRAINT not_negative_held CHECK (held >= 0),
+    ADD COLUMN IF NOT EXISTS available DECIMAL(12,0) GENERATED ALWAYS AS (balance - held) STORED,
+    ADD CONSTRAINT not_negative_available CHECK (balance - held >= 0);
+
+-- hold reserves points until the order is confirmed (captured - points are withdrawn)
+-- or cancelled (voided), not finished hold expires after expires_at
+CREATE TABLE IF NOT EXISTS loyalty_app.holds
+(
+    id             uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_uuid   uuid NOT NULL REFERENCES loyalty_app.accounts (uuid),
+    amount         integer NOT NULL CHECK (amount > 0),
+    captured       integer NOT NULL DEFAULT 0 CHECK (captured >= 0 AND captured <= amount),
+    status         text NOT NULL DEFAULT 'active'
+        CONSTRAINT hold_status CHECK (status IN ('active', 'captured', 'voided', 'expired')),
+    comment        text NOT NULL,
+    transaction_id uuid REFERENCES loyalty_app.loyalty_transactions (id), -- withdraw made by capture
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modified       TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at     TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS holds_active_expires_at_idx
+    ON loyalty_app.holds (expires_at) WHERE status = 'active';
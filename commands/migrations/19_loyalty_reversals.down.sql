@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS loyalty_app.loyalty_transactions_reversal_of_idx;
+ALTER TABLE loyalty_app.loyalty_transactions DROP COLUMN IF EXISTS reversal_of;
@@ -0,0 +1,6 @@
+-- reversal undoes a part or the whole of a deposit or withdraw, it's saved as the opposite
+-- operation linked to the original transaction. Sum of reversals doesn't exceed the original amount.
+ALTER TABLE loyalty_app.loyalty_transactions
+    ADD COLUMN IF NOT EXISTS reversal_of uuid REFERENCES loyalty_app.loyalty_transactions (id);
+CREATE INDEX IF NOT EXISTS loyalty_transactions_reversal_of_idx
+    ON loyalty_app.loyalty_transactions (reversal_of) WHERE reversal_of IS NOT NULL;
@@ -0,0 +1,2 @@
+-- lets support staff reverse mistaken loyalty operations
+INSERT INTO role_permissions(role, permission) VALUES ('admin', 'loyalty:reverse') ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS loyalty_app.loyalty_transactions_sent_transfers_idx;
+DROP INDEX IF EXISTS loyalty_app.loyalty_transactions_transfer_id_idx;
+ALTER TABLE loyalty_app.loyalty_transactions DROP COLUMN IF EXISTS transfer_id;
@@ -0,0 +1,9 @@
+-- transfer between users is saved as withdraw from sender and deposit to recipient
+-- sharing transfer_id, both are written in the same transaction
+ALTER TABLE loyalty_app.loyalty_transactions ADD COLUMN IF NOT EXISTS transfer_id uuid;
+CREATE INDEX IF NOT EXISTS loyalty_transactions_transfer_id_idx
+    ON loyalty_app.loyalty_transactions (transfer_id) WHERE transfer_id IS NOT NULL;
+-- daily transfer limit sums transfers sent by account within the last day
+CREATE INDEX IF NOT EXISTS loyalty_transactions_sent_transfers_idx
+    ON loyalty_app.loyalty_transactions (account_uuid, created_at)
+    WHERE transfer_id IS NOT NULL AND transaction_type = 'w';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS loyalty_app.loyalty_transactions_purchases_idx;
+ALTER TABLE loyalty_app.loyalty_transactions DROP COLUMN IF EXISTS purchase_id;
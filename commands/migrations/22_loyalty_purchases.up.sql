@@ -0,0 +1,5 @@
+-- points earned by purchases are deposits linked to the purchase, earned points of a user
+-- within a period are limited by earning rules caps
+ALTER TABLE loyalty_app.loyalty_transactions ADD COLUMN IF NOT EXISTS purchase_id text;
+CREATE INDEX IF NOT EXISTS loyalty_transactions_purchases_idx
+    ON loyalty_app.loyalty_transactions (account_uuid, created_at) WHERE purchase_id IS NOT NULL;
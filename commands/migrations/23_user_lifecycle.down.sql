@@ -0,0 +1,4 @@
+DELETE FROM role_permissions WHERE role = 'admin' AND permission = 'sso:users:manage';
+ALTER TABLE users DROP COLUMN IF EXISTS block_reason;
+ALTER TABLE users DROP COLUMN IF EXISTS blocked_until;
+ALTER TABLE users DROP COLUMN IF EXISTS blocked_at;
@@ -0,0 +1,2 @@
+ALTER TABLE loyalty_app.idempotency_keys DROP COLUMN IF EXISTS response_error;
+ALTER TABLE loyalty_app.idempotency_keys DROP COLUMN IF EXISTS response_status;
@@ -0,0 +1,8 @@
+-- http status of the response to the first request, a repeated request gets the same
+-- status and body: account_uuid and balance of applied operation or response_error of
+-- rejected one (e.g. negative balance). Rejected operation is saved without transaction.
+ALTER TABLE loyalty_app.idempotency_keys ADD COLUMN IF NOT EXISTS response_status smallint;
+ALTER TABLE loyalty_app.idempotency_keys ADD COLUMN IF NOT EXISTS response_error text;
+
+-- keys saved before status was stored were only saved for successful operations
+UPDATE loyalty_app.idempotency_keys SET response_status = 200 WHERE response_status IS NULL;
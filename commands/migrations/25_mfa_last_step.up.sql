@@ -0,0 +1,3 @@
+-- time step of the last accepted TOTP code, a code is accepted once: codes of
+-- this or earlier steps are rejected (RFC 6238, section 5.2)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS mfa_last_step bigint;
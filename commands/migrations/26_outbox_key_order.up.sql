@@ -0,0 +1,4 @@
+-- outbox relay publishes events of a key in order, it looks for earlier unsent
+-- events of the same key before claiming an event
+CREATE INDEX IF NOT EXISTS outbox_pending_key_idx
+    ON outbox (topic, message_key, id) WHERE sent_at IS NULL;
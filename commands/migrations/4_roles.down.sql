@@ -0,0 +1,7 @@
+UPDATE users SET is_admin = EXISTS (
+    SELECT 1 FROM user_roles WHERE user_uuid = users.uuid AND role = 'admin'
+);
+
+DROP TABLE IF EXISTS user_roles;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,4 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS verified_at TIMESTAMP;
+
+-- users registered before email verification was introduced are trusted
+UPDATE users SET verified_at = created WHERE verified_at IS NULL;
@@ -0,0 +1,12 @@
+-- TOTP secret is encrypted by sso, MFA is enabled once enrollment is confirmed
+ALTER TABLE users ADD COLUMN IF NOT EXISTS mfa_secret bytea;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS mfa_enabled_at TIMESTAMP;
+
+-- one-time codes to complete login without authenticator app, only hashes are stored
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes
+(
+    user_uuid uuid NOT NULL REFERENCES users (uuid) ON DELETE CASCADE,
+    code_hash text NOT NULL,
+    used_at   TIMESTAMP,
+    PRIMARY KEY (user_uuid, code_hash)
+);
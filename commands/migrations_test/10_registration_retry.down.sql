@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS users_message_status_idx;
+ALTER TABLE users DROP COLUMN IF EXISTS message_retry_at;
+ALTER TABLE users DROP COLUMN IF EXISTS message_attempts;
@@ -0,0 +1,5 @@
+-- registration message is re-sent to users stuck in failed or inProgress status
+ALTER TABLE users ADD COLUMN IF NOT EXISTS message_attempts integer NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS message_retry_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS users_message_status_idx ON users (created) WHERE message_status <> 'successful';
@@ -0,0 +1,2 @@
+-- lets support staff replay loyalty events moved to dead-letter topic
+INSERT INTO role_permissions(role, permission) VALUES ('admin', 'loyalty:dlq:replay') ON CONFLICT DO NOTHING;
@@ -0,0 +1,7 @@
+-- blocked users can't login, block without blocked_until lasts until the user is unblocked
+ALTER TABLE users ADD COLUMN IF NOT EXISTS blocked_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS blocked_until TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS block_reason text;
+
+-- lets support staff block, unblock and delete users
+INSERT INTO role_permissions(role, permission) VALUES ('admin', 'sso:users:manage') ON CONFLICT DO NOTHING;
@@ -0,0 +1,5 @@
+-- encrypted keys can't be decrypted in db, they are removed: tokens signed by
+-- them are rejected and the key from config is used until the next rotation
+DELETE FROM signing_keys WHERE private_key IS NULL;
+ALTER TABLE signing_keys ALTER COLUMN private_key SET NOT NULL;
+ALTER TABLE signing_keys DROP COLUMN IF EXISTS private_key_encrypted;
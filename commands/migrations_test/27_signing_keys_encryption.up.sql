@@ -0,0 +1,4 @@
+-- private keys are encrypted by sso (AES-GCM, key mfa.encryptionKey), plain
+-- text keys saved before are encrypted by sso on start and private_key is cleared
+ALTER TABLE signing_keys ADD COLUMN IF NOT EXISTS private_key_encrypted bytea;
+ALTER TABLE signing_keys ALTER COLUMN private_key DROP NOT NULL;
@@ -0,0 +1,13 @@
+-- keys used to sign jwt tokens, only one key is active (signs new tokens),
+-- retiring keys are accepted for verification until expires_at.
+CREATE TABLE IF NOT EXISTS signing_keys
+(
+    kid text PRIMARY KEY,
+    algorithm text NOT NULL,
+    private_key text NOT NULL,
+    is_active boolean NOT NULL DEFAULT FALSE,
+    created TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS signing_keys_single_active ON signing_keys (is_active) WHERE is_active;
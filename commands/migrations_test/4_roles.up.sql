@@ -0,0 +1,36 @@
+-- roles grant permissions, permissions are embedded into access tokens
+CREATE TABLE IF NOT EXISTS roles
+(
+    name text PRIMARY KEY,
+    created TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions
+(
+    role text REFERENCES roles(name) ON DELETE CASCADE,
+    permission text NOT NULL,
+    PRIMARY KEY (role, permission)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles
+(
+    user_uuid uuid REFERENCES users(uuid) ON DELETE CASCADE,
+    role text REFERENCES roles(name) ON DELETE CASCADE,
+    created TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_uuid, role)
+);
+
+INSERT INTO roles(name) VALUES ('admin'), ('user') ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions(role, permission)
+VALUES ('admin', 'loyalty:deposit'),
+       ('admin', 'loyalty:withdraw'),
+       ('admin', 'loyalty:withdraw:any'),
+       ('admin', 'sso:keys:rotate'),
+       ('admin', 'sso:roles:manage'),
+       ('user', 'loyalty:withdraw')
+ON CONFLICT DO NOTHING;
+
+-- is_admin is kept for rollback, user_roles is the source of truth
+INSERT INTO user_roles(user_uuid, role) SELECT uuid, 'user' FROM users ON CONFLICT DO NOTHING;
+INSERT INTO user_roles(user_uuid, role) SELECT uuid, 'admin' FROM users WHERE is_admin ON CONFLICT DO NOTHING;
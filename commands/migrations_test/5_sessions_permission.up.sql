@@ -0,0 +1,2 @@
+-- lets support staff list and revoke sessions of any user
+INSERT INTO role_permissions(role, permission) VALUES ('admin', 'sso:sessions:manage') ON CONFLICT DO NOTHING;
@@ -0,0 +1,2 @@
+-- lets support staff unlock logins blocked after failed attempts
+INSERT INTO role_permissions(role, permission) VALUES ('admin', 'sso:users:unlock') ON CONFLICT DO NOTHING;
@@ -0,0 +1,18 @@
+-- events are saved in the same transaction as data changes they describe and
+-- published to kafka by sso outbox relay
+CREATE TABLE IF NOT EXISTS outbox
+(
+    id              bigserial PRIMARY KEY,
+    topic           text      NOT NULL,
+    message_key     text      NOT NULL,
+    -- full protobuf message name to decode payload
+    message_type    text      NOT NULL,
+    payload         bytea     NOT NULL,
+    attempts        integer   NOT NULL DEFAULT 0,
+    last_error      text,
+    created         TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at         TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (next_attempt_at) WHERE sent_at IS NULL;
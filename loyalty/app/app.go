@@ -34,6 +34,7 @@ type App struct {
 	ServerHttp           *serverhttp.App
 	ServerLoyaltyStorage loyaltyStorage
 	ServerConsumer       io.Closer
+	LoyaltyService       *loyaltyservice.Loyalty
 	ServerOpenTelemetry  *trace.TracerProvider
 }
 
@@ -76,6 +77,7 @@ func New() (*App, error) {
 		ServerHttp:           serverHttp,
 		ServerLoyaltyStorage: loyalStorage,
 		ServerConsumer:       consumer,
+		LoyaltyService:       loyalService,
 		ServerOpenTelemetry:  tp,
 	}, nil
 }
@@ -93,6 +95,10 @@ func (a *App) startHTTPServer() chan error {
 func (a *App) Start(ctx context.Context) error {
 	log.Info("http server starting")
 	errHTTPChan := a.startHTTPServer()
+	log.Info("points expiration starting")
+	go a.LoyaltyService.RunPointsExpiration(ctx)
+	log.Info("holds expiration starting")
+	go a.LoyaltyService.RunHoldExpiration(ctx)
 	select {
 	case <-ctx.Done():
 		return a.Stop()
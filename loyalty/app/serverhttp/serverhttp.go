@@ -11,6 +11,8 @@ import (
 	"github.com/AlexBlackNn/authloyalty/loyalty/cmd/router"
 	"github.com/AlexBlackNn/authloyalty/loyalty/internal/config"
 	"github.com/AlexBlackNn/authloyalty/loyalty/internal/handlershttp/http/v1"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/jwt"
+	"github.com/AlexBlackNn/authloyalty/loyalty/pkg/ssoclient"
 )
 
 // App service consists all entities needed to work.
@@ -21,6 +23,7 @@ type App struct {
 	loyaltyService *loyaltyservice.Loyalty
 	HandlersV1     v1.LoyaltyHandlers
 	HealthChecker  v1.HealthHandlers
+	TokenVerifier  *jwt.Verifier
 }
 
 // New creates App collecting handlers and server
@@ -30,6 +33,12 @@ func New(
 	loyaltyService *loyaltyservice.Loyalty,
 ) (*App, error) {
 
+	ssoClient, err := ssoclient.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	tokenVerifier := jwt.NewVerifier(cfg, ssoClient)
+
 	projectHandlersV1 := v1.New(log, cfg, loyaltyService)
 	healthHandlersV1 := v1.NewHealth(log, loyaltyService)
 	srv := &http.Server{
@@ -39,6 +48,7 @@ func New(
 			log,
 			projectHandlersV1,
 			healthHandlersV1,
+			tokenVerifier,
 		),
 		ReadTimeout:  time.Duration(cfg.ServerTimeout.ReadTimeout) * time.Second,
 		WriteTimeout: time.Duration(cfg.ServerTimeout.WriteTimeout) * time.Second,
@@ -50,5 +60,6 @@ func New(
 		Srv:           srv,
 		HandlersV1:    projectHandlersV1,
 		HealthChecker: healthHandlersV1,
+		TokenVerifier: tokenVerifier,
 	}, nil
 }
@@ -87,6 +87,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.UserLoyalty"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Repeated request with the same key returns result of the first one",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -99,6 +105,290 @@ const docTemplate = `{
                 }
             }
         },
+        "/loyalty/dlq/replay": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Publish loyalty events moved to dead-letter topic back to the original topic",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Loyalty"
+                ],
+                "summary": "ReplayDeadLetters",
+                "parameters": [
+                    {
+                        "description": "ReplayDeadLetters request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReplayDeadLetters"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dead letters replayed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/loyalty/holds": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reserve points until the hold is captured, voided or expired. Reserved points stay in balance but are not available. Holds of own account require loyalty:withdraw permission, of any account - loyalty:withdraw:any.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Loyalty"
+                ],
+                "summary": "CreateHold",
+                "parameters": [
+                    {
+                        "description": "CreateHold request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateHold"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Hold created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.HoldResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/loyalty/holds/{id}/capture": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Withdraw reserved points, the rest of the hold is released",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Loyalty"
+                ],
+                "summary": "CaptureHold",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Hold ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Captured amount, the whole hold is captured if it's empty",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CaptureHold"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Hold captured",
+                        "schema": {
+                            "$ref": "#/definitions/dto.HoldResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/loyalty/holds/{id}/void": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Release points reserved by hold",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Loyalty"
+                ],
+                "summary": "VoidHold",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Hold ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Hold voided",
+                        "schema": {
+                            "$ref": "#/definitions/dto.HoldResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/loyalty/purchases": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deposit points earned by purchase by earning rules: points per currency unit, category multipliers, campaigns and caps per user. Purchase is accrued once. occurred_at (request time if empty) must be within earning_rules.occurredAtWindow of server time. Requires loyalty:deposit permission.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Loyalty"
+                ],
+                "summary": "AddPurchase",
+                "parameters": [
+                    {
+                        "description": "Purchase, amounts are in minor currency units",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.Purchase"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Purchase points accrued",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PurchaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/loyalty/transactions/{id}/reverse": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Undo deposit or withdraw fully or partially by the opposite operation linked to it. Sum of reversals can't exceed the transaction amount. Requires loyalty:reverse permission.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Loyalty"
+                ],
+                "summary": "ReverseTransaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reversed amount, the rest of the transaction is reversed if it's empty",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReverseTransaction"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Transaction reversed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReversalResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/loyalty/transfer": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Transfer points to other user. Users with loyalty:withdraw permission send points from their own account, with loyalty:withdraw:any - from any account. Points sent within a day are limited.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Loyalty"
+                ],
+                "summary": "Transfer",
+                "parameters": [
+                    {
+                        "description": "Transfer request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.Transfer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Points transferred",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TransferResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/loyalty/{uuid}": {
             "get": {
                 "security": [
@@ -106,7 +396,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get Loyalty",
+                "description": "Get Loyalty, held is a part of balance reserved by holds, expiring_soon is a part of balance expiring within configured window",
                 "consumes": [
                     "application/json"
                 ],
@@ -135,9 +425,246 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "/loyalty/{uuid}/transactions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get transaction history of loyalty account from newest to oldest. Owner of the account or users with loyalty:transactions:read:any permission are allowed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Loyalty"
+                ],
+                "summary": "GetTransactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 20 by default, 100 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transactions created at or after, RFC3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transactions created before, RFC3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transaction type: d - deposit, w - withdraw, e - expire",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimal transaction amount",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximal transaction amount",
+                        "name": "max_amount",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Get transactions successful",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TransactionsResponse"
+                        }
+                    }
+                }
+            }
         }
     },
     "definitions": {
+        "dto.CaptureHold": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount is captured amount, the whole hold is captured if it's empty.",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "dto.CreateHold": {
+            "type": "object",
+            "required": [
+                "amount",
+                "comment"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "description": "UUID is account of the hold, token owner account is used if it's empty.",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Hold": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "captured": {
+                    "type": "integer"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "transaction_id": {
+                    "description": "TransactionID is withdraw transaction of captured hold.",
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.HoldResponse": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "Balance and Held are account points after operation, available points\nare Balance - Held.",
+                    "type": "integer"
+                },
+                "held": {
+                    "type": "integer"
+                },
+                "hold": {
+                    "$ref": "#/definitions/dto.Hold"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Purchase": {
+            "type": "object",
+            "required": [
+                "id",
+                "items",
+                "uuid"
+            ],
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "items": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.PurchaseItem"
+                    }
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PurchaseItem": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount is in minor currency units.",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "category": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PurchaseResponse": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "integer"
+                },
+                "deposited": {
+                    "type": "integer"
+                },
+                "points": {
+                    "description": "Points are earned by purchase, Deposited are points left after caps.",
+                    "type": "integer"
+                },
+                "purchase_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "transaction_id": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReplayDeadLetters": {
+            "type": "object",
+            "required": [
+                "limit"
+            ],
+            "properties": {
+                "limit": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1
+                }
+            }
+        },
         "dto.Response": {
             "type": "object",
             "properties": {
@@ -147,6 +674,18 @@ const docTemplate = `{
                 "error": {
                     "type": "string"
                 },
+                "expiring_soon": {
+                    "description": "ExpiringSoon is a part of balance expiring within configured window.",
+                    "type": "integer"
+                },
+                "held": {
+                    "description": "Held is a part of balance reserved by holds, available points are\nBalance - Held.",
+                    "type": "integer"
+                },
+                "replayed": {
+                    "description": "Replayed is number of dead letters published back to original topic.",
+                    "type": "integer"
+                },
                 "status": {
                     "type": "string"
                 },
@@ -155,6 +694,141 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.ReversalResponse": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "Balance is account balance after reversal.",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "transaction": {
+                    "$ref": "#/definitions/dto.Transaction"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReverseTransaction": {
+            "type": "object",
+            "required": [
+                "comment"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Amount is reversed amount, the rest of the transaction is reversed if\nit's empty.",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "comment": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Transaction": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reversal_of": {
+                    "description": "ReversalOf is id of transaction undone by this reversal.",
+                    "type": "string"
+                },
+                "reversed": {
+                    "description": "Reversed is amount of the transaction undone by reversals.",
+                    "type": "integer"
+                },
+                "transfer_id": {
+                    "description": "TransferID is shared by withdraw and deposit of a transfer.",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TransactionsResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "description": "NextCursor is passed as cursor to get the next page, it's empty on the\nlast page.",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Transaction"
+                    }
+                }
+            }
+        },
+        "dto.Transfer": {
+            "type": "object",
+            "required": [
+                "amount",
+                "comment",
+                "to"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "from": {
+                    "description": "From is sender account, token owner account is used if it's empty.",
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TransferResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "balance": {
+                    "description": "Balance is sender balance after transfer.",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "transfer_id": {
+                    "type": "string"
+                }
+            }
+        },
         "dto.UserLoyalty": {
             "type": "object",
             "required": [
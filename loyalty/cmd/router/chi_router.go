@@ -11,6 +11,7 @@ import (
 
 	_ "github.com/AlexBlackNn/authloyalty/loyalty/cmd/docs"
 	"github.com/AlexBlackNn/authloyalty/loyalty/internal/config"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/jwt"
 	customMiddleware "github.com/AlexBlackNn/authloyalty/loyalty/internal/middleware"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -24,6 +25,7 @@ func NewChiRouter(
 	log *slog.Logger,
 	loyaltyhHandlerV1 v1.LoyaltyHandlers,
 	healthHandlerV1 v1.HealthHandlers,
+	tokenVerifier *jwt.Verifier,
 ) *chi.Mux {
 
 	router := chi.NewRouter()
@@ -41,7 +43,15 @@ func NewChiRouter(
 		r.Use(customMiddleware.GzipDecompressor(log))
 		r.Use(customMiddleware.GzipCompressor(log, gzip.BestCompression))
 		r.Get("/{uuid}", loyaltyhHandlerV1.GetLoyalty)
-		r.Post("/", loyaltyhHandlerV1.AddLoyalty)
+		r.With(customMiddleware.TokenVerifier(log, tokenVerifier)).Get("/{uuid}/transactions", loyaltyhHandlerV1.GetTransactions)
+		r.With(customMiddleware.TokenVerifier(log, tokenVerifier)).Post("/", loyaltyhHandlerV1.AddLoyalty)
+		r.With(customMiddleware.TokenVerifier(log, tokenVerifier)).Post("/transactions/{id}/reverse", loyaltyhHandlerV1.ReverseTransaction)
+		r.With(customMiddleware.TokenVerifier(log, tokenVerifier)).Post("/transfer", loyaltyhHandlerV1.Transfer)
+		r.With(customMiddleware.TokenVerifier(log, tokenVerifier)).Post("/purchases", loyaltyhHandlerV1.AddPurchase)
+		r.With(customMiddleware.TokenVerifier(log, tokenVerifier)).Post("/dlq/replay", loyaltyhHandlerV1.ReplayDeadLetters)
+		r.With(customMiddleware.TokenVerifier(log, tokenVerifier)).Post("/holds", loyaltyhHandlerV1.CreateHold)
+		r.With(customMiddleware.TokenVerifier(log, tokenVerifier)).Post("/holds/{id}/capture", loyaltyhHandlerV1.CaptureHold)
+		r.With(customMiddleware.TokenVerifier(log, tokenVerifier)).Post("/holds/{id}/void", loyaltyhHandlerV1.VoidHold)
 		r.Get("/ready", healthHandlerV1.ReadinessProbe)
 		r.Get("/healthz", healthHandlerV1.LivenessProbe)
 
@@ -16,6 +16,21 @@ type StoragePatroniConfig struct {
 type KafkaConfig struct {
 	KafkaURL          string `yaml:"kafkaUrl" env-required:"true"`
 	SchemaRegistryURL string `yaml:"schemaRegistryURL" env-required:"true"`
+	GroupID           string `yaml:"groupId" env-default:"1"`
+	Topic             string `yaml:"topic" env-default:"registration"`
+	// UserEventsTopic is where sso publishes user lifecycle events.
+	UserEventsTopic string `yaml:"userEventsTopic" env-default:"user_events"`
+	PollTimeoutMs   int    `yaml:"pollTimeoutMs" env-default:"100"`
+	// RetryDelay is a delay before a message that failed to be processed is
+	// consumed again. Offset is committed only after message is processed.
+	RetryDelay time.Duration `yaml:"retryDelay" env-default:"1s"`
+	// MaxAttempts is how many times a message is processed before it's moved
+	// to DeadLetterTopic.
+	MaxAttempts     int    `yaml:"maxAttempts" env-default:"5"`
+	DeadLetterTopic string `yaml:"deadLetterTopic" env-default:"registration.dlq"`
+	// ReplayTimeout limits how long dead letters are replayed by a single
+	// request, it must be less than server write timeout.
+	ReplayTimeout time.Duration `yaml:"replayTimeout" env-default:"8s"`
 }
 
 type ServerTimeoutConfig struct {
@@ -35,6 +50,80 @@ type ServerGRPC struct {
 	GRPCAddress string `yaml:"grpcAddress" env-required:"true"`
 }
 
+type TokenVerificationConfig struct {
+	// JWKSURL is sso endpoint providing public keys to verify tokens.
+	JWKSURL string `yaml:"jwksUrl" env-required:"true"`
+	// JWKSRefreshInterval is how often public keys are refetched.
+	JWKSRefreshInterval time.Duration `yaml:"jwksRefreshInterval" env-default:"5m"`
+	// RevocationCacheTtl is how long token revocation check result is cached.
+	RevocationCacheTtl time.Duration `yaml:"revocationCacheTtl" env-default:"30s"`
+}
+
+// PointsExpirationConfig sets expiration of deposited points.
+type PointsExpirationConfig struct {
+	// Ttl is how long deposited points are valid, zero means points never
+	// expire.
+	Ttl time.Duration `yaml:"ttl" env-default:"8760h"`
+	// ExpiringSoonWindow is how far ahead points are reported as expiring
+	// soon.
+	ExpiringSoonWindow time.Duration `yaml:"expiringSoonWindow" env-default:"720h"`
+	// Interval is how often expired points are written off.
+	Interval  time.Duration `yaml:"interval" env-default:"1h"`
+	BatchSize int           `yaml:"batchSize" env-default:"100"`
+}
+
+// HoldsConfig sets points holds.
+type HoldsConfig struct {
+	// Ttl is how long hold reserves points if it's not captured or voided.
+	Ttl time.Duration `yaml:"ttl" env-default:"15m"`
+	// Interval is how often expired holds are released.
+	Interval  time.Duration `yaml:"interval" env-default:"1m"`
+	BatchSize int           `yaml:"batchSize" env-default:"100"`
+}
+
+// TransfersConfig sets points transfers between users.
+type TransfersConfig struct {
+	// DailyLimit is how many points a user can send within a day, zero
+	// means transfers are not limited.
+	DailyLimit int `yaml:"dailyLimit" env-default:"10000"`
+}
+
+// EarningRulesConfig sets points earned by purchases. Purchase amounts are
+// in minor currency units, points of a purchase are rounded down.
+type EarningRulesConfig struct {
+	// PointsPerUnit is how many points a currency unit of purchase earns.
+	PointsPerUnit float64 `yaml:"pointsPerUnit" env-default:"1"`
+	// MinorUnits is number of minor units in a currency unit.
+	MinorUnits int64 `yaml:"minorUnits" env-default:"100"`
+	// CategoryMultipliers change points of purchase items by category,
+	// items of other categories earn PointsPerUnit.
+	CategoryMultipliers map[string]float64 `yaml:"categoryMultipliers"`
+	// Campaigns multiply points of purchases made while they last.
+	Campaigns []CampaignConfig `yaml:"campaigns"`
+	// Caps limit points a user earns by purchases within a period.
+	Caps []EarningCapConfig `yaml:"caps"`
+	// OccurredAtWindow is how far purchase time sent by client may differ from
+	// server time, so client can't pick time of a campaign it missed.
+	OccurredAtWindow time.Duration `yaml:"occurredAtWindow" env-default:"15m"`
+}
+
+// CampaignConfig multiplies points of purchases made from From until To.
+// Multipliers of overlapping campaigns are not combined, the largest is used.
+type CampaignConfig struct {
+	Name       string    `yaml:"name"`
+	From       time.Time `yaml:"from"`
+	To         time.Time `yaml:"to"`
+	Multiplier float64   `yaml:"multiplier"`
+	// Categories are categories of the campaign, empty means all categories.
+	Categories []string `yaml:"categories"`
+}
+
+// EarningCapConfig limits points earned by purchases within Period.
+type EarningCapConfig struct {
+	Period time.Duration `yaml:"period"`
+	Points int           `yaml:"points"`
+}
+
 type Config struct {
 	// without this param will be used "local" as param value
 	Env             string        `yaml:"env" env-default:"local"`
@@ -52,6 +141,14 @@ type Config struct {
 	RateLimit              int                          `yaml:"rate_limit" `
 	Address                string                       `yaml:"address"`
 	SSOAddress             string                       `yaml:"sso_address"`
+	TokenVerification      TokenVerificationConfig      `yaml:"token_verification"`
+	// RegistrationBonus is granted for registration events of producers not
+	// sending bonus amount.
+	RegistrationBonus int                    `yaml:"registration_bonus" env-default:"100"`
+	PointsExpiration  PointsExpirationConfig `yaml:"points_expiration"`
+	Holds             HoldsConfig            `yaml:"holds"`
+	Transfers         TransfersConfig        `yaml:"transfers"`
+	EarningRules      EarningRulesConfig     `yaml:"earning_rules"`
 }
 
 func New() *Config {
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Account statuses, operations are allowed with active accounts only.
+const (
+	AccountActive = "active"
+	AccountFrozen = "frozen"
+	AccountClosed = "closed"
+)
+
+// AccountStatus is a status change of loyalty account caused by user event.
+type AccountStatus struct {
+	UUID   string
+	Status string
+	// FrozenUntil is when frozen account becomes active, nil means it's
+	// frozen until unfrozen.
+	FrozenUntil *time.Time
+	// EventID identifies broker event the change comes from.
+	EventID string
+}
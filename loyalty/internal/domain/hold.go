@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// Hold statuses, only active hold can be captured or voided.
+const (
+	HoldActive   = "active"
+	HoldCaptured = "captured"
+	HoldVoided   = "voided"
+	HoldExpired  = "expired"
+)
+
+// Hold reserves points of account, reserved points are not available for
+// other operations but stay in balance until hold is captured.
+type Hold struct {
+	ID      string
+	UUID    string
+	Amount  int
+	Comment string
+	Status  string
+	// Captured is amount withdrawn by capture, the rest of hold is released.
+	Captured      int
+	TransactionID string
+	CreatedAt     time.Time
+	ExpiresAt     time.Time
+	// Balance and Held are account balance and held points after operation
+	// with the hold.
+	Balance int
+	Held    int
+}
+
+// HoldAction captures or voids hold ID.
+type HoldAction struct {
+	ID string
+	// UUID restricts action to holds of the account, empty UUID allows holds
+	// of any account.
+	UUID string
+	// Amount is captured amount, zero captures the whole hold.
+	Amount int
+}
@@ -1,8 +1,34 @@
 package domain
 
+import "time"
+
 type UserLoyalty struct {
 	UUID      string
 	Operation string
 	Comment   string
 	Balance   int
+	// Held is a part of balance reserved by active holds, it's not available
+	// for withdraw.
+	Held int
+	// EventID identifies broker event the operation comes from, operation with
+	// already processed EventID is not applied again.
+	EventID string
+	// IdempotencyKey identifies request of RequestedBy user applying the
+	// operation, operation with already used key is not applied again.
+	IdempotencyKey string
+	RequestedBy    string
+	// RequestHash is hash of the operation, the same key with other operation
+	// is rejected.
+	RequestHash string
+	// ResponseStatus is http status of the response saved with IdempotencyKey,
+	// repeated request is answered with the saved status and balance or
+	// ResponseError.
+	ResponseStatus int
+	// ResponseError is error message of the rejected operation saved with
+	// IdempotencyKey, empty if the operation was applied.
+	ResponseError string
+	// ExpiresAt is when deposited points expire, nil means they never expire.
+	ExpiresAt *time.Time
+	// ExpiringSoon is a part of balance expiring within configured window.
+	ExpiringSoon int
 }
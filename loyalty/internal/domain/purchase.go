@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// PurchaseItem is a part of purchase, Amount is in minor currency units.
+type PurchaseItem struct {
+	Category string
+	Amount   int64
+}
+
+// Purchase earns points by earning rules.
+type Purchase struct {
+	ID         string
+	UUID       string
+	Items      []PurchaseItem
+	OccurredAt time.Time
+}
+
+// EarningCap limits points earned by purchases within Period.
+type EarningCap struct {
+	Period time.Duration
+	Points int
+}
+
+// Accrual deposits Points earned by purchase PurchaseID, deposited points
+// are reduced to fit Caps.
+type Accrual struct {
+	PurchaseID string
+	UUID       string
+	Points     int
+	Caps       []EarningCap
+	Comment    string
+	// EventID identifies the purchase, purchase is not accrued twice.
+	EventID string
+	// ExpiresAt is expiry of deposited points, nil means they never expire.
+	ExpiresAt *time.Time
+	// Deposited is points deposited after caps, TransactionID is its
+	// deposit, Balance is account balance after it.
+	Deposited     int
+	TransactionID string
+	Balance       int
+}
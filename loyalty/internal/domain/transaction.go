@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// Transaction is a loyalty account operation.
+type Transaction struct {
+	ID        string
+	Amount    int
+	Type      string
+	Comment   string
+	CreatedAt time.Time
+	// ReversalOf is transaction undone by this reversal.
+	ReversalOf string
+	// Reversed is amount of the transaction undone by reversals.
+	Reversed int
+	// TransferID is shared by withdraw and deposit of a transfer.
+	TransferID string
+}
+
+// TransactionCursor points to the last transaction of a page, the next page
+// starts after it. Transactions are ordered from newest to oldest.
+type TransactionCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// TransactionFilter selects account transactions, zero fields don't filter.
+type TransactionFilter struct {
+	UUID      string
+	From      *time.Time
+	To        *time.Time
+	Type      string
+	MinAmount int
+	MaxAmount int
+	After     *TransactionCursor
+	Limit     int
+}
+
+// TransactionPage is a page of account transactions, Next is nil on the last
+// page.
+type TransactionPage struct {
+	Transactions []Transaction
+	Next         *TransactionCursor
+}
+
+// Reversal undoes Amount of deposit or withdraw TransactionID by the
+// opposite operation, zero Amount undoes the rest of the transaction.
+type Reversal struct {
+	TransactionID string
+	Amount        int
+	Comment       string
+	// ExpiresAt is expiry of points returned by reversal of withdraw, nil
+	// means they never expire.
+	ExpiresAt *time.Time
+	// ID, UUID, Type and CreatedAt describe saved reversal transaction,
+	// Balance is account balance after it.
+	ID        string
+	UUID      string
+	Type      string
+	CreatedAt time.Time
+	Balance   int
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// Transfer moves Amount points from account From to account To.
+type Transfer struct {
+	From    string
+	To      string
+	Amount  int
+	Comment string
+	// DailyLimit is how many points From can send within a day including
+	// the transfer, zero means transfers are not limited.
+	DailyLimit int
+	// ExpiresAt is expiry of points received by To, nil means they never
+	// expire.
+	ExpiresAt *time.Time
+	// ID is shared by withdraw and deposit transactions of saved transfer,
+	// Balance is From account balance after it.
+	ID        string
+	CreatedAt time.Time
+	Balance   int
+}
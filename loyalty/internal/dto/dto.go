@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	jsoniter "github.com/json-iterator/go"
@@ -18,6 +19,61 @@ type UserLoyalty struct {
 	Balance   int    `json:"balance" validate:"required"`
 }
 
+// TransactionsQuery is query of transaction history request.
+type TransactionsQuery struct {
+	Cursor    string
+	Limit     int `validate:"min=1,max=100"`
+	From      *time.Time
+	To        *time.Time
+	Type      string `validate:"omitempty,oneof=d w e"`
+	MinAmount int    `validate:"min=0"`
+	MaxAmount int    `validate:"omitempty,min=1,gtefield=MinAmount"`
+}
+
+type CreateHold struct {
+	// UUID is account of the hold, token owner account is used if it's empty.
+	UUID    string `json:"uuid" validate:"omitempty,uuid"`
+	Amount  int    `json:"amount" validate:"required,min=1"`
+	Comment string `json:"comment" validate:"required"`
+}
+
+type CaptureHold struct {
+	// Amount is captured amount, the whole hold is captured if it's empty.
+	Amount int `json:"amount" validate:"min=0"`
+}
+
+type ReverseTransaction struct {
+	// Amount is reversed amount, the rest of the transaction is reversed if
+	// it's empty.
+	Amount  int    `json:"amount" validate:"min=0"`
+	Comment string `json:"comment" validate:"required"`
+}
+
+type Transfer struct {
+	// From is sender account, token owner account is used if it's empty.
+	From    string `json:"from" validate:"omitempty,uuid"`
+	To      string `json:"to" validate:"required,uuid"`
+	Amount  int    `json:"amount" validate:"required,min=1"`
+	Comment string `json:"comment" validate:"required"`
+}
+
+type PurchaseItem struct {
+	Category string `json:"category"`
+	// Amount is in minor currency units.
+	Amount int64 `json:"amount" validate:"min=1"`
+}
+
+type Purchase struct {
+	ID         string         `json:"id" validate:"required,max=255"`
+	UUID       string         `json:"uuid" validate:"required,uuid"`
+	Items      []PurchaseItem `json:"items" validate:"required,min=1,dive"`
+	OccurredAt *time.Time     `json:"occurred_at"`
+}
+
+type ReplayDeadLetters struct {
+	Limit int `json:"limit" validate:"required,min=1,max=1000"`
+}
+
 // Output
 
 type Response struct {
@@ -25,6 +81,86 @@ type Response struct {
 	Error   string `json:"error,omitempty"`
 	UUID    string `json:"uuid,omitempty"`
 	Balance int    `json:"balance,omitempty"`
+	// Held is a part of balance reserved by holds, available points are
+	// Balance - Held.
+	Held int `json:"held,omitempty"`
+	// ExpiringSoon is a part of balance expiring within configured window.
+	ExpiringSoon int `json:"expiring_soon,omitempty"`
+	// Replayed is number of dead letters published back to original topic.
+	Replayed int `json:"replayed,omitempty"`
+}
+
+type Transaction struct {
+	ID        string    `json:"id"`
+	Amount    int       `json:"amount"`
+	Type      string    `json:"type"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+	// ReversalOf is id of transaction undone by this reversal.
+	ReversalOf string `json:"reversal_of,omitempty"`
+	// Reversed is amount of the transaction undone by reversals.
+	Reversed int `json:"reversed,omitempty"`
+	// TransferID is shared by withdraw and deposit of a transfer.
+	TransferID string `json:"transfer_id,omitempty"`
+}
+
+type ReversalResponse struct {
+	Status      string      `json:"status"`
+	Transaction Transaction `json:"transaction"`
+	UUID        string      `json:"uuid"`
+	// Balance is account balance after reversal.
+	Balance int `json:"balance"`
+}
+
+type TransactionsResponse struct {
+	Status       string        `json:"status"`
+	Transactions []Transaction `json:"transactions"`
+	// NextCursor is passed as cursor to get the next page, it's empty on the
+	// last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type Hold struct {
+	ID        string    `json:"id"`
+	UUID      string    `json:"uuid"`
+	Amount    int       `json:"amount"`
+	Captured  int       `json:"captured,omitempty"`
+	Status    string    `json:"status"`
+	Comment   string    `json:"comment"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// TransactionID is withdraw transaction of captured hold.
+	TransactionID string `json:"transaction_id,omitempty"`
+}
+
+type HoldResponse struct {
+	Status string `json:"status"`
+	Hold   Hold   `json:"hold"`
+	// Balance and Held are account points after operation, available points
+	// are Balance - Held.
+	Balance int `json:"balance"`
+	Held    int `json:"held"`
+}
+
+type TransferResponse struct {
+	Status     string    `json:"status"`
+	TransferID string    `json:"transfer_id"`
+	From       string    `json:"from"`
+	To         string    `json:"to"`
+	Amount     int       `json:"amount"`
+	CreatedAt  time.Time `json:"created_at"`
+	// Balance is sender balance after transfer.
+	Balance int `json:"balance"`
+}
+
+type PurchaseResponse struct {
+	Status     string `json:"status"`
+	PurchaseID string `json:"purchase_id"`
+	UUID       string `json:"uuid"`
+	// Points are earned by purchase, Deposited are points left after caps.
+	Points        int    `json:"points"`
+	Deposited     int    `json:"deposited"`
+	TransactionID string `json:"transaction_id,omitempty"`
+	Balance       int    `json:"balance"`
 }
 
 const StatusError = "Error"
@@ -40,6 +176,20 @@ func sendJSON(w http.ResponseWriter, statusCode int, data []byte) {
 
 // Errors.
 
+// ResponseError writes error message with status code, it replays response
+// saved with idempotency key.
+func ResponseError(
+	w http.ResponseWriter,
+	code int,
+	message string,
+) {
+	dataMarshal, _ := json.Marshal(Response{
+		Status: StatusError,
+		Error:  message,
+	})
+	sendJSON(w, code, dataMarshal)
+}
+
 func ResponseErrorNotFound(
 	w http.ResponseWriter,
 	message string,
@@ -81,7 +231,40 @@ func ResponseErrorStatusConflict(
 		Status: StatusError,
 		Error:  message,
 	})
-	sendJSON(w, http.StatusMethodNotAllowed, dataMarshal)
+	sendJSON(w, http.StatusConflict, dataMarshal)
+}
+
+func ResponseErrorUnauthorized(
+	w http.ResponseWriter,
+	message string,
+) {
+	dataMarshal, _ := json.Marshal(Response{
+		Status: StatusError,
+		Error:  message,
+	})
+	sendJSON(w, http.StatusUnauthorized, dataMarshal)
+}
+
+func ResponseErrorForbidden(
+	w http.ResponseWriter,
+	message string,
+) {
+	dataMarshal, _ := json.Marshal(Response{
+		Status: StatusError,
+		Error:  message,
+	})
+	sendJSON(w, http.StatusForbidden, dataMarshal)
+}
+
+func ResponseErrorServiceUnavailable(
+	w http.ResponseWriter,
+	message string,
+) {
+	dataMarshal, _ := json.Marshal(Response{
+		Status: StatusError,
+		Error:  message,
+	})
+	sendJSON(w, http.StatusServiceUnavailable, dataMarshal)
 }
 
 func ResponseErrorBadRequest(
@@ -106,8 +289,11 @@ func ResponseOK(w http.ResponseWriter) {
 	sendJSON(w, http.StatusOK, dataMarshal)
 }
 
-func ResponseOKLoyalty(
+// ResponseLoyalty writes balance with status code, the code differs from 200
+// if response saved with idempotency key is replayed.
+func ResponseLoyalty(
 	w http.ResponseWriter,
+	code int,
 	uuid string,
 	value int,
 ) {
@@ -118,6 +304,99 @@ func ResponseOKLoyalty(
 			Balance: value,
 		},
 	)
+	sendJSON(w, code, dataMarshal)
+}
+
+func ResponseOKAccount(
+	w http.ResponseWriter,
+	uuid string,
+	value int,
+	held int,
+	expiringSoon int,
+) {
+	dataMarshal, _ := json.Marshal(
+		Response{
+			Status:       StatusSuccess,
+			UUID:         uuid,
+			Balance:      value,
+			Held:         held,
+			ExpiringSoon: expiringSoon,
+		},
+	)
+	sendJSON(w, http.StatusOK, dataMarshal)
+}
+
+func ResponseOKHold(
+	w http.ResponseWriter,
+	hold Hold,
+	balance int,
+	held int,
+) {
+	dataMarshal, _ := json.Marshal(
+		HoldResponse{
+			Status:  StatusSuccess,
+			Hold:    hold,
+			Balance: balance,
+			Held:    held,
+		},
+	)
+	sendJSON(w, http.StatusOK, dataMarshal)
+}
+
+func ResponseOKTransactions(
+	w http.ResponseWriter,
+	transactions []Transaction,
+	nextCursor string,
+) {
+	if transactions == nil {
+		transactions = []Transaction{}
+	}
+	dataMarshal, _ := json.Marshal(
+		TransactionsResponse{
+			Status:       StatusSuccess,
+			Transactions: transactions,
+			NextCursor:   nextCursor,
+		},
+	)
+	sendJSON(w, http.StatusOK, dataMarshal)
+}
+
+func ResponseOKReversal(
+	w http.ResponseWriter,
+	transaction Transaction,
+	uuid string,
+	balance int,
+) {
+	dataMarshal, _ := json.Marshal(
+		ReversalResponse{
+			Status:      StatusSuccess,
+			Transaction: transaction,
+			UUID:        uuid,
+			Balance:     balance,
+		},
+	)
+	sendJSON(w, http.StatusOK, dataMarshal)
+}
+
+func ResponseOKTransfer(w http.ResponseWriter, transfer TransferResponse) {
+	transfer.Status = StatusSuccess
+	dataMarshal, _ := json.Marshal(transfer)
+	sendJSON(w, http.StatusOK, dataMarshal)
+}
+
+func ResponseOKPurchase(w http.ResponseWriter, purchase PurchaseResponse) {
+	purchase.Status = StatusSuccess
+	dataMarshal, _ := json.Marshal(purchase)
+	sendJSON(w, http.StatusOK, dataMarshal)
+}
+
+func ResponseOKReplayed(w http.ResponseWriter, replayed int) {
+	dataMarshal, _ := json.Marshal(
+		Response{
+			Status:   StatusSuccess,
+			Replayed: replayed,
+		},
+	)
 	sendJSON(w, http.StatusOK, dataMarshal)
 }
 
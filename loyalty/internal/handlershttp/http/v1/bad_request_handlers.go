@@ -4,6 +4,8 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
 	"github.com/AlexBlackNn/authloyalty/loyalty/internal/dto"
@@ -43,6 +45,204 @@ func handleAddLoyaltyBadRequest(w http.ResponseWriter, r *http.Request, reqData
 	return reqData, nil
 }
 
+func handleReplayDeadLettersBadRequest(w http.ResponseWriter, r *http.Request, reqData *dto.ReplayDeadLetters) (*dto.ReplayDeadLetters, error) {
+	if r.Method != http.MethodPost {
+		dto.ResponseErrorNowAllowed(w, "only POST method allowed")
+		return nil, errors.New("method not allowed")
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		dto.ResponseErrorBadRequest(w, "failed to read body")
+		return nil, errors.New("failed to read body")
+	}
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	err = json.Unmarshal(body, reqData)
+	if err != nil {
+		dto.ResponseErrorBadRequest(w, "failed to decode body")
+		return nil, errors.New("failed to decode request")
+	}
+
+	if err = validator.New().Struct(reqData); err != nil {
+		var validateErr validator.ValidationErrors
+		if errors.As(err, &validateErr) {
+			dto.ResponseErrorBadRequest(w, dto.ValidationError(validateErr))
+			return nil, errors.New("validation error")
+		}
+		dto.ResponseErrorBadRequest(w, "bad request")
+		return nil, errors.New("bad request")
+	}
+	return reqData, nil
+}
+
+func handleCreateHoldBadRequest(w http.ResponseWriter, r *http.Request, reqData *dto.CreateHold) (*dto.CreateHold, error) {
+	if r.Method != http.MethodPost {
+		dto.ResponseErrorNowAllowed(w, "only POST method allowed")
+		return nil, errors.New("method not allowed")
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		dto.ResponseErrorBadRequest(w, "failed to read body")
+		return nil, errors.New("failed to read body")
+	}
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	err = json.Unmarshal(body, reqData)
+	if err != nil {
+		dto.ResponseErrorBadRequest(w, "failed to decode body")
+		return nil, errors.New("failed to decode request")
+	}
+
+	if err = validator.New().Struct(reqData); err != nil {
+		var validateErr validator.ValidationErrors
+		if errors.As(err, &validateErr) {
+			dto.ResponseErrorBadRequest(w, dto.ValidationError(validateErr))
+			return nil, errors.New("validation error")
+		}
+		dto.ResponseErrorBadRequest(w, "bad request")
+		return nil, errors.New("bad request")
+	}
+	return reqData, nil
+}
+
+func handleTransferBadRequest(w http.ResponseWriter, r *http.Request, reqData *dto.Transfer) (*dto.Transfer, error) {
+	if r.Method != http.MethodPost {
+		dto.ResponseErrorNowAllowed(w, "only POST method allowed")
+		return nil, errors.New("method not allowed")
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		dto.ResponseErrorBadRequest(w, "failed to read body")
+		return nil, errors.New("failed to read body")
+	}
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	err = json.Unmarshal(body, reqData)
+	if err != nil {
+		dto.ResponseErrorBadRequest(w, "failed to decode body")
+		return nil, errors.New("failed to decode request")
+	}
+
+	if err = validator.New().Struct(reqData); err != nil {
+		var validateErr validator.ValidationErrors
+		if errors.As(err, &validateErr) {
+			dto.ResponseErrorBadRequest(w, dto.ValidationError(validateErr))
+			return nil, errors.New("validation error")
+		}
+		dto.ResponseErrorBadRequest(w, "bad request")
+		return nil, errors.New("bad request")
+	}
+	return reqData, nil
+}
+
+func handlePurchaseBadRequest(w http.ResponseWriter, r *http.Request, reqData *dto.Purchase) (*dto.Purchase, error) {
+	if r.Method != http.MethodPost {
+		dto.ResponseErrorNowAllowed(w, "only POST method allowed")
+		return nil, errors.New("method not allowed")
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		dto.ResponseErrorBadRequest(w, "failed to read body")
+		return nil, errors.New("failed to read body")
+	}
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	err = json.Unmarshal(body, reqData)
+	if err != nil {
+		dto.ResponseErrorBadRequest(w, "failed to decode body")
+		return nil, errors.New("failed to decode request")
+	}
+
+	if err = validator.New().Struct(reqData); err != nil {
+		var validateErr validator.ValidationErrors
+		if errors.As(err, &validateErr) {
+			dto.ResponseErrorBadRequest(w, dto.ValidationError(validateErr))
+			return nil, errors.New("validation error")
+		}
+		dto.ResponseErrorBadRequest(w, "bad request")
+		return nil, errors.New("bad request")
+	}
+	return reqData, nil
+}
+
+// handleHoldActionBadRequest parses hold id from path and optional capture
+// body, empty body captures the whole hold.
+func handleHoldActionBadRequest(w http.ResponseWriter, r *http.Request) (*domain.HoldAction, error) {
+	if r.Method != http.MethodPost {
+		dto.ResponseErrorNowAllowed(w, "only POST method allowed")
+		return nil, errors.New("method not allowed")
+	}
+	holdID := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(holdID); err != nil {
+		dto.ResponseErrorBadRequest(w, "invalid hold id")
+		return nil, errors.New("invalid hold id")
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		dto.ResponseErrorBadRequest(w, "failed to read body")
+		return nil, errors.New("failed to read body")
+	}
+	reqData := &dto.CaptureHold{}
+	if len(body) > 0 {
+		var json = jsoniter.ConfigCompatibleWithStandardLibrary
+		if err = json.Unmarshal(body, reqData); err != nil {
+			dto.ResponseErrorBadRequest(w, "failed to decode body")
+			return nil, errors.New("failed to decode request")
+		}
+	}
+	if err = validator.New().Struct(reqData); err != nil {
+		var validateErr validator.ValidationErrors
+		if errors.As(err, &validateErr) {
+			dto.ResponseErrorBadRequest(w, dto.ValidationError(validateErr))
+			return nil, errors.New("validation error")
+		}
+		dto.ResponseErrorBadRequest(w, "bad request")
+		return nil, errors.New("bad request")
+	}
+	return &domain.HoldAction{ID: holdID, Amount: reqData.Amount}, nil
+}
+
+func handleReverseTransactionBadRequest(w http.ResponseWriter, r *http.Request) (*domain.Reversal, error) {
+	if r.Method != http.MethodPost {
+		dto.ResponseErrorNowAllowed(w, "only POST method allowed")
+		return nil, errors.New("method not allowed")
+	}
+	transactionID := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(transactionID); err != nil {
+		dto.ResponseErrorBadRequest(w, "invalid transaction id")
+		return nil, errors.New("invalid transaction id")
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		dto.ResponseErrorBadRequest(w, "failed to read body")
+		return nil, errors.New("failed to read body")
+	}
+	reqData := &dto.ReverseTransaction{}
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	err = json.Unmarshal(body, reqData)
+	if err != nil {
+		dto.ResponseErrorBadRequest(w, "failed to decode body")
+		return nil, errors.New("failed to decode request")
+	}
+
+	if err = validator.New().Struct(reqData); err != nil {
+		var validateErr validator.ValidationErrors
+		if errors.As(err, &validateErr) {
+			dto.ResponseErrorBadRequest(w, dto.ValidationError(validateErr))
+			return nil, errors.New("validation error")
+		}
+		dto.ResponseErrorBadRequest(w, "bad request")
+		return nil, errors.New("bad request")
+	}
+	return &domain.Reversal{
+		TransactionID: transactionID,
+		Amount:        reqData.Amount,
+		Comment:       reqData.Comment,
+	}, nil
+}
+
 func handleGetLoyaltyBadRequest(w http.ResponseWriter, r *http.Request) (*domain.UserLoyalty, error) {
 	if r.Method != http.MethodGet {
 		dto.ResponseErrorNowAllowed(w, "only Get method allowed")
@@ -56,3 +256,84 @@ func handleGetLoyaltyBadRequest(w http.ResponseWriter, r *http.Request) (*domain
 	}
 	return &domain.UserLoyalty{UUID: currentUUID}, nil
 }
+
+// defaultTransactionsLimit is page size of transaction history if limit is not set.
+const defaultTransactionsLimit = 20
+
+func handleGetTransactionsBadRequest(w http.ResponseWriter, r *http.Request) (*domain.TransactionFilter, error) {
+	if r.Method != http.MethodGet {
+		dto.ResponseErrorNowAllowed(w, "only Get method allowed")
+		return nil, errors.New("method not allowed")
+	}
+	currentUUID := chi.URLParam(r, "uuid")
+	if _, err := uuid.Parse(currentUUID); err != nil {
+		dto.ResponseErrorBadRequest(w, "invalid uuid")
+		return nil, errors.New("invalid uuid")
+	}
+
+	query := r.URL.Query()
+	reqData := &dto.TransactionsQuery{
+		Cursor: query.Get("cursor"),
+		Limit:  defaultTransactionsLimit,
+		Type:   query.Get("type"),
+	}
+	var err error
+	for param, value := range map[string]*int{
+		"limit":      &reqData.Limit,
+		"min_amount": &reqData.MinAmount,
+		"max_amount": &reqData.MaxAmount,
+	} {
+		if query.Get(param) == "" {
+			continue
+		}
+		if *value, err = strconv.Atoi(query.Get(param)); err != nil {
+			dto.ResponseErrorBadRequest(w, param+" must be integer")
+			return nil, errors.New("bad request")
+		}
+	}
+	for param, value := range map[string]**time.Time{
+		"from": &reqData.From,
+		"to":   &reqData.To,
+	} {
+		if query.Get(param) == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, query.Get(param))
+		if err != nil {
+			dto.ResponseErrorBadRequest(w, param+" must be RFC3339 time")
+			return nil, errors.New("bad request")
+		}
+		*value = &t
+	}
+
+	if err = validator.New().Struct(reqData); err != nil {
+		var validateErr validator.ValidationErrors
+		if errors.As(err, &validateErr) {
+			dto.ResponseErrorBadRequest(w, dto.ValidationError(validateErr))
+			return nil, errors.New("validation error")
+		}
+		dto.ResponseErrorBadRequest(w, "bad request")
+		return nil, errors.New("bad request")
+	}
+	if reqData.From != nil && reqData.To != nil && !reqData.From.Before(*reqData.To) {
+		dto.ResponseErrorBadRequest(w, "from must be before to")
+		return nil, errors.New("bad request")
+	}
+
+	filter := &domain.TransactionFilter{
+		UUID:      currentUUID,
+		From:      reqData.From,
+		To:        reqData.To,
+		Type:      reqData.Type,
+		MinAmount: reqData.MinAmount,
+		MaxAmount: reqData.MaxAmount,
+		Limit:     reqData.Limit,
+	}
+	if reqData.Cursor != "" {
+		if filter.After, err = decodeTransactionCursor(reqData.Cursor); err != nil {
+			dto.ResponseErrorBadRequest(w, "invalid cursor")
+			return nil, errors.New("invalid cursor")
+		}
+	}
+	return filter, nil
+}
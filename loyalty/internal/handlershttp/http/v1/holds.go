@@ -0,0 +1,175 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/dto"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/jwt"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/services/loyaltyservice"
+)
+
+// @Summary CreateHold
+// @Description Reserve points until the hold is captured, voided or expired. Reserved points stay in balance but are not available. Holds of own account require loyalty:withdraw permission, of any account - loyalty:withdraw:any.
+// @Tags Loyalty
+// @Accept json
+// @Produce json
+// @Param body body dto.CreateHold true "CreateHold request"
+// @Success 200 {object} dto.HoldResponse "Hold created"
+// @Router /loyalty/holds [post]
+// @Security BearerAuth
+func (l *LoyaltyHandlers) CreateHold(w http.ResponseWriter, r *http.Request) {
+	reqData, err := handleCreateHoldBadRequest(w, r, &dto.CreateHold{})
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := ctxWithTimeoutCause(r, l.cfg, "create hold")
+	defer cancel()
+
+	// token is verified by middleware
+	claims, err := jwt.ClaimsFromContext(ctx)
+	if err != nil {
+		dto.ResponseErrorUnauthorized(w, "jwt token required")
+		return
+	}
+	hold := &domain.Hold{
+		UUID:    claims.UID,
+		Amount:  reqData.Amount,
+		Comment: reqData.Comment,
+	}
+	switch {
+	case claims.HasPermission(jwt.PermissionLoyaltyWithdrawAny):
+		if reqData.UUID != "" {
+			hold.UUID = reqData.UUID
+		}
+	case claims.HasPermission(jwt.PermissionLoyaltyWithdraw):
+	default:
+		dto.ResponseErrorForbidden(w, "permission loyalty:withdraw required")
+		return
+	}
+
+	hold, err = l.loyalty.CreateHold(ctx, hold)
+	if err != nil {
+		responseHoldError(w, err)
+		return
+	}
+	dto.ResponseOKHold(w, holdResponse(hold), hold.Balance, hold.Held)
+}
+
+// @Summary CaptureHold
+// @Description Withdraw reserved points, the rest of the hold is released
+// @Tags Loyalty
+// @Accept json
+// @Produce json
+// @Param id path string true "Hold ID"
+// @Param body body dto.CaptureHold false "Captured amount, the whole hold is captured if it's empty"
+// @Success 200 {object} dto.HoldResponse "Hold captured"
+// @Router /loyalty/holds/{id}/capture [post]
+// @Security BearerAuth
+func (l *LoyaltyHandlers) CaptureHold(w http.ResponseWriter, r *http.Request) {
+	action, err := handleHoldActionBadRequest(w, r)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := ctxWithTimeoutCause(r, l.cfg, "capture hold")
+	defer cancel()
+
+	if !authorizeHoldAction(w, r, action) {
+		return
+	}
+	hold, err := l.loyalty.CaptureHold(ctx, action)
+	if err != nil {
+		responseHoldError(w, err)
+		return
+	}
+	dto.ResponseOKHold(w, holdResponse(hold), hold.Balance, hold.Held)
+}
+
+// @Summary VoidHold
+// @Description Release points reserved by hold
+// @Tags Loyalty
+// @Accept json
+// @Produce json
+// @Param id path string true "Hold ID"
+// @Success 200 {object} dto.HoldResponse "Hold voided"
+// @Router /loyalty/holds/{id}/void [post]
+// @Security BearerAuth
+func (l *LoyaltyHandlers) VoidHold(w http.ResponseWriter, r *http.Request) {
+	action, err := handleHoldActionBadRequest(w, r)
+	if err != nil {
+		return
+	}
+	action.Amount = 0
+
+	ctx, cancel := ctxWithTimeoutCause(r, l.cfg, "void hold")
+	defer cancel()
+
+	if !authorizeHoldAction(w, r, action) {
+		return
+	}
+	hold, err := l.loyalty.VoidHold(ctx, action)
+	if err != nil {
+		responseHoldError(w, err)
+		return
+	}
+	dto.ResponseOKHold(w, holdResponse(hold), hold.Balance, hold.Held)
+}
+
+// authorizeHoldAction restricts action to holds of token owner account unless
+// token owner can withdraw from any account.
+func authorizeHoldAction(w http.ResponseWriter, r *http.Request, action *domain.HoldAction) bool {
+	// token is verified by middleware
+	claims, err := jwt.ClaimsFromContext(r.Context())
+	if err != nil {
+		dto.ResponseErrorUnauthorized(w, "jwt token required")
+		return false
+	}
+	switch {
+	case claims.HasPermission(jwt.PermissionLoyaltyWithdrawAny):
+	case claims.HasPermission(jwt.PermissionLoyaltyWithdraw):
+		action.UUID = claims.UID
+	default:
+		dto.ResponseErrorForbidden(w, "permission loyalty:withdraw required")
+		return false
+	}
+	return true
+}
+
+func responseHoldError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, loyaltyservice.ErrNegativeBalance):
+		dto.ResponseErrorBadRequest(w, "not enough available points")
+	case errors.Is(err, loyaltyservice.ErrUserNotFound):
+		dto.ResponseErrorBadRequest(w, "user not found")
+	case errors.Is(err, loyaltyservice.ErrHoldNotFound):
+		dto.ResponseErrorNotFound(w, "hold not found")
+	case errors.Is(err, loyaltyservice.ErrHoldNotActive):
+		dto.ResponseErrorStatusConflict(w, "hold is already captured, voided or expired")
+	case errors.Is(err, loyaltyservice.ErrHoldAmountExceeded):
+		dto.ResponseErrorBadRequest(w, "captured amount exceeds hold")
+	case errors.Is(err, loyaltyservice.ErrAccountFrozen):
+		dto.ResponseErrorForbidden(w, "account is frozen")
+	case errors.Is(err, loyaltyservice.ErrAccountClosed):
+		dto.ResponseErrorForbidden(w, "account is closed")
+	case errors.Is(err, loyaltyservice.ErrLotsMismatch):
+		dto.ResponseErrorInternal(w, "account points don't match balance, operation is rejected")
+	default:
+		dto.ResponseErrorInternal(w, "internal server error")
+	}
+}
+
+func holdResponse(hold *domain.Hold) dto.Hold {
+	return dto.Hold{
+		ID:            hold.ID,
+		UUID:          hold.UUID,
+		Amount:        hold.Amount,
+		Captured:      hold.Captured,
+		Status:        hold.Status,
+		Comment:       hold.Comment,
+		ExpiresAt:     hold.ExpiresAt,
+		TransactionID: hold.TransactionID,
+	}
+}
@@ -5,14 +5,12 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
 	"github.com/AlexBlackNn/authloyalty/loyalty/internal/dto"
 	"github.com/AlexBlackNn/authloyalty/loyalty/internal/jwt"
 	"github.com/AlexBlackNn/authloyalty/loyalty/internal/services/loyaltyservice"
-	"github.com/AlexBlackNn/authloyalty/loyalty/pkg/ssoclient"
 	"go.opentelemetry.io/otel"
 
 	"github.com/AlexBlackNn/authloyalty/loyalty/internal/config"
@@ -27,13 +25,45 @@ type loyaltyService interface {
 		ctx context.Context,
 		reqData *domain.UserLoyalty,
 	) (*domain.UserLoyalty, error)
+	GetTransactions(
+		ctx context.Context,
+		filter *domain.TransactionFilter,
+	) (*domain.TransactionPage, error)
+	ReverseTransaction(
+		ctx context.Context,
+		reversal *domain.Reversal,
+	) (*domain.Reversal, error)
+	CreateHold(
+		ctx context.Context,
+		hold *domain.Hold,
+	) (*domain.Hold, error)
+	CaptureHold(
+		ctx context.Context,
+		action *domain.HoldAction,
+	) (*domain.Hold, error)
+	VoidHold(
+		ctx context.Context,
+		action *domain.HoldAction,
+	) (*domain.Hold, error)
+	Transfer(
+		ctx context.Context,
+		transfer *domain.Transfer,
+	) (*domain.Transfer, error)
+	AddPurchase(
+		ctx context.Context,
+		purchase *domain.Purchase,
+	) (*domain.Accrual, error)
+	SaveRejectedLoyalty(
+		ctx context.Context,
+		reqData *domain.UserLoyalty,
+	) error
+	ReplayDeadLetters(ctx context.Context, limit int) (int, error)
 }
 
 type LoyaltyHandlers struct {
-	log       *slog.Logger
-	cfg       *config.Config
-	loyalty   loyaltyService
-	ssoClient *ssoclient.SSOClient
+	log     *slog.Logger
+	cfg     *config.Config
+	loyalty loyaltyService
 }
 
 func New(
@@ -41,15 +71,10 @@ func New(
 	cfg *config.Config,
 	loyalty loyaltyService,
 ) LoyaltyHandlers {
-	ssoClient, err := ssoclient.New(cfg)
-	if err != nil {
-		log.Error("can't create SSO client")
-	}
 	return LoyaltyHandlers{
-		log:       log,
-		cfg:       cfg,
-		ssoClient: ssoClient,
-		loyalty:   loyalty,
+		log:     log,
+		cfg:     cfg,
+		loyalty: loyalty,
 	}
 }
 
@@ -68,12 +93,20 @@ func ctxWithTimeoutCause(
 
 var tracer = otel.Tracer("loyalty service")
 
+const (
+	// IdempotencyKeyHeader is a header with a client generated key of the
+	// request, retry of the request with the same key is not applied twice.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	maxIdempotencyKeyLen = 255
+)
+
 // @Summary AddLoyalty
 // @Description Add Loyalty
 // @Tags Loyalty
 // @Accept json
 // @Produce json
 // @Param body body dto.UserLoyalty true "UserLoyalty request"
+// @Param Idempotency-Key header string false "Repeated request with the same key returns result of the first one"
 // @Success 201 {object} dto.Response "Add loyalty successful"
 // @Router /loyalty [post]
 // @Security BearerAuth
@@ -83,65 +116,106 @@ func (l *LoyaltyHandlers) AddLoyalty(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	idempotencyKey := r.Header.Get(IdempotencyKeyHeader)
+	if len(idempotencyKey) > maxIdempotencyKeyLen {
+		dto.ResponseErrorBadRequest(w, "idempotency key is too long")
+		return
+	}
+
 	ctx, cancel := ctxWithTimeoutCause(r, l.cfg, "add loyalty")
 	defer cancel()
 
-	tokenString := r.Header.Get("Authorization")
-	token := strings.TrimPrefix(tokenString, "Bearer")
-	token = strings.TrimPrefix(token, " ")
-	if !l.ssoClient.IsJWTValid(ctx, tracer, token) {
-		dto.ResponseErrorBadRequest(w, "jwt token invalid")
-		return
-	}
-	uuid, _, err := jwt.Parse(token)
+	// token is verified by middleware
+	claims, err := jwt.ClaimsFromContext(ctx)
 	if err != nil {
-		dto.ResponseErrorBadRequest(w, "jwt token parsing failed")
+		dto.ResponseErrorUnauthorized(w, "jwt token required")
 		return
 	}
 
-	var userLoyalty *domain.UserLoyalty
-
-	isAdmin := l.ssoClient.IsAdmin(ctx, tracer, uuid)
-	// only admins can deposit and withdraw loyalty using uuid in post request
-	if isAdmin {
-		userLoyalty = &domain.UserLoyalty{
-			UUID:      reqData.UUID,
-			Operation: reqData.Operation,
-			Comment:   reqData.Comment,
-			Balance:   reqData.Balance,
-		}
-	} else if reqData.Operation == "d" {
-		dto.ResponseErrorBadRequest(w, "only admins can deposit loyalty")
-		return
-	} else {
+	userLoyalty := &domain.UserLoyalty{
 		// users can only withdraw loyalty from their own account (uuid extracted from jwt)
-		userLoyalty = &domain.UserLoyalty{
-			UUID:      uuid,
-			Operation: reqData.Operation,
-			Comment:   reqData.Comment,
-			Balance:   reqData.Balance,
-		}
+		UUID:      claims.UID,
+		Operation: reqData.Operation,
+		Comment:   reqData.Comment,
+		Balance:   reqData.Balance,
+		// keys are scoped by user, so keys of different users don't clash
+		IdempotencyKey: idempotencyKey,
+		RequestedBy:    claims.UID,
+		// saved with the key, so repeated request gets the same status
+		ResponseStatus: http.StatusOK,
+	}
+	switch {
+	case reqData.Operation == "w" && claims.HasPermission(jwt.PermissionLoyaltyWithdrawAny):
+		userLoyalty.UUID = reqData.UUID
+	case reqData.Operation == "w" && claims.HasPermission(jwt.PermissionLoyaltyWithdraw):
+	case reqData.Operation == "w":
+		dto.ResponseErrorForbidden(w, "permission loyalty:withdraw required")
+		return
+	case claims.HasPermission(jwt.PermissionLoyaltyDeposit):
+		userLoyalty.UUID = reqData.UUID
+	default:
+		dto.ResponseErrorForbidden(w, "permission loyalty:deposit required")
+		return
 	}
 
 	loyalty, err := l.loyalty.AddLoyalty(ctx, userLoyalty)
 
 	if err != nil {
-		if errors.Is(err, loyaltyservice.ErrNegativeBalance) {
-			dto.ResponseErrorBadRequest(w, "withdraw such amount of loyalty leads to negative balance")
+		if errors.Is(err, loyaltyservice.ErrIdempotencyKeyConflict) {
+			dto.ResponseErrorStatusConflict(w, "idempotency key is already used by other operation")
 			return
 		}
-		if errors.Is(err, loyaltyservice.ErrUserNotFound) {
-			dto.ResponseErrorBadRequest(w, "user not found")
+		// data integrity violation is not saved as rejection, operation can be
+		// retried after the account is fixed
+		if errors.Is(err, loyaltyservice.ErrLotsMismatch) {
+			dto.ResponseErrorInternal(w, "account points don't match balance, operation is rejected")
 			return
 		}
-		dto.ResponseErrorInternal(w, "internal server error")
+		code, message, rejected := addLoyaltyRejection(err)
+		if !rejected {
+			dto.ResponseErrorInternal(w, "internal server error")
+			return
+		}
+		// rejection is final, retry with the same key gets the same response
+		userLoyalty.ResponseStatus = code
+		userLoyalty.ResponseError = message
+		if err = l.loyalty.SaveRejectedLoyalty(ctx, userLoyalty); err != nil {
+			l.log.Error("failed to save rejected operation", "err", err.Error())
+		}
+		dto.ResponseError(w, code, message)
+		return
+	}
+	// repeated request is answered with response saved with idempotency key
+	code := http.StatusOK
+	if loyalty.ResponseStatus != 0 {
+		code = loyalty.ResponseStatus
+	}
+	if loyalty.ResponseError != "" {
+		dto.ResponseError(w, code, loyalty.ResponseError)
 		return
 	}
-	dto.ResponseOKLoyalty(w, loyalty.UUID, loyalty.Balance)
+	dto.ResponseLoyalty(w, code, loyalty.UUID, loyalty.Balance)
+}
+
+// addLoyaltyRejection returns response to operation rejected by business
+// rules. Other errors may be transient, they are not rejections and retry
+// applies the operation again.
+func addLoyaltyRejection(err error) (int, string, bool) {
+	switch {
+	case errors.Is(err, loyaltyservice.ErrNegativeBalance):
+		return http.StatusBadRequest, "withdraw such amount of loyalty leads to negative balance", true
+	case errors.Is(err, loyaltyservice.ErrUserNotFound):
+		return http.StatusBadRequest, "user not found", true
+	case errors.Is(err, loyaltyservice.ErrAccountFrozen):
+		return http.StatusForbidden, "account is frozen", true
+	case errors.Is(err, loyaltyservice.ErrAccountClosed):
+		return http.StatusForbidden, "account is closed", true
+	}
+	return 0, "", false
 }
 
 // @Summary GetLoyalty
-// @Description Get Loyalty
+// @Description Get Loyalty, held is a part of balance reserved by holds, expiring_soon is a part of balance expiring within configured window
 // @Tags Loyalty
 // @Accept json
 // @Produce json
@@ -163,5 +237,50 @@ func (l *LoyaltyHandlers) GetLoyalty(w http.ResponseWriter, r *http.Request) {
 		dto.ResponseErrorInternal(w, "internal server error")
 		return
 	}
-	dto.ResponseOKLoyalty(w, loyalty.UUID, loyalty.Balance)
+	dto.ResponseOKAccount(w, loyalty.UUID, loyalty.Balance, loyalty.Held, loyalty.ExpiringSoon)
+}
+
+// @Summary ReplayDeadLetters
+// @Description Publish loyalty events moved to dead-letter topic back to the original topic
+// @Tags Loyalty
+// @Accept json
+// @Produce json
+// @Param body body dto.ReplayDeadLetters true "ReplayDeadLetters request"
+// @Success 200 {object} dto.Response "Dead letters replayed"
+// @Router /loyalty/dlq/replay [post]
+// @Security BearerAuth
+func (l *LoyaltyHandlers) ReplayDeadLetters(w http.ResponseWriter, r *http.Request) {
+	reqData, err := handleReplayDeadLettersBadRequest(w, r, &dto.ReplayDeadLetters{})
+	if err != nil {
+		return
+	}
+
+	// replay reads dead-letter topic, it takes longer than other requests
+	ctx, cancel := context.WithTimeoutCause(
+		r.Context(), l.cfg.Kafka.ReplayTimeout, errors.New("replay dead letters"),
+	)
+	defer cancel()
+
+	// token is verified by middleware
+	claims, err := jwt.ClaimsFromContext(ctx)
+	if err != nil {
+		dto.ResponseErrorUnauthorized(w, "jwt token required")
+		return
+	}
+	if !claims.HasPermission(jwt.PermissionLoyaltyDLQReplay) {
+		dto.ResponseErrorForbidden(w, "permission loyalty:dlq:replay required")
+		return
+	}
+
+	replayed, err := l.loyalty.ReplayDeadLetters(ctx, reqData.Limit)
+	if err != nil {
+		// replayed dead letters are committed, so request can be safely repeated
+		if errors.Is(err, context.DeadlineExceeded) {
+			dto.ResponseOKReplayed(w, replayed)
+			return
+		}
+		dto.ResponseErrorInternal(w, "internal server error")
+		return
+	}
+	dto.ResponseOKReplayed(w, replayed)
 }
@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/dto"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/jwt"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/services/loyaltyservice"
+)
+
+// @Summary AddPurchase
+// @Description Deposit points earned by purchase by earning rules: points per currency unit, category multipliers, campaigns and caps per user. Purchase is accrued once. occurred_at (request time if empty) must be within earning_rules.occurredAtWindow of server time. Requires loyalty:deposit permission.
+// @Tags Loyalty
+// @Accept json
+// @Produce json
+// @Param body body dto.Purchase true "Purchase, amounts are in minor currency units"
+// @Success 200 {object} dto.PurchaseResponse "Purchase points accrued"
+// @Router /loyalty/purchases [post]
+// @Security BearerAuth
+func (l *LoyaltyHandlers) AddPurchase(w http.ResponseWriter, r *http.Request) {
+	reqData, err := handlePurchaseBadRequest(w, r, &dto.Purchase{})
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := ctxWithTimeoutCause(r, l.cfg, "add purchase")
+	defer cancel()
+
+	// token is verified by middleware
+	claims, err := jwt.ClaimsFromContext(ctx)
+	if err != nil {
+		dto.ResponseErrorUnauthorized(w, "jwt token required")
+		return
+	}
+	if !claims.HasPermission(jwt.PermissionLoyaltyDeposit) {
+		dto.ResponseErrorForbidden(w, "permission loyalty:deposit required")
+		return
+	}
+
+	purchase := &domain.Purchase{
+		ID:    reqData.ID,
+		UUID:  reqData.UUID,
+		Items: make([]domain.PurchaseItem, 0, len(reqData.Items)),
+	}
+	for _, item := range reqData.Items {
+		purchase.Items = append(purchase.Items, domain.PurchaseItem{
+			Category: item.Category,
+			Amount:   item.Amount,
+		})
+	}
+	if reqData.OccurredAt != nil {
+		purchase.OccurredAt = *reqData.OccurredAt
+	}
+
+	accrual, err := l.loyalty.AddPurchase(ctx, purchase)
+	if err != nil {
+		switch {
+		case errors.Is(err, loyaltyservice.ErrPurchaseProcessed):
+			dto.ResponseErrorStatusConflict(w, "purchase already processed")
+		case errors.Is(err, loyaltyservice.ErrPurchaseTimeOutOfWindow):
+			dto.ResponseErrorBadRequest(w, "occurred_at is too far from server time")
+		case errors.Is(err, loyaltyservice.ErrUserNotFound):
+			dto.ResponseErrorBadRequest(w, "user not found")
+		case errors.Is(err, loyaltyservice.ErrAccountFrozen):
+			dto.ResponseErrorForbidden(w, "account is frozen")
+		case errors.Is(err, loyaltyservice.ErrAccountClosed):
+			dto.ResponseErrorForbidden(w, "account is closed")
+		default:
+			dto.ResponseErrorInternal(w, "internal server error")
+		}
+		return
+	}
+	dto.ResponseOKPurchase(w, dto.PurchaseResponse{
+		PurchaseID:    accrual.PurchaseID,
+		UUID:          accrual.UUID,
+		Points:        accrual.Points,
+		Deposited:     accrual.Deposited,
+		TransactionID: accrual.TransactionID,
+		Balance:       accrual.Balance,
+	})
+}
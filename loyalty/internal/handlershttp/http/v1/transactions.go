@@ -0,0 +1,166 @@
+package v1
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/dto"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/jwt"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/services/loyaltyservice"
+	"github.com/google/uuid"
+)
+
+// @Summary GetTransactions
+// @Description Get transaction history of loyalty account from newest to oldest. Owner of the account or users with loyalty:transactions:read:any permission are allowed.
+// @Tags Loyalty
+// @Accept json
+// @Produce json
+// @Param uuid path string true "User UUID"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query int false "Page size, 20 by default, 100 at most"
+// @Param from query string false "Transactions created at or after, RFC3339"
+// @Param to query string false "Transactions created before, RFC3339"
+// @Param type query string false "Transaction type: d - deposit, w - withdraw, e - expire"
+// @Param min_amount query int false "Minimal transaction amount"
+// @Param max_amount query int false "Maximal transaction amount"
+// @Success 200 {object} dto.TransactionsResponse "Get transactions successful"
+// @Router /loyalty/{uuid}/transactions [get]
+// @Security BearerAuth
+func (l *LoyaltyHandlers) GetTransactions(w http.ResponseWriter, r *http.Request) {
+	filter, err := handleGetTransactionsBadRequest(w, r)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := ctxWithTimeoutCause(r, l.cfg, "get transactions")
+	defer cancel()
+
+	// token is verified by middleware
+	claims, err := jwt.ClaimsFromContext(ctx)
+	if err != nil {
+		dto.ResponseErrorUnauthorized(w, "jwt token required")
+		return
+	}
+	if claims.UID != filter.UUID && !claims.HasPermission(jwt.PermissionLoyaltyReadAny) {
+		dto.ResponseErrorForbidden(w, "permission loyalty:transactions:read:any required")
+		return
+	}
+
+	page, err := l.loyalty.GetTransactions(ctx, filter)
+	if err != nil {
+		dto.ResponseErrorInternal(w, "internal server error")
+		return
+	}
+	transactions := make([]dto.Transaction, 0, len(page.Transactions))
+	for _, transaction := range page.Transactions {
+		transactions = append(transactions, dto.Transaction{
+			ID:        transaction.ID,
+			Amount:    transaction.Amount,
+			Type:      transaction.Type,
+			Comment:   transaction.Comment,
+			CreatedAt: transaction.CreatedAt,
+			// reversals are linked to the transaction they undo
+			ReversalOf: transaction.ReversalOf,
+			Reversed:   transaction.Reversed,
+			TransferID: transaction.TransferID,
+		})
+	}
+	var nextCursor string
+	if page.Next != nil {
+		nextCursor = encodeTransactionCursor(page.Next)
+	}
+	dto.ResponseOKTransactions(w, transactions, nextCursor)
+}
+
+// @Summary ReverseTransaction
+// @Description Undo deposit or withdraw fully or partially by the opposite operation linked to it. Sum of reversals can't exceed the transaction amount. Requires loyalty:reverse permission.
+// @Tags Loyalty
+// @Accept json
+// @Produce json
+// @Param id path string true "Transaction ID"
+// @Param body body dto.ReverseTransaction true "Reversed amount, the rest of the transaction is reversed if it's empty"
+// @Success 200 {object} dto.ReversalResponse "Transaction reversed"
+// @Router /loyalty/transactions/{id}/reverse [post]
+// @Security BearerAuth
+func (l *LoyaltyHandlers) ReverseTransaction(w http.ResponseWriter, r *http.Request) {
+	reversal, err := handleReverseTransactionBadRequest(w, r)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := ctxWithTimeoutCause(r, l.cfg, "reverse transaction")
+	defer cancel()
+
+	// token is verified by middleware
+	claims, err := jwt.ClaimsFromContext(ctx)
+	if err != nil {
+		dto.ResponseErrorUnauthorized(w, "jwt token required")
+		return
+	}
+	if !claims.HasPermission(jwt.PermissionLoyaltyReverse) {
+		dto.ResponseErrorForbidden(w, "permission loyalty:reverse required")
+		return
+	}
+
+	reversal, err = l.loyalty.ReverseTransaction(ctx, reversal)
+	if err != nil {
+		switch {
+		case errors.Is(err, loyaltyservice.ErrTransactionNotFound):
+			dto.ResponseErrorNotFound(w, "transaction not found")
+		case errors.Is(err, loyaltyservice.ErrTransactionNotReversible):
+			dto.ResponseErrorBadRequest(w, "only deposit and withdraw can be reversed")
+		case errors.Is(err, loyaltyservice.ErrReversalAmountExceeded):
+			dto.ResponseErrorStatusConflict(w, "reversals exceed transaction amount")
+		case errors.Is(err, loyaltyservice.ErrNegativeBalance):
+			dto.ResponseErrorBadRequest(w, "reversal leads to negative balance")
+		case errors.Is(err, loyaltyservice.ErrAccountFrozen):
+			dto.ResponseErrorForbidden(w, "account is frozen")
+		case errors.Is(err, loyaltyservice.ErrAccountClosed):
+			dto.ResponseErrorForbidden(w, "account is closed")
+		case errors.Is(err, loyaltyservice.ErrLotsMismatch):
+			dto.ResponseErrorInternal(w, "account points don't match balance, operation is rejected")
+		default:
+			dto.ResponseErrorInternal(w, "internal server error")
+		}
+		return
+	}
+	dto.ResponseOKReversal(w, dto.Transaction{
+		ID:         reversal.ID,
+		Amount:     reversal.Amount,
+		Type:       reversal.Type,
+		Comment:    reversal.Comment,
+		CreatedAt:  reversal.CreatedAt,
+		ReversalOf: reversal.TransactionID,
+	}, reversal.UUID, reversal.Balance)
+}
+
+// encodeTransactionCursor returns opaque cursor passed by clients to get the
+// next page.
+func encodeTransactionCursor(cursor *domain.TransactionCursor) string {
+	return base64.RawURLEncoding.EncodeToString(
+		[]byte(cursor.CreatedAt.Format(time.RFC3339Nano) + "|" + cursor.ID),
+	)
+}
+
+func decodeTransactionCursor(cursor string) (*domain.TransactionCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+	createdAt, id, ok := strings.Cut(string(decoded), "|")
+	if !ok {
+		return nil, errors.New("cursor without id")
+	}
+	if _, err = uuid.Parse(id); err != nil {
+		return nil, err
+	}
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.TransactionCursor{CreatedAt: t, ID: id}, nil
+}
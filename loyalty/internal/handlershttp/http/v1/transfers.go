@@ -0,0 +1,84 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/dto"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/jwt"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/services/loyaltyservice"
+)
+
+// @Summary Transfer
+// @Description Transfer points to other user. Users with loyalty:withdraw permission send points from their own account, with loyalty:withdraw:any - from any account. Points sent within a day are limited.
+// @Tags Loyalty
+// @Accept json
+// @Produce json
+// @Param body body dto.Transfer true "Transfer request"
+// @Success 200 {object} dto.TransferResponse "Points transferred"
+// @Router /loyalty/transfer [post]
+// @Security BearerAuth
+func (l *LoyaltyHandlers) Transfer(w http.ResponseWriter, r *http.Request) {
+	reqData, err := handleTransferBadRequest(w, r, &dto.Transfer{})
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := ctxWithTimeoutCause(r, l.cfg, "transfer")
+	defer cancel()
+
+	// token is verified by middleware
+	claims, err := jwt.ClaimsFromContext(ctx)
+	if err != nil {
+		dto.ResponseErrorUnauthorized(w, "jwt token required")
+		return
+	}
+	transfer := &domain.Transfer{
+		From:    claims.UID,
+		To:      reqData.To,
+		Amount:  reqData.Amount,
+		Comment: reqData.Comment,
+	}
+	switch {
+	case claims.HasPermission(jwt.PermissionLoyaltyWithdrawAny):
+		if reqData.From != "" {
+			transfer.From = reqData.From
+		}
+	case claims.HasPermission(jwt.PermissionLoyaltyWithdraw):
+	default:
+		dto.ResponseErrorForbidden(w, "permission loyalty:withdraw required")
+		return
+	}
+
+	transfer, err = l.loyalty.Transfer(ctx, transfer)
+	if err != nil {
+		switch {
+		case errors.Is(err, loyaltyservice.ErrSelfTransfer):
+			dto.ResponseErrorBadRequest(w, "transfer to the same account")
+		case errors.Is(err, loyaltyservice.ErrUserNotFound):
+			dto.ResponseErrorBadRequest(w, "user not found")
+		case errors.Is(err, loyaltyservice.ErrNegativeBalance):
+			dto.ResponseErrorBadRequest(w, "not enough available points")
+		case errors.Is(err, loyaltyservice.ErrTransferLimitExceeded):
+			dto.ResponseErrorForbidden(w, "daily transfer limit exceeded")
+		case errors.Is(err, loyaltyservice.ErrAccountFrozen):
+			dto.ResponseErrorForbidden(w, "account is frozen")
+		case errors.Is(err, loyaltyservice.ErrAccountClosed):
+			dto.ResponseErrorForbidden(w, "account is closed")
+		case errors.Is(err, loyaltyservice.ErrLotsMismatch):
+			dto.ResponseErrorInternal(w, "account points don't match balance, operation is rejected")
+		default:
+			dto.ResponseErrorInternal(w, "internal server error")
+		}
+		return
+	}
+	dto.ResponseOKTransfer(w, dto.TransferResponse{
+		TransferID: transfer.ID,
+		From:       transfer.From,
+		To:         transfer.To,
+		Amount:     transfer.Amount,
+		CreatedAt:  transfer.CreatedAt,
+		Balance:    transfer.Balance,
+	})
+}
@@ -0,0 +1,168 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// minRefetchInterval protects sso from refetching keys on every token with unknown kid.
+const minRefetchInterval = 10 * time.Second
+
+var ErrKeyNotFound = errors.New("public key not found")
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+}
+
+type publicKey struct {
+	algorithm string
+	key       crypto.PublicKey
+}
+
+// keySet caches public keys published by sso.
+type keySet struct {
+	url             string
+	client          *http.Client
+	refreshInterval time.Duration
+
+	mu        sync.RWMutex
+	keys      map[string]publicKey
+	fetchedAt time.Time
+}
+
+func newKeySet(url string, refreshInterval time.Duration) *keySet {
+	return &keySet{
+		url:             url,
+		client:          &http.Client{Timeout: 5 * time.Second},
+		refreshInterval: refreshInterval,
+		keys:            make(map[string]publicKey),
+	}
+}
+
+// get returns public key by kid. Keys are refetched when they are stale or
+// kid is unknown, i.e. sso has just rotated its signing key.
+func (ks *keySet) get(ctx context.Context, kid string) (publicKey, error) {
+	ks.mu.RLock()
+	key, ok := ks.find(kid)
+	stale := time.Since(ks.fetchedAt) > ks.refreshInterval
+	canRefetch := time.Since(ks.fetchedAt) > minRefetchInterval
+	ks.mu.RUnlock()
+
+	if ok && !stale {
+		return key, nil
+	}
+	if !ok && !canRefetch {
+		return publicKey{}, ErrKeyNotFound
+	}
+	if err := ks.fetch(ctx); err != nil {
+		if ok {
+			// sso is not available, keep using cached key
+			return key, nil
+		}
+		return publicKey{}, err
+	}
+
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	key, ok = ks.find(kid)
+	if !ok {
+		return publicKey{}, ErrKeyNotFound
+	}
+	return key, nil
+}
+
+// find must be called under lock. Tokens without kid are accepted only if
+// sso publishes the only key.
+func (ks *keySet) find(kid string) (publicKey, bool) {
+	if kid == "" && len(ks.keys) == 1 {
+		for _, key := range ks.keys {
+			return key, true
+		}
+	}
+	key, ok := ks.keys[kid]
+	return key, ok
+}
+
+func (ks *keySet) fetch(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ks.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := ks.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch jwks: unexpected status %d", resp.StatusCode)
+	}
+
+	var jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return fmt.Errorf("failed to decode jwks: %w", err)
+	}
+	keys := make(map[string]publicKey, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		key, err := k.publicKey()
+		if err != nil {
+			return err
+		}
+		keys[k.Kid] = publicKey{algorithm: k.Alg, key: key}
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.keys = keys
+	ks.fetchedAt = time.Now()
+	return nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwk %s: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwk %s: %w", k.Kid, err)
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("invalid jwk %s: unsupported curve %s", k.Kid, k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwk %s: %w", k.Kid, err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid jwk %s: wrong key size", k.Kid)
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("invalid jwk %s: unsupported key type %s", k.Kid, k.Kty)
+	}
+}
@@ -0,0 +1,63 @@
+package jwt
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+// revocationCache keeps results of revocation checks made by sso, so sso is
+// called at most once per ttl for a token.
+type revocationCache struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	entries   map[[sha256.Size]byte]revocationEntry
+	cleanedAt time.Time
+}
+
+type revocationEntry struct {
+	valid     bool
+	expiresAt time.Time
+}
+
+func newRevocationCache(ttl time.Duration) *revocationCache {
+	return &revocationCache{
+		ttl:       ttl,
+		entries:   make(map[[sha256.Size]byte]revocationEntry),
+		cleanedAt: time.Now(),
+	}
+}
+
+func (rc *revocationCache) get(token string) (valid bool, ok bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	entry, ok := rc.entries[sha256.Sum256([]byte(token))]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return false, false
+	}
+	return entry.valid, true
+}
+
+// set caches check result. Revoked tokens never become valid again, so they
+// are cached until token expiration, valid ones only for ttl.
+func (rc *revocationCache) set(token string, valid bool, tokenExpiresAt time.Time) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	now := time.Now()
+	expiresAt := tokenExpiresAt
+	if valid && now.Add(rc.ttl).Before(tokenExpiresAt) {
+		expiresAt = now.Add(rc.ttl)
+	}
+	rc.entries[sha256.Sum256([]byte(token))] = revocationEntry{valid: valid, expiresAt: expiresAt}
+
+	if now.Sub(rc.cleanedAt) < rc.ttl {
+		return
+	}
+	for key, entry := range rc.entries {
+		if now.After(entry.expiresAt) {
+			delete(rc.entries, key)
+		}
+	}
+	rc.cleanedAt = now
+}
@@ -0,0 +1,166 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const TokenTypeAccess = "access"
+
+// Permissions granted by sso roles.
+const (
+	PermissionLoyaltyDeposit     = "loyalty:deposit"
+	PermissionLoyaltyWithdraw    = "loyalty:withdraw"
+	PermissionLoyaltyWithdrawAny = "loyalty:withdraw:any"
+	PermissionLoyaltyDLQReplay   = "loyalty:dlq:replay"
+	PermissionLoyaltyReadAny     = "loyalty:transactions:read:any"
+	PermissionLoyaltyReverse     = "loyalty:reverse"
+)
+
+var (
+	ErrTokenInvalid   = errors.New("token invalid")
+	ErrTokenWrongType = errors.New("token wrong type")
+	ErrTokenRevoked   = errors.New("token has been revoked")
+	ErrSSOUnavailable = errors.New("sso unavailable")
+	ErrClaimsNotFound = errors.New("claims not found")
+)
+
+var tracer = otel.Tracer("loyalty service")
+
+// Claims are claims of verified access token.
+type Claims struct {
+	UID         string
+	Email       string
+	Roles       []string
+	Permissions []string
+	ExpiresAt   time.Time
+}
+
+// HasPermission checks if token owner is granted the permission.
+func (c *Claims) HasPermission(permission string) bool {
+	return slices.Contains(c.Permissions, permission)
+}
+
+type revocationChecker interface {
+	CheckJWT(
+		ctx context.Context,
+		tracer trace.Tracer,
+		token string,
+	) (bool, error)
+}
+
+// Verifier verifies tokens issued by sso locally using its published keys.
+// sso is called only to check whether a token is revoked.
+type Verifier struct {
+	keys              *keySet
+	revocations       *revocationCache
+	revocationChecker revocationChecker
+}
+
+// NewVerifier returns token verifier. Keys are fetched lazily on first verification.
+func NewVerifier(cfg *config.Config, revocationChecker revocationChecker) *Verifier {
+	return &Verifier{
+		keys: newKeySet(
+			cfg.TokenVerification.JWKSURL,
+			cfg.TokenVerification.JWKSRefreshInterval,
+		),
+		revocations:       newRevocationCache(cfg.TokenVerification.RevocationCacheTtl),
+		revocationChecker: revocationChecker,
+	}
+}
+
+// Verify checks token signature, expiration, type and revocation.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	ctx, span := tracer.Start(ctx, "jwt: Verify",
+		trace.WithAttributes(attribute.String("handler", "Verify")))
+	defer span.End()
+
+	tokenParsed, err := jwt.Parse(
+		token,
+		func(token *jwt.Token) (any, error) {
+			kid, _ := token.Header["kid"].(string)
+			key, err := v.keys.get(ctx, kid)
+			if err != nil {
+				return nil, err
+			}
+			// prevents algorithm substitution, i.e. "none" or HS256 signed with public key
+			if token.Method.Alg() != key.algorithm {
+				return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+			}
+			return key.key, nil
+		},
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTokenInvalid, err)
+	}
+	mapClaims, ok := tokenParsed.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrTokenInvalid
+	}
+	if mapClaims["token_type"] != TokenTypeAccess {
+		return nil, ErrTokenWrongType
+	}
+	expiresAt, err := mapClaims.GetExpirationTime()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTokenInvalid, err)
+	}
+	claims := &Claims{ExpiresAt: expiresAt.Time}
+	claims.UID, _ = mapClaims["uid"].(string)
+	claims.Email, _ = mapClaims["email"].(string)
+	claims.Roles = stringsClaim(mapClaims, "roles")
+	claims.Permissions = stringsClaim(mapClaims, "permissions")
+	if claims.UID == "" {
+		return nil, fmt.Errorf("%w: uid not found", ErrTokenInvalid)
+	}
+
+	valid, ok := v.revocations.get(token)
+	if !ok {
+		valid, err = v.revocationChecker.CheckJWT(ctx, tracer, token)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrSSOUnavailable, err)
+		}
+		v.revocations.set(token, valid, claims.ExpiresAt)
+	}
+	if !valid {
+		return nil, ErrTokenRevoked
+	}
+	return claims, nil
+}
+
+// stringsClaim extracts list of strings, json arrays are decoded as []any.
+func stringsClaim(mapClaims jwt.MapClaims, name string) []string {
+	values, _ := mapClaims[name].([]any)
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if str, ok := value.(string); ok {
+			result = append(result, str)
+		}
+	}
+	return result
+}
+
+type claimsKey struct{}
+
+// ContextWithClaims returns context carrying claims of verified token.
+func ContextWithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFromContext returns claims put to context by token verification middleware.
+func ClaimsFromContext(ctx context.Context) (*Claims, error) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	if !ok {
+		return nil, ErrClaimsNotFound
+	}
+	return claims, nil
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/dto"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/jwt"
+)
+
+type tokenVerifier interface {
+	Verify(ctx context.Context, token string) (*jwt.Claims, error)
+}
+
+// TokenVerifier verifies bearer access token and puts its claims to request context.
+func TokenVerifier(log *slog.Logger, verifier tokenVerifier) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		log := log.With(
+			slog.String("component", "middleware/tokenVerifier"),
+		)
+
+		log.Info("token verifier middleware enabled")
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer")
+			token = strings.TrimSpace(token)
+			if token == "" {
+				dto.ResponseErrorUnauthorized(w, "jwt token required")
+				return
+			}
+			claims, err := verifier.Verify(r.Context(), token)
+			if err != nil {
+				log.Warn("token verification failed", "err", err.Error())
+				switch {
+				case errors.Is(err, jwt.ErrSSOUnavailable):
+					dto.ResponseErrorServiceUnavailable(w, "token revocation check failed")
+				case errors.Is(err, jwt.ErrTokenRevoked):
+					dto.ResponseErrorUnauthorized(w, "jwt token revoked")
+				case errors.Is(err, jwt.ErrTokenWrongType):
+					dto.ResponseErrorUnauthorized(w, "jwt token wrong type, expected access")
+				default:
+					dto.ResponseErrorUnauthorized(w, "jwt token invalid")
+				}
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(jwt.ContextWithClaims(r.Context(), claims)))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,125 @@
+package loyaltyservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/config"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/storage"
+	"github.com/AlexBlackNn/authloyalty/loyalty/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PurchasePoints returns points earned by purchase before caps. Points of an
+// item are its amount in currency units multiplied by points per unit, its
+// category multiplier and the largest multiplier of campaigns lasting when
+// the purchase was made. Points of the purchase are rounded down.
+func PurchasePoints(rules config.EarningRulesConfig, purchase *domain.Purchase) int {
+	minorUnits := rules.MinorUnits
+	if minorUnits <= 0 {
+		minorUnits = 1
+	}
+	var points float64
+	for _, item := range purchase.Items {
+		multiplier := 1.0
+		if categoryMultiplier, ok := rules.CategoryMultipliers[item.Category]; ok {
+			multiplier = categoryMultiplier
+		}
+		multiplier *= campaignMultiplier(rules.Campaigns, item.Category, purchase.OccurredAt)
+		points += float64(item.Amount) / float64(minorUnits) * rules.PointsPerUnit * multiplier
+	}
+	return int(math.Floor(points))
+}
+
+// campaignMultiplier returns the largest multiplier of campaigns of category
+// lasting at the time, 1 if there are no such campaigns.
+func campaignMultiplier(campaigns []config.CampaignConfig, category string, at time.Time) float64 {
+	multiplier := 1.0
+	for _, campaign := range campaigns {
+		if at.Before(campaign.From) || !at.Before(campaign.To) {
+			continue
+		}
+		if len(campaign.Categories) > 0 && !slices.Contains(campaign.Categories, category) {
+			continue
+		}
+		multiplier = max(multiplier, campaign.Multiplier)
+	}
+	return multiplier
+}
+
+// AddPurchase deposits points earned by purchase by earning rules. Purchase
+// is accrued once, repeated purchase returns ErrPurchaseProcessed. Purchase
+// time selects campaigns, so time differing from server time more than
+// earning_rules.occurredAtWindow is rejected with ErrPurchaseTimeOutOfWindow.
+func (l *Loyalty) AddPurchase(
+	ctx context.Context,
+	purchase *domain.Purchase,
+) (*domain.Accrual, error) {
+	const op = "SERVICE LAYER: AddPurchase"
+	ctx, span := tracer.Start(ctx, "service layer: AddPurchase",
+		trace.WithAttributes(attribute.String("handler", "AddPurchase")))
+	defer span.End()
+
+	log := l.log.With(
+		slog.String("info", op),
+		slog.String("purchase-id", purchase.ID),
+	)
+	log.Info("accruing purchase points")
+
+	now := time.Now()
+	if purchase.OccurredAt.IsZero() {
+		purchase.OccurredAt = now
+	}
+	window := l.cfg.EarningRules.OccurredAtWindow
+	if purchase.OccurredAt.Before(now.Add(-window)) || purchase.OccurredAt.After(now.Add(window)) {
+		log.Warn("purchase time is out of window", "occurred-at", purchase.OccurredAt)
+		return nil, ErrPurchaseTimeOutOfWindow
+	}
+	rules := l.cfg.EarningRules
+	accrual := &domain.Accrual{
+		PurchaseID: purchase.ID,
+		UUID:       purchase.UUID,
+		Points:     PurchasePoints(rules, purchase),
+		Comment:    "purchase " + purchase.ID,
+		EventID:    "purchase/" + purchase.ID,
+		ExpiresAt:  l.pointsExpiresAt(),
+	}
+	for _, earningCap := range rules.Caps {
+		accrual.Caps = append(accrual.Caps, domain.EarningCap{
+			Period: earningCap.Period,
+			Points: earningCap.Points,
+		})
+	}
+
+	accrual, err := l.loyalStorage.AddAccrual(ctx, accrual)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrEventProcessed):
+			return nil, ErrPurchaseProcessed
+		case errors.Is(err, storage.ErrUserNotFound):
+			return nil, ErrUserNotFound
+		case errors.Is(err, storage.ErrAccountFrozen):
+			return nil, ErrAccountFrozen
+		case errors.Is(err, storage.ErrAccountClosed):
+			return nil, ErrAccountClosed
+		}
+		tracing.SpanError(span, "failed to accrue purchase points", err)
+		log.Error("failed to accrue purchase points", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("purchase points accrued", "points", accrual.Points, "deposited", accrual.Deposited)
+	span.AddEvent(
+		"purchase points accrued",
+		trace.WithAttributes(
+			attribute.Int("points", accrual.Points),
+			attribute.Int("deposited", accrual.Deposited),
+		))
+	return accrual, nil
+}
@@ -5,4 +5,33 @@ import "errors"
 var (
 	ErrUserNotFound    = errors.New("user not found")
 	ErrNegativeBalance = errors.New("balance must be greater than zero")
+	ErrAccountFrozen   = errors.New("account is frozen")
+	ErrAccountClosed   = errors.New("account is closed")
+	// ErrIdempotencyKeyConflict is returned if idempotency key is already used
+	// by other operation.
+	ErrIdempotencyKeyConflict = errors.New("idempotency key used by other operation")
+	ErrHoldNotFound           = errors.New("hold not found")
+	ErrHoldNotActive          = errors.New("hold is not active")
+	// ErrHoldAmountExceeded is returned if captured amount exceeds held amount.
+	ErrHoldAmountExceeded  = errors.New("captured amount exceeds hold")
+	ErrTransactionNotFound = errors.New("transaction not found")
+	// ErrTransactionNotReversible is returned for reversals and operations
+	// other than deposit and withdraw.
+	ErrTransactionNotReversible = errors.New("transaction can't be reversed")
+	// ErrReversalAmountExceeded is returned if reversals exceed amount of the
+	// original transaction.
+	ErrReversalAmountExceeded = errors.New("reversals exceed transaction amount")
+	// ErrTransferLimitExceeded is returned if sender exceeds daily transfer
+	// limit.
+	ErrTransferLimitExceeded = errors.New("daily transfer limit exceeded")
+	ErrSelfTransfer          = errors.New("transfer to the same account")
+	// ErrPurchaseProcessed is returned if points of the purchase are already
+	// accrued.
+	ErrPurchaseProcessed = errors.New("purchase already processed")
+	// ErrPurchaseTimeOutOfWindow is returned if purchase time differs from
+	// server time more than earning_rules.occurredAtWindow.
+	ErrPurchaseTimeOutOfWindow = errors.New("purchase time is out of allowed window")
+	// ErrLotsMismatch is returned if account lots don't match its balance,
+	// operation is rejected until the account is fixed.
+	ErrLotsMismatch = errors.New("account points don't match balance")
 )
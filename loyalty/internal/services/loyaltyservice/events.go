@@ -0,0 +1,100 @@
+package loyaltyservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	eventsv1 "github.com/AlexBlackNn/authloyalty/commands/proto/events.v1/events.v1"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/loyalty/pkg/broker"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// eventHandler applies broker message of a single type.
+type eventHandler func(ctx context.Context, msg *broker.Message) error
+
+func (l *Loyalty) eventHandlers() map[string]eventHandler {
+	return map[string]eventHandler{
+		broker.TypeRegistration:  l.handleRegistration,
+		broker.TypeUserDeleted:   l.handleUserDeleted,
+		broker.TypeUserBlocked:   l.handleUserBlocked,
+		broker.TypeUserUnblocked: l.handleUserUnblocked,
+		// loyalty accounts don't depend on user email and roles
+		broker.TypeEmailChanged: l.skipEvent,
+		broker.TypeRoleChanged:  l.skipEvent,
+	}
+}
+
+// handleRegistration creates loyalty account with registration bonus.
+func (l *Loyalty) handleRegistration(ctx context.Context, msg *broker.Message) error {
+	userLoyalty, err := l.loyalStorage.AddLoyalty(ctx, &domain.UserLoyalty{
+		UUID:      msg.UUID,
+		Balance:   msg.Balance,
+		Operation: msg.Type,
+		Comment:   msg.Comment,
+		EventID:   msg.EventID,
+		ExpiresAt: l.pointsExpiresAt(),
+	})
+	if err != nil {
+		return err
+	}
+	l.log.Info("GetMessageChan: userLoyalty", "userLoyalty", userLoyalty)
+	trace.SpanFromContext(ctx).AddEvent(
+		"user loyalty extracted from broker message",
+		trace.WithAttributes(
+			attribute.String("user-id", userLoyalty.UUID),
+			attribute.Int("balance", userLoyalty.Balance),
+		),
+	)
+	return nil
+}
+
+// handleUserDeleted closes loyalty account of deleted user.
+func (l *Loyalty) handleUserDeleted(ctx context.Context, msg *broker.Message) error {
+	return l.updateAccountStatus(ctx, msg, domain.AccountClosed, nil)
+}
+
+// handleUserBlocked freezes loyalty account of blocked user.
+func (l *Loyalty) handleUserBlocked(ctx context.Context, msg *broker.Message) error {
+	blocked, ok := msg.Payload.(*eventsv1.UserBlocked)
+	if !ok {
+		return fmt.Errorf("unexpected payload %T of %s event", msg.Payload, msg.Type)
+	}
+	var frozenUntil *time.Time
+	if blocked.GetUntil() != nil {
+		until := blocked.GetUntil().AsTime()
+		frozenUntil = &until
+	}
+	return l.updateAccountStatus(ctx, msg, domain.AccountFrozen, frozenUntil)
+}
+
+// handleUserUnblocked makes frozen loyalty account active again.
+func (l *Loyalty) handleUserUnblocked(ctx context.Context, msg *broker.Message) error {
+	return l.updateAccountStatus(ctx, msg, domain.AccountActive, nil)
+}
+
+func (l *Loyalty) skipEvent(_ context.Context, msg *broker.Message) error {
+	l.log.Debug("event doesn't affect loyalty", "event-id", msg.EventID, "type", msg.Type)
+	return nil
+}
+
+func (l *Loyalty) updateAccountStatus(
+	ctx context.Context,
+	msg *broker.Message,
+	status string,
+	frozenUntil *time.Time,
+) error {
+	err := l.loyalStorage.UpdateAccountStatus(ctx, &domain.AccountStatus{
+		UUID:        msg.UUID,
+		Status:      status,
+		FrozenUntil: frozenUntil,
+		EventID:     msg.EventID,
+	})
+	if err != nil {
+		return err
+	}
+	l.log.Info("account status updated", "user-id", msg.UUID, "status", status)
+	return nil
+}
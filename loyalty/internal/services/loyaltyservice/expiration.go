@@ -0,0 +1,108 @@
+package loyaltyservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/pkg/tracing"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	pointsExpired = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "loyalty_points_expired_total",
+		Help: "Number of expired loyalty points written off.",
+	})
+	pointsExpirationErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "loyalty_points_expiration_errors_total",
+		Help: "Number of accounts failed to write off expired points.",
+	})
+	lotsMismatches = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "loyalty_lots_mismatch_total",
+		Help: "Number of operations rejected because account lots don't match balance.",
+	})
+)
+
+// lotsMismatch reports operation rejected because account lots don't match
+// its balance. It's a data integrity violation, not a client error, so it's
+// logged as an alert to be investigated.
+func (l *Loyalty) lotsMismatch(op string, span trace.Span, err error) error {
+	lotsMismatches.Inc()
+	tracing.SpanError(span, "account lots don't match balance", err)
+	l.log.Error(
+		"DATA INTEGRITY ALERT: account lots don't match balance",
+		"op", op, "err", err.Error(),
+	)
+	return ErrLotsMismatch
+}
+
+// pointsExpiresAt returns when points deposited now expire, nil means they
+// never expire.
+func (l *Loyalty) pointsExpiresAt() *time.Time {
+	ttl := l.cfg.PointsExpiration.Ttl
+	if ttl <= 0 {
+		return nil
+	}
+	expiresAt := time.Now().Add(ttl)
+	return &expiresAt
+}
+
+// RunPointsExpiration periodically writes off expired points until ctx is
+// done.
+func (l *Loyalty) RunPointsExpiration(ctx context.Context) {
+	ticker := time.NewTicker(l.cfg.PointsExpiration.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := l.ExpirePoints(ctx); err != nil {
+			l.log.Error("points expiration failed", "err", err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExpirePoints writes off expired points of a batch of accounts and returns
+// the written off amount. Each account is processed in its own transaction,
+// failed account is retried by the next run.
+func (l *Loyalty) ExpirePoints(ctx context.Context) (int, error) {
+	const op = "SERVICE LAYER: ExpirePoints"
+	ctx, span := tracer.Start(ctx, "service layer: ExpirePoints",
+		trace.WithAttributes(attribute.String("handler", "ExpirePoints")))
+	defer span.End()
+
+	now := time.Now()
+	accounts, err := l.loyalStorage.GetAccountsWithExpiredPoints(
+		ctx, now, l.cfg.PointsExpiration.BatchSize,
+	)
+	if err != nil {
+		tracing.SpanError(span, "failed to get accounts with expired points", err)
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var total int
+	for _, uuid := range accounts {
+		expired, err := l.loyalStorage.ExpirePoints(ctx, uuid, now)
+		if err != nil {
+			pointsExpirationErrors.Inc()
+			l.log.Error("failed to expire points", "err", err.Error(), "uuid", uuid)
+			continue
+		}
+		pointsExpired.Add(float64(expired))
+		total += expired
+		l.log.Info("points expired", "uuid", uuid, "amount", expired)
+	}
+	span.AddEvent(
+		"points expired",
+		trace.WithAttributes(
+			attribute.Int("accounts", len(accounts)),
+			attribute.Int("amount", total),
+		))
+	return total, nil
+}
@@ -0,0 +1,173 @@
+package loyaltyservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/storage"
+	"github.com/AlexBlackNn/authloyalty/loyalty/pkg/tracing"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	holdsExpired = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "loyalty_holds_expired_total",
+		Help: "Number of expired holds released.",
+	})
+	holdsExpirationErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "loyalty_holds_expiration_errors_total",
+		Help: "Number of expired holds failed to be released.",
+	})
+)
+
+// CreateHold reserves points of account for Holds.Ttl, reserved points are
+// withdrawn by CaptureHold or released by VoidHold.
+func (l *Loyalty) CreateHold(
+	ctx context.Context,
+	hold *domain.Hold,
+) (*domain.Hold, error) {
+	const op = "SERVICE LAYER: CreateHold"
+	ctx, span := tracer.Start(ctx, "service layer: CreateHold",
+		trace.WithAttributes(attribute.String("handler", "CreateHold")))
+	defer span.End()
+
+	hold.ExpiresAt = time.Now().Add(l.cfg.Holds.Ttl)
+	hold, err := l.loyalStorage.CreateHold(ctx, hold)
+	if err != nil {
+		return nil, l.holdError(op, span, err)
+	}
+	span.AddEvent(
+		"hold created",
+		trace.WithAttributes(
+			attribute.String("hold-id", hold.ID),
+			attribute.Int("amount", hold.Amount),
+		))
+	return hold, nil
+}
+
+// CaptureHold withdraws captured amount of hold, the rest is released.
+func (l *Loyalty) CaptureHold(
+	ctx context.Context,
+	action *domain.HoldAction,
+) (*domain.Hold, error) {
+	const op = "SERVICE LAYER: CaptureHold"
+	ctx, span := tracer.Start(ctx, "service layer: CaptureHold",
+		trace.WithAttributes(attribute.String("handler", "CaptureHold")))
+	defer span.End()
+
+	hold, err := l.loyalStorage.CaptureHold(ctx, action)
+	if err != nil {
+		return nil, l.holdError(op, span, err)
+	}
+	span.AddEvent(
+		"hold captured",
+		trace.WithAttributes(
+			attribute.String("hold-id", hold.ID),
+			attribute.Int("captured", hold.Captured),
+		))
+	return hold, nil
+}
+
+// VoidHold releases points reserved by hold.
+func (l *Loyalty) VoidHold(
+	ctx context.Context,
+	action *domain.HoldAction,
+) (*domain.Hold, error) {
+	const op = "SERVICE LAYER: VoidHold"
+	ctx, span := tracer.Start(ctx, "service layer: VoidHold",
+		trace.WithAttributes(attribute.String("handler", "VoidHold")))
+	defer span.End()
+
+	hold, err := l.loyalStorage.VoidHold(ctx, action)
+	if err != nil {
+		return nil, l.holdError(op, span, err)
+	}
+	span.AddEvent(
+		"hold voided",
+		trace.WithAttributes(attribute.String("hold-id", hold.ID)),
+	)
+	return hold, nil
+}
+
+// holdError converts storage error of hold operation to service error.
+func (l *Loyalty) holdError(op string, span trace.Span, err error) error {
+	switch {
+	case errors.Is(err, storage.ErrUserNotFound):
+		return ErrUserNotFound
+	case errors.Is(err, storage.ErrNegativeBalance):
+		return ErrNegativeBalance
+	case errors.Is(err, storage.ErrAccountFrozen):
+		return ErrAccountFrozen
+	case errors.Is(err, storage.ErrAccountClosed):
+		return ErrAccountClosed
+	case errors.Is(err, storage.ErrHoldNotFound):
+		return ErrHoldNotFound
+	case errors.Is(err, storage.ErrHoldNotActive):
+		return ErrHoldNotActive
+	case errors.Is(err, storage.ErrHoldAmountExceeded):
+		return ErrHoldAmountExceeded
+	case errors.Is(err, storage.ErrLotsMismatch):
+		return l.lotsMismatch(op, span, err)
+	}
+	tracing.SpanError(span, "hold operation failed", err)
+	l.log.Error("hold operation failed", "op", op, "err", err.Error())
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+// RunHoldExpiration periodically releases expired holds until ctx is done.
+func (l *Loyalty) RunHoldExpiration(ctx context.Context) {
+	ticker := time.NewTicker(l.cfg.Holds.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := l.ExpireHolds(ctx); err != nil {
+			l.log.Error("holds expiration failed", "err", err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExpireHolds releases a batch of expired holds and returns number of
+// released holds. Hold captured or voided concurrently is skipped.
+func (l *Loyalty) ExpireHolds(ctx context.Context) (int, error) {
+	const op = "SERVICE LAYER: ExpireHolds"
+	ctx, span := tracer.Start(ctx, "service layer: ExpireHolds",
+		trace.WithAttributes(attribute.String("handler", "ExpireHolds")))
+	defer span.End()
+
+	holds, err := l.loyalStorage.GetExpiredHolds(ctx, time.Now(), l.cfg.Holds.BatchSize)
+	if err != nil {
+		tracing.SpanError(span, "failed to get expired holds", err)
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var expired int
+	for _, id := range holds {
+		hold, err := l.loyalStorage.ExpireHold(ctx, id)
+		if err != nil {
+			if errors.Is(err, storage.ErrHoldNotActive) {
+				continue
+			}
+			holdsExpirationErrors.Inc()
+			l.log.Error("failed to expire hold", "err", err.Error(), "hold-id", id)
+			continue
+		}
+		holdsExpired.Inc()
+		expired++
+		l.log.Info("hold expired", "hold-id", hold.ID, "uuid", hold.UUID, "amount", hold.Amount)
+	}
+	span.AddEvent(
+		"holds expired",
+		trace.WithAttributes(attribute.Int("holds", expired)),
+	)
+	return expired, nil
+}
@@ -2,9 +2,12 @@ package loyaltyservice
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/AlexBlackNn/authloyalty/loyalty/internal/storage"
 	"github.com/AlexBlackNn/authloyalty/loyalty/pkg/tracing"
@@ -19,6 +22,7 @@ import (
 
 type loyaltyBroker interface {
 	GetMessageChan() chan *broker.MessageReceived
+	ReplayDeadLetters(ctx context.Context, limit int) (int, error)
 }
 
 type loyaltyStorage interface {
@@ -30,6 +34,63 @@ type loyaltyStorage interface {
 		ctx context.Context,
 		loyalty *domain.UserLoyalty,
 	) (*domain.UserLoyalty, error)
+	GetTransactions(
+		ctx context.Context,
+		filter *domain.TransactionFilter,
+	) ([]domain.Transaction, error)
+	ReverseTransaction(
+		ctx context.Context,
+		reversal *domain.Reversal,
+	) (*domain.Reversal, error)
+	Transfer(
+		ctx context.Context,
+		transfer *domain.Transfer,
+	) (*domain.Transfer, error)
+	AddAccrual(
+		ctx context.Context,
+		accrual *domain.Accrual,
+	) (*domain.Accrual, error)
+	UpdateAccountStatus(
+		ctx context.Context,
+		accountStatus *domain.AccountStatus,
+	) error
+	SaveRejectedLoyalty(
+		ctx context.Context,
+		loyalty *domain.UserLoyalty,
+	) error
+	GetExpiringPoints(
+		ctx context.Context,
+		uuid string,
+		before time.Time,
+	) (int, error)
+	GetAccountsWithExpiredPoints(
+		ctx context.Context,
+		now time.Time,
+		limit int,
+	) ([]string, error)
+	ExpirePoints(
+		ctx context.Context,
+		uuid string,
+		now time.Time,
+	) (int, error)
+	CreateHold(
+		ctx context.Context,
+		hold *domain.Hold,
+	) (*domain.Hold, error)
+	CaptureHold(
+		ctx context.Context,
+		action *domain.HoldAction,
+	) (*domain.Hold, error)
+	VoidHold(
+		ctx context.Context,
+		action *domain.HoldAction,
+	) (*domain.Hold, error)
+	ExpireHold(ctx context.Context, id string) (*domain.Hold, error)
+	GetExpiredHolds(
+		ctx context.Context,
+		now time.Time,
+		limit int,
+	) ([]string, error)
 	HealthCheck(context.Context) error
 	Stop() error
 }
@@ -39,6 +100,8 @@ type Loyalty struct {
 	log          *slog.Logger
 	loyalBroker  loyaltyBroker
 	loyalStorage loyaltyStorage
+	// handlers process broker messages by message type.
+	handlers map[string]eventHandler
 }
 
 var tracer = otel.Tracer("loyalty service")
@@ -51,36 +114,56 @@ func New(
 	loyalStorage loyaltyStorage,
 ) *Loyalty {
 
-	msgChan := loyalBroker.GetMessageChan()
-	go func() {
-		for msg := range msgChan {
-
-			userLoyalty := &domain.UserLoyalty{UUID: msg.Msg.UUID, Balance: msg.Msg.Balance, Operation: msg.Msg.Type, Comment: msg.Msg.Comment}
-			ctx, span := tracer.Start(msg.Ctx, "service layer: GetMessageChan",
-				trace.WithAttributes(attribute.String("handler", "GetMessageChan")))
-			userLoyalty, err := loyalStorage.AddLoyalty(ctx, userLoyalty)
-			if err != nil {
-				log.Error(err.Error(), "userLoyalty", userLoyalty)
-				tracing.SpanError(span, "failed to create loyalty for user", err)
-				continue
-			}
-			log.Info("GetMessageChan: userLoyalty", userLoyalty)
-			span.AddEvent(
-				"user loyalty extracted from broker message",
-				trace.WithAttributes(
-					attribute.String("user-id", userLoyalty.UUID),
-					attribute.Int("balance", userLoyalty.Balance),
-				),
-			)
-			span.End()
-		}
-	}()
-
-	return &Loyalty{
+	loyalty := &Loyalty{
 		cfg:          cfg,
 		log:          log,
+		loyalBroker:  loyalBroker,
 		loyalStorage: loyalStorage,
 	}
+	loyalty.handlers = loyalty.eventHandlers()
+	go loyalty.consume(loyalBroker.GetMessageChan())
+	return loyalty
+}
+
+// consume applies broker messages to loyalty accounts and reports the result
+// back to broker, so offset is committed only after message is persisted.
+func (l *Loyalty) consume(msgChan chan *broker.MessageReceived) {
+	for msg := range msgChan {
+		err := l.processMessage(msg)
+		if msg.Result != nil {
+			msg.Result <- err
+		}
+	}
+}
+
+// processMessage dispatches message to handler of its type. Messages of
+// unknown types are skipped, they might be published by newer producers.
+func (l *Loyalty) processMessage(msg *broker.MessageReceived) error {
+	ctx, span := tracer.Start(msg.Ctx, "service layer: GetMessageChan",
+		trace.WithAttributes(
+			attribute.String("handler", "GetMessageChan"),
+			attribute.String("event-id", msg.Msg.EventID),
+			attribute.String("event-type", msg.Msg.Type),
+			attribute.String("occurred-at", msg.Msg.OccurredAt.Format(time.RFC3339)),
+		))
+	defer span.End()
+
+	handler, ok := l.handlers[msg.Msg.Type]
+	if !ok {
+		l.log.Warn("event of unknown type skipped", "event-id", msg.Msg.EventID, "type", msg.Msg.Type)
+		return nil
+	}
+	err := handler(ctx, &msg.Msg)
+	if err != nil {
+		if errors.Is(err, storage.ErrEventProcessed) {
+			l.log.Info("event already processed", "event-id", msg.Msg.EventID)
+			return nil
+		}
+		l.log.Error(err.Error(), "event-id", msg.Msg.EventID)
+		tracing.SpanError(span, "failed to process event", err)
+		return err
+	}
+	return nil
 }
 
 // HealthCheck returns service health check.
@@ -117,6 +200,16 @@ func (l *Loyalty) GetLoyalty(
 		log.Error("failed to get loyalty", "err", err.Error())
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	if window := l.cfg.PointsExpiration.ExpiringSoonWindow; window > 0 {
+		userLoyalty.ExpiringSoon, err = l.loyalStorage.GetExpiringPoints(
+			ctx, userLoyalty.UUID, time.Now().Add(window),
+		)
+		if err != nil {
+			tracing.SpanError(span, "failed to get expiring points", err)
+			log.Error("failed to get expiring points", "err", err.Error())
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
 	span.AddEvent(
 		"user loyalty extracted",
 		trace.WithAttributes(
@@ -141,6 +234,12 @@ func (l *Loyalty) AddLoyalty(
 	)
 	log.Info("add loyalty to user")
 
+	if userLoyalty.IdempotencyKey != "" {
+		userLoyalty.RequestHash = requestHash(userLoyalty)
+	}
+	if userLoyalty.Operation == "d" {
+		userLoyalty.ExpiresAt = l.pointsExpiresAt()
+	}
 	userLoyalty, err := l.loyalStorage.AddLoyalty(ctx, userLoyalty)
 	if err != nil {
 		if errors.Is(err, storage.ErrNegativeBalance) {
@@ -153,6 +252,21 @@ func (l *Loyalty) AddLoyalty(
 			log.Error("withdraw might lead to negative balance", "err", err.Error())
 			return nil, ErrUserNotFound
 		}
+		if errors.Is(err, storage.ErrIdempotencyKeyConflict) {
+			log.Warn("idempotency key reused", "err", err.Error())
+			return nil, ErrIdempotencyKeyConflict
+		}
+		if errors.Is(err, storage.ErrAccountFrozen) {
+			log.Warn("operation with frozen account", "err", err.Error())
+			return nil, ErrAccountFrozen
+		}
+		if errors.Is(err, storage.ErrAccountClosed) {
+			log.Warn("operation with closed account", "err", err.Error())
+			return nil, ErrAccountClosed
+		}
+		if errors.Is(err, storage.ErrLotsMismatch) {
+			return nil, l.lotsMismatch(op, span, err)
+		}
 		tracing.SpanError(span, "failed to get loyalty", err)
 		log.Error("failed to get loyalty", "err", err.Error())
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -165,3 +279,63 @@ func (l *Loyalty) AddLoyalty(
 		))
 	return userLoyalty, nil
 }
+
+// SaveRejectedLoyalty saves response to operation rejected by AddLoyalty with
+// its idempotency key, so retry gets the same response. Operations without
+// idempotency key are not saved.
+func (l *Loyalty) SaveRejectedLoyalty(
+	ctx context.Context,
+	userLoyalty *domain.UserLoyalty,
+) error {
+	const op = "SERVICE LAYER: SaveRejectedLoyalty"
+	ctx, span := tracer.Start(ctx, "service layer: SaveRejectedLoyalty",
+		trace.WithAttributes(attribute.String("handler", "SaveRejectedLoyalty")))
+	defer span.End()
+
+	if userLoyalty.IdempotencyKey == "" {
+		return nil
+	}
+	if userLoyalty.RequestHash == "" {
+		userLoyalty.RequestHash = requestHash(userLoyalty)
+	}
+	if err := l.loyalStorage.SaveRejectedLoyalty(ctx, userLoyalty); err != nil {
+		tracing.SpanError(span, "failed to save rejected operation", err)
+		l.log.Error("failed to save rejected operation", "err", err.Error())
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// ReplayDeadLetters publishes up to limit messages that failed to be processed
+// back to the original topic, so they are processed again.
+func (l *Loyalty) ReplayDeadLetters(ctx context.Context, limit int) (int, error) {
+	const op = "SERVICE LAYER: ReplayDeadLetters"
+	ctx, span := tracer.Start(ctx, "service layer: ReplayDeadLetters",
+		trace.WithAttributes(attribute.String("handler", "ReplayDeadLetters")))
+	defer span.End()
+
+	log := l.log.With(slog.String("info", op))
+	log.Info("replaying dead letters", "limit", limit)
+
+	replayed, err := l.loyalBroker.ReplayDeadLetters(ctx, limit)
+	if err != nil {
+		tracing.SpanError(span, "failed to replay dead letters", err)
+		log.Error("failed to replay dead letters", "err", err.Error(), "replayed", replayed)
+		return replayed, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("dead letters replayed", "replayed", replayed)
+	span.AddEvent(
+		"dead letters replayed",
+		trace.WithAttributes(attribute.Int("replayed", replayed)),
+	)
+	return replayed, nil
+}
+
+// requestHash returns hash of the operation requested with idempotency key.
+func requestHash(userLoyalty *domain.UserLoyalty) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf(
+		"%s|%s|%d|%s",
+		userLoyalty.UUID, userLoyalty.Operation, userLoyalty.Balance, userLoyalty.Comment,
+	)))
+	return hex.EncodeToString(sum[:])
+}
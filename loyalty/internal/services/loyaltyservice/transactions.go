@@ -0,0 +1,103 @@
+package loyaltyservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/storage"
+	"github.com/AlexBlackNn/authloyalty/loyalty/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GetTransactions returns a page of account transactions from newest to
+// oldest. Cursor of the next page is set if there are more transactions.
+func (l *Loyalty) GetTransactions(
+	ctx context.Context,
+	filter *domain.TransactionFilter,
+) (*domain.TransactionPage, error) {
+	const op = "SERVICE LAYER: GetTransactions"
+	ctx, span := tracer.Start(ctx, "service layer: GetTransactions",
+		trace.WithAttributes(attribute.String("handler", "GetTransactions")))
+	defer span.End()
+
+	log := l.log.With(
+		slog.String("info", op),
+		slog.String("user-id", filter.UUID),
+	)
+	log.Info("getting transactions")
+
+	// one more transaction is read to find out if there is a next page
+	pageFilter := *filter
+	pageFilter.Limit++
+	transactions, err := l.loyalStorage.GetTransactions(ctx, &pageFilter)
+	if err != nil {
+		tracing.SpanError(span, "failed to get transactions", err)
+		log.Error("failed to get transactions", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	page := &domain.TransactionPage{Transactions: transactions}
+	if len(transactions) > filter.Limit {
+		page.Transactions = transactions[:filter.Limit]
+		last := page.Transactions[filter.Limit-1]
+		page.Next = &domain.TransactionCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+	span.AddEvent(
+		"transactions extracted",
+		trace.WithAttributes(attribute.Int("transactions", len(page.Transactions))),
+	)
+	return page, nil
+}
+
+// ReverseTransaction undoes deposit or withdraw fully or partially. Returned
+// points expire like deposited ones.
+func (l *Loyalty) ReverseTransaction(
+	ctx context.Context,
+	reversal *domain.Reversal,
+) (*domain.Reversal, error) {
+	const op = "SERVICE LAYER: ReverseTransaction"
+	ctx, span := tracer.Start(ctx, "service layer: ReverseTransaction",
+		trace.WithAttributes(attribute.String("handler", "ReverseTransaction")))
+	defer span.End()
+
+	log := l.log.With(
+		slog.String("info", op),
+		slog.String("transaction-id", reversal.TransactionID),
+	)
+	log.Info("reversing transaction")
+
+	reversal.ExpiresAt = l.pointsExpiresAt()
+	reversal, err := l.loyalStorage.ReverseTransaction(ctx, reversal)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrTransactionNotFound):
+			return nil, ErrTransactionNotFound
+		case errors.Is(err, storage.ErrTransactionNotReversible):
+			return nil, ErrTransactionNotReversible
+		case errors.Is(err, storage.ErrReversalAmountExceeded):
+			return nil, ErrReversalAmountExceeded
+		case errors.Is(err, storage.ErrNegativeBalance):
+			return nil, ErrNegativeBalance
+		case errors.Is(err, storage.ErrAccountFrozen):
+			return nil, ErrAccountFrozen
+		case errors.Is(err, storage.ErrAccountClosed):
+			return nil, ErrAccountClosed
+		case errors.Is(err, storage.ErrLotsMismatch):
+			return nil, l.lotsMismatch(op, span, err)
+		}
+		tracing.SpanError(span, "failed to reverse transaction", err)
+		log.Error("failed to reverse transaction", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("transaction reversed", "reversal-id", reversal.ID, "amount", reversal.Amount)
+	span.AddEvent(
+		"transaction reversed",
+		trace.WithAttributes(
+			attribute.String("reversal-id", reversal.ID),
+			attribute.Int("amount", reversal.Amount),
+		))
+	return reversal, nil
+}
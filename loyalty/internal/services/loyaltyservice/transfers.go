@@ -0,0 +1,79 @@
+package loyaltyservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/storage"
+	"github.com/AlexBlackNn/authloyalty/loyalty/pkg/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Transfer moves points between accounts within Transfers.DailyLimit of the
+// sender. Received points expire like deposited ones.
+func (l *Loyalty) Transfer(
+	ctx context.Context,
+	transfer *domain.Transfer,
+) (*domain.Transfer, error) {
+	const op = "SERVICE LAYER: Transfer"
+	ctx, span := tracer.Start(ctx, "service layer: Transfer",
+		trace.WithAttributes(attribute.String("handler", "Transfer")))
+	defer span.End()
+
+	log := l.log.With(
+		slog.String("info", op),
+		slog.String("from", transfer.From),
+		slog.String("to", transfer.To),
+	)
+	log.Info("transferring points")
+
+	// the same account might be written in other case or format, uuids are
+	// canonicalized before they are compared and used as lock order
+	from, err := uuid.Parse(transfer.From)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	to, err := uuid.Parse(transfer.To)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	transfer.From, transfer.To = from.String(), to.String()
+	if transfer.From == transfer.To {
+		return nil, ErrSelfTransfer
+	}
+	transfer.DailyLimit = l.cfg.Transfers.DailyLimit
+	transfer.ExpiresAt = l.pointsExpiresAt()
+	transfer, err = l.loyalStorage.Transfer(ctx, transfer)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrUserNotFound):
+			return nil, ErrUserNotFound
+		case errors.Is(err, storage.ErrNegativeBalance):
+			return nil, ErrNegativeBalance
+		case errors.Is(err, storage.ErrTransferLimitExceeded):
+			return nil, ErrTransferLimitExceeded
+		case errors.Is(err, storage.ErrAccountFrozen):
+			return nil, ErrAccountFrozen
+		case errors.Is(err, storage.ErrAccountClosed):
+			return nil, ErrAccountClosed
+		case errors.Is(err, storage.ErrLotsMismatch):
+			return nil, l.lotsMismatch(op, span, err)
+		}
+		tracing.SpanError(span, "failed to transfer points", err)
+		log.Error("failed to transfer points", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("points transferred", "transfer-id", transfer.ID, "amount", transfer.Amount)
+	span.AddEvent(
+		"points transferred",
+		trace.WithAttributes(
+			attribute.String("transfer-id", transfer.ID),
+			attribute.Int("amount", transfer.Amount),
+		))
+	return transfer, nil
+}
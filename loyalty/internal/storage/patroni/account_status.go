@@ -0,0 +1,49 @@
+package patroni
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// UpdateAccountStatus freezes, unfreezes or closes loyalty account. Closed
+// account is never reopened. User might be blocked or deleted before the
+// account is created by registration event (e.g. email is not verified yet),
+// then the account is created with the status and registration adds bonus to it.
+func (s *Storage) UpdateAccountStatus(
+	ctx context.Context,
+	accountStatus *domain.AccountStatus,
+) error {
+	ctx, span := tracer.Start(
+		ctx, "data layer Patroni: UpdateAccountStatus",
+		trace.WithAttributes(attribute.String("handler", "UpdateAccountStatus")),
+	)
+	defer span.End()
+
+	tx, err := s.dbWrite.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf(
+			"DATA LAYER: storage.postgres.UpdateAccountStatus: failed to begin transaction: %w", err,
+		)
+	}
+	defer tx.Rollback()
+
+	if err = markEventProcessed(ctx, tx, accountStatus.EventID); err != nil {
+		return err
+	}
+	query := `INSERT INTO loyalty_app.accounts (uuid, balance, status, frozen_until)
+		VALUES ($1, 0, $2, $3)
+		ON CONFLICT (uuid) DO UPDATE
+		SET status = EXCLUDED.status, frozen_until = EXCLUDED.frozen_until, modified = CURRENT_TIMESTAMP
+		WHERE loyalty_app.accounts.status <> 'closed';`
+	_, err = tx.ExecContext(
+		ctx, query, accountStatus.UUID, accountStatus.Status, accountStatus.FrozenUntil,
+	)
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.UpdateAccountStatus: %w", err)
+	}
+	return tx.Commit()
+}
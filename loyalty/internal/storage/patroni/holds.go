@@ -0,0 +1,275 @@
+package patroni
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/storage"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CreateHold reserves hold.Amount points of active account until the hold is
+// captured, voided or expired. Reserved points stay in balance, but they are
+// not available for other operations.
+func (s *Storage) CreateHold(
+	ctx context.Context,
+	hold *domain.Hold,
+) (*domain.Hold, error) {
+	ctx, span := tracer.Start(
+		ctx, "data layer Patroni: CreateHold",
+		trace.WithAttributes(attribute.String("handler", "CreateHold")),
+	)
+	defer span.End()
+
+	tx, err := s.dbWrite.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"DATA LAYER: storage.postgres.CreateHold: failed to begin transaction: %w", err,
+		)
+	}
+	defer tx.Rollback()
+
+	if err = lockActiveAccount(ctx, tx, hold.UUID); err != nil {
+		return nil, err
+	}
+	// expired points can't be held, they are written off first
+	if _, err = expireLots(ctx, tx, hold.UUID, time.Now()); err != nil {
+		return nil, err
+	}
+	query := `UPDATE loyalty_app.accounts SET held = held + $1, modified = CURRENT_TIMESTAMP
+		WHERE uuid = $2 RETURNING balance, held;`
+	err = tx.QueryRowContext(ctx, query, hold.Amount, hold.UUID).Scan(&hold.Balance, &hold.Held)
+	if err != nil {
+		var pgerr *pgconn.PgError
+		if errors.As(err, &pgerr) && pgerr.Code == CheckViolationErr {
+			return nil, storage.ErrNegativeBalance
+		}
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.CreateHold: %w", err)
+	}
+	query = `INSERT INTO loyalty_app.holds (account_uuid, amount, comment, expires_at)
+		VALUES ($1, $2, $3, $4) RETURNING id, status, created_at;`
+	err = tx.QueryRowContext(ctx, query, hold.UUID, hold.Amount, hold.Comment, hold.ExpiresAt).
+		Scan(&hold.ID, &hold.Status, &hold.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.CreateHold: %w", err)
+	}
+	return hold, tx.Commit()
+}
+
+// CaptureHold withdraws captured amount of active hold from balance, the rest
+// of the hold is released. Withdraw is saved as a transaction of the hold.
+func (s *Storage) CaptureHold(
+	ctx context.Context,
+	action *domain.HoldAction,
+) (*domain.Hold, error) {
+	ctx, span := tracer.Start(
+		ctx, "data layer Patroni: CaptureHold",
+		trace.WithAttributes(attribute.String("handler", "CaptureHold")),
+	)
+	defer span.End()
+
+	tx, err := s.dbWrite.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"DATA LAYER: storage.postgres.CaptureHold: failed to begin transaction: %w", err,
+		)
+	}
+	defer tx.Rollback()
+
+	hold, err := lockHold(ctx, tx, action)
+	if err != nil {
+		return nil, err
+	}
+	// expired hold is released by expiration job
+	if !hold.ExpiresAt.After(time.Now()) {
+		return nil, storage.ErrHoldNotActive
+	}
+	hold.Captured = hold.Amount
+	if action.Amount > 0 {
+		hold.Captured = action.Amount
+	}
+	if hold.Captured > hold.Amount {
+		return nil, storage.ErrHoldAmountExceeded
+	}
+	if err = lockActiveAccount(ctx, tx, hold.UUID); err != nil {
+		return nil, err
+	}
+
+	query := `UPDATE loyalty_app.accounts
+		SET balance = balance - $1, held = held - $2, modified = CURRENT_TIMESTAMP
+		WHERE uuid = $3 RETURNING balance, held;`
+	err = tx.QueryRowContext(ctx, query, hold.Captured, hold.Amount, hold.UUID).
+		Scan(&hold.Balance, &hold.Held)
+	if err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.CaptureHold: %w", err)
+	}
+	query = "INSERT INTO loyalty_app.loyalty_transactions (account_uuid, transaction_amount, transaction_type, comment) VALUES ($1, $2, $3, $4) RETURNING id;"
+	err = tx.QueryRowContext(ctx, query, hold.UUID, hold.Captured, Withdraw, hold.Comment).
+		Scan(&hold.TransactionID)
+	if err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.CaptureHold: %w", err)
+	}
+	if err = withdrawHeldLots(ctx, tx, hold.UUID, hold.Captured); err != nil {
+		return nil, err
+	}
+
+	hold.Status = domain.HoldCaptured
+	query = `UPDATE loyalty_app.holds
+		SET status = $2, captured = $3, transaction_id = $4, modified = CURRENT_TIMESTAMP
+		WHERE id = $1;`
+	_, err = tx.ExecContext(ctx, query, hold.ID, hold.Status, hold.Captured, hold.TransactionID)
+	if err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.CaptureHold: %w", err)
+	}
+	return hold, tx.Commit()
+}
+
+// VoidHold releases points reserved by active hold.
+func (s *Storage) VoidHold(
+	ctx context.Context,
+	action *domain.HoldAction,
+) (*domain.Hold, error) {
+	ctx, span := tracer.Start(
+		ctx, "data layer Patroni: VoidHold",
+		trace.WithAttributes(attribute.String("handler", "VoidHold")),
+	)
+	defer span.End()
+	return s.releaseHold(ctx, action, domain.HoldVoided)
+}
+
+// ExpireHold releases points reserved by active hold id after it's expired.
+func (s *Storage) ExpireHold(ctx context.Context, id string) (*domain.Hold, error) {
+	ctx, span := tracer.Start(
+		ctx, "data layer Patroni: ExpireHold",
+		trace.WithAttributes(attribute.String("handler", "ExpireHold")),
+	)
+	defer span.End()
+	return s.releaseHold(ctx, &domain.HoldAction{ID: id}, domain.HoldExpired)
+}
+
+// GetExpiredHolds returns up to limit ids of active holds expired by now.
+func (s *Storage) GetExpiredHolds(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]string, error) {
+	ctx, span := tracer.Start(
+		ctx, "data layer Patroni: GetExpiredHolds",
+		trace.WithAttributes(attribute.String("handler", "GetExpiredHolds")),
+	)
+	defer span.End()
+
+	// master is used, replica might return holds already released
+	query := `SELECT id FROM loyalty_app.holds
+		WHERE status = 'active' AND expires_at <= $1 ORDER BY expires_at LIMIT $2;`
+	rows, err := s.dbWrite.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.GetExpiredHolds: %w", err)
+	}
+	defer rows.Close()
+
+	var holds []string
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("DATA LAYER: storage.postgres.GetExpiredHolds: %w", err)
+		}
+		holds = append(holds, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.GetExpiredHolds: %w", err)
+	}
+	return holds, nil
+}
+
+// releaseHold finishes active hold with status without withdraw, reserved
+// points become available again.
+func (s *Storage) releaseHold(
+	ctx context.Context,
+	action *domain.HoldAction,
+	status string,
+) (*domain.Hold, error) {
+	tx, err := s.dbWrite.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"DATA LAYER: storage.postgres.releaseHold: failed to begin transaction: %w", err,
+		)
+	}
+	defer tx.Rollback()
+
+	hold, err := lockHold(ctx, tx, action)
+	if err != nil {
+		return nil, err
+	}
+	query := `UPDATE loyalty_app.accounts SET held = held - $1, modified = CURRENT_TIMESTAMP
+		WHERE uuid = $2 RETURNING balance, held;`
+	err = tx.QueryRowContext(ctx, query, hold.Amount, hold.UUID).Scan(&hold.Balance, &hold.Held)
+	if err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.releaseHold: %w", err)
+	}
+	hold.Status = status
+	query = "UPDATE loyalty_app.holds SET status = $2, modified = CURRENT_TIMESTAMP WHERE id = $1;"
+	if _, err = tx.ExecContext(ctx, query, hold.ID, hold.Status); err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.releaseHold: %w", err)
+	}
+	return hold, tx.Commit()
+}
+
+// lockHold locks active hold of action in transaction tx. Hold is locked
+// before its account, so concurrent actions with the same hold are applied
+// one by one.
+func lockHold(
+	ctx context.Context,
+	tx *sql.Tx,
+	action *domain.HoldAction,
+) (*domain.Hold, error) {
+	hold := &domain.Hold{ID: action.ID}
+	query := `SELECT account_uuid, amount, status, comment, created_at, expires_at
+		FROM loyalty_app.holds WHERE id = $1 FOR UPDATE;`
+	err := tx.QueryRowContext(ctx, query, action.ID).Scan(
+		&hold.UUID, &hold.Amount, &hold.Status, &hold.Comment, &hold.CreatedAt, &hold.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrHoldNotFound
+		}
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.lockHold: %w", err)
+	}
+	// holds of other accounts are reported as missing
+	if action.UUID != "" && action.UUID != hold.UUID {
+		return nil, storage.ErrHoldNotFound
+	}
+	if hold.Status != domain.HoldActive {
+		return nil, storage.ErrHoldNotActive
+	}
+	return hold, nil
+}
+
+// lockActiveAccount locks account row in transaction tx, operations with
+// frozen and closed accounts are rejected.
+func lockActiveAccount(ctx context.Context, tx *sql.Tx, uuid string) error {
+	var closed, frozen bool
+	query := `SELECT status = 'closed',
+		status = 'frozen' AND (frozen_until IS NULL OR frozen_until > CURRENT_TIMESTAMP)
+		FROM loyalty_app.accounts WHERE uuid = $1 FOR UPDATE;`
+	err := tx.QueryRowContext(ctx, query, uuid).Scan(&closed, &frozen)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.ErrUserNotFound
+		}
+		return fmt.Errorf("DATA LAYER: storage.postgres.lockActiveAccount: %w", err)
+	}
+	if closed {
+		return storage.ErrAccountClosed
+	}
+	if frozen {
+		return storage.ErrAccountFrozen
+	}
+	return nil
+}
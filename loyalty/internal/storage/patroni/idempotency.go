@@ -0,0 +1,113 @@
+package patroni
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/storage"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// claimIdempotencyKey saves idempotency key of the operation in transaction tx.
+// If the key is already saved, result of the original operation is returned.
+// Concurrent request with the same key waits until the original transaction
+// is finished.
+func claimIdempotencyKey(
+	ctx context.Context,
+	tx *sql.Tx,
+	userLoyalty *domain.UserLoyalty,
+) (*domain.UserLoyalty, error) {
+	query := `INSERT INTO loyalty_app.idempotency_keys (requested_by, key, request_hash)
+		VALUES ($1, $2, $3) ON CONFLICT (requested_by, key) DO NOTHING;`
+	result, err := tx.ExecContext(
+		ctx, query, userLoyalty.RequestedBy, userLoyalty.IdempotencyKey, userLoyalty.RequestHash,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.claimIdempotencyKey: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.claimIdempotencyKey: %w", err)
+	}
+	if affected == 1 {
+		return nil, nil
+	}
+
+	var requestHash string
+	saved := &domain.UserLoyalty{
+		Operation:      userLoyalty.Operation,
+		Comment:        userLoyalty.Comment,
+		IdempotencyKey: userLoyalty.IdempotencyKey,
+		RequestedBy:    userLoyalty.RequestedBy,
+	}
+	// keys saved by instances not storing status yet were only saved on success,
+	// rejected operations have no account and balance
+	query = `SELECT request_hash, COALESCE(account_uuid::text, ''), COALESCE(balance, 0),
+			COALESCE(response_status, 200), COALESCE(response_error, '')
+		FROM loyalty_app.idempotency_keys
+		WHERE requested_by = $1 AND key = $2;`
+	err = tx.QueryRowContext(ctx, query, userLoyalty.RequestedBy, userLoyalty.IdempotencyKey).
+		Scan(&requestHash, &saved.UUID, &saved.Balance, &saved.ResponseStatus, &saved.ResponseError)
+	if err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.claimIdempotencyKey: %w", err)
+	}
+	if requestHash != userLoyalty.RequestHash {
+		return nil, storage.ErrIdempotencyKeyConflict
+	}
+	saved.RequestHash = requestHash
+	return saved, nil
+}
+
+// saveIdempotencyResult saves result of the operation and status of the
+// response to its idempotency key.
+func saveIdempotencyResult(
+	ctx context.Context,
+	tx *sql.Tx,
+	userLoyalty *domain.UserLoyalty,
+	transactionID string,
+) error {
+	query := `UPDATE loyalty_app.idempotency_keys
+		SET transaction_id = $3, account_uuid = $4, balance = $5, response_status = $6
+		WHERE requested_by = $1 AND key = $2;`
+	_, err := tx.ExecContext(
+		ctx, query,
+		userLoyalty.RequestedBy, userLoyalty.IdempotencyKey,
+		transactionID, userLoyalty.UUID, userLoyalty.Balance, userLoyalty.ResponseStatus,
+	)
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.saveIdempotencyResult: %w", err)
+	}
+	return nil
+}
+
+// SaveRejectedLoyalty saves response to the rejected operation with its
+// idempotency key, so repeated request gets the same response instead of
+// applying the operation again. Operation transaction is rolled back with the
+// key, so the response is saved in its own transaction. Key saved by a
+// concurrent request is kept.
+func (s *Storage) SaveRejectedLoyalty(
+	ctx context.Context,
+	userLoyalty *domain.UserLoyalty,
+) error {
+	ctx, span := tracer.Start(
+		ctx, "data layer Patroni: SaveRejectedLoyalty",
+		trace.WithAttributes(attribute.String("handler", "SaveRejectedLoyalty")),
+	)
+	defer span.End()
+
+	query := `INSERT INTO loyalty_app.idempotency_keys
+		(requested_by, key, request_hash, response_status, response_error)
+		VALUES ($1, $2, $3, $4, $5) ON CONFLICT (requested_by, key) DO NOTHING;`
+	_, err := s.dbWrite.ExecContext(
+		ctx, query,
+		userLoyalty.RequestedBy, userLoyalty.IdempotencyKey, userLoyalty.RequestHash,
+		userLoyalty.ResponseStatus, userLoyalty.ResponseError,
+	)
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.SaveRejectedLoyalty: %w", err)
+	}
+	return nil
+}
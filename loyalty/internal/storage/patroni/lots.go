@@ -0,0 +1,248 @@
+package patroni
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/storage"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// addLot tracks points deposited by transaction transactionID as a lot
+// expiring at expiresAt, nil expiresAt means points never expire.
+func addLot(
+	ctx context.Context,
+	tx *sql.Tx,
+	uuid string,
+	transactionID string,
+	amount int,
+	expiresAt *time.Time,
+) error {
+	query := `INSERT INTO loyalty_app.lots (account_uuid, transaction_id, amount, remaining, expires_at)
+		VALUES ($1, $2, $3, $3, $4);`
+	_, err := tx.ExecContext(ctx, query, uuid, transactionID, amount, expiresAt)
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.addLot: %w", err)
+	}
+	return nil
+}
+
+// consumeLots withdraws up to amount from account lots, lots expiring first
+// are consumed first. If expired is true, only lots expired by now are
+// consumed, otherwise only lots not expired by now. Account row must be locked
+// by tx, so lots are not changed concurrently. Consumed amount is returned.
+func consumeLots(
+	ctx context.Context,
+	tx *sql.Tx,
+	uuid string,
+	amount int,
+	now time.Time,
+	expired bool,
+) (int, error) {
+	query := `WITH open_lots AS (
+			SELECT id, remaining, SUM(remaining) OVER (
+				ORDER BY expires_at NULLS LAST, created_at, id
+			) - remaining AS consumed_before
+			FROM loyalty_app.lots
+			WHERE account_uuid = $1 AND remaining > 0
+				AND COALESCE(expires_at <= $3, FALSE) = $4
+		), consumed AS (
+			UPDATE loyalty_app.lots l
+			SET remaining = l.remaining - LEAST(o.remaining, $2 - o.consumed_before)
+			FROM open_lots o
+			WHERE l.id = o.id AND o.consumed_before < $2
+			RETURNING LEAST(o.remaining, $2 - o.consumed_before) AS amount
+		)
+		SELECT COALESCE(SUM(amount), 0) FROM consumed;`
+	var consumed int
+	err := tx.QueryRowContext(ctx, query, uuid, amount, now, expired).Scan(&consumed)
+	if err != nil {
+		return 0, fmt.Errorf("DATA LAYER: storage.postgres.consumeLots: %w", err)
+	}
+	return consumed, nil
+}
+
+// withdrawLots consumes exactly amount from not expired account lots. Expired
+// points must be written off by expireLots before balance is checked, then
+// lots sum equals available balance. If lots hold less than withdrawn from
+// balance storage.ErrLotsMismatch is returned, so the transaction is rolled back.
+func withdrawLots(ctx context.Context, tx *sql.Tx, uuid string, amount int) error {
+	consumed, err := consumeLots(ctx, tx, uuid, amount, time.Now(), false)
+	if err != nil {
+		return err
+	}
+	if consumed != amount {
+		return fmt.Errorf(
+			"DATA LAYER: storage.postgres.withdrawLots: account %s consumed %d of %d: %w",
+			uuid, consumed, amount, storage.ErrLotsMismatch,
+		)
+	}
+	return nil
+}
+
+// withdrawHeldLots consumes exactly amount from account lots, expired lots are
+// consumed first. Held points are not written off, so points held before their
+// lots expired are still withdrawn by capture. If lots hold less than withdrawn
+// from balance storage.ErrLotsMismatch is returned.
+func withdrawHeldLots(ctx context.Context, tx *sql.Tx, uuid string, amount int) error {
+	now := time.Now()
+	consumed, err := consumeLots(ctx, tx, uuid, amount, now, true)
+	if err != nil {
+		return err
+	}
+	if consumed == amount {
+		return nil
+	}
+	rest, err := consumeLots(ctx, tx, uuid, amount-consumed, now, false)
+	if err != nil {
+		return err
+	}
+	if consumed+rest != amount {
+		return fmt.Errorf(
+			"DATA LAYER: storage.postgres.withdrawHeldLots: account %s consumed %d of %d: %w",
+			uuid, consumed+rest, amount, storage.ErrLotsMismatch,
+		)
+	}
+	return nil
+}
+
+// expireLots writes off remaining points of account lots expired by now and
+// returns the written off amount. Balance is decreased and expire transaction
+// is saved in tx. Held points are not written off, they are written off after
+// hold is voided or expired. Account row must be locked by tx.
+func expireLots(ctx context.Context, tx *sql.Tx, uuid string, now time.Time) (int, error) {
+	var available int
+	query := "SELECT balance - held FROM loyalty_app.accounts WHERE uuid = $1;"
+	if err := tx.QueryRowContext(ctx, query, uuid).Scan(&available); err != nil {
+		return 0, fmt.Errorf("DATA LAYER: storage.postgres.expireLots: %w", err)
+	}
+	if available <= 0 {
+		return 0, nil
+	}
+	expired, err := consumeLots(ctx, tx, uuid, available, now, true)
+	if err != nil {
+		return 0, err
+	}
+	if expired == 0 {
+		return 0, nil
+	}
+
+	query = "UPDATE loyalty_app.accounts SET balance = balance - $1, modified = CURRENT_TIMESTAMP WHERE uuid = $2;"
+	if _, err = tx.ExecContext(ctx, query, expired, uuid); err != nil {
+		return 0, fmt.Errorf("DATA LAYER: storage.postgres.expireLots: %w", err)
+	}
+	query = "INSERT INTO loyalty_app.loyalty_transactions (account_uuid, transaction_amount, transaction_type, comment) VALUES ($1, $2, $3, $4);"
+	if _, err = tx.ExecContext(ctx, query, uuid, expired, Expire, "points expired"); err != nil {
+		return 0, fmt.Errorf("DATA LAYER: storage.postgres.expireLots: %w", err)
+	}
+	return expired, nil
+}
+
+// GetExpiringPoints returns amount of account points expiring before the time.
+func (s *Storage) GetExpiringPoints(
+	ctx context.Context,
+	uuid string,
+	before time.Time,
+) (int, error) {
+	ctx, span := tracer.Start(
+		ctx, "data layer Patroni: GetExpiringPoints",
+		trace.WithAttributes(attribute.String("handler", "GetExpiringPoints")),
+	)
+	defer span.End()
+
+	var expiring int
+	query := `SELECT COALESCE(SUM(remaining), 0) FROM loyalty_app.lots
+		WHERE account_uuid = $1 AND remaining > 0 AND expires_at < $2;`
+	err := s.dbRead.QueryRowContext(ctx, query, uuid, before).Scan(&expiring)
+	if err != nil {
+		return 0, fmt.Errorf("DATA LAYER: storage.postgres.GetExpiringPoints: %w", err)
+	}
+	return expiring, nil
+}
+
+// GetAccountsWithExpiredPoints returns up to limit active accounts having
+// lots expired by now and available points to write them off. Frozen and
+// closed accounts are skipped (see ExpirePoints), so they don't fill batches.
+func (s *Storage) GetAccountsWithExpiredPoints(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]string, error) {
+	ctx, span := tracer.Start(
+		ctx, "data layer Patroni: GetAccountsWithExpiredPoints",
+		trace.WithAttributes(attribute.String("handler", "GetAccountsWithExpiredPoints")),
+	)
+	defer span.End()
+
+	// master is used, replica might return accounts already processed
+	query := `SELECT DISTINCT l.account_uuid FROM loyalty_app.lots l
+		JOIN loyalty_app.accounts a ON a.uuid = l.account_uuid
+		WHERE l.remaining > 0 AND l.expires_at <= $1 AND a.balance - a.held > 0
+			AND a.status <> 'closed'
+			AND NOT (a.status = 'frozen' AND (a.frozen_until IS NULL OR a.frozen_until > $1))
+		LIMIT $2;`
+	rows, err := s.dbWrite.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.GetAccountsWithExpiredPoints: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []string
+	for rows.Next() {
+		var uuid string
+		if err = rows.Scan(&uuid); err != nil {
+			return nil, fmt.Errorf("DATA LAYER: storage.postgres.GetAccountsWithExpiredPoints: %w", err)
+		}
+		accounts = append(accounts, uuid)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.GetAccountsWithExpiredPoints: %w", err)
+	}
+	return accounts, nil
+}
+
+// ExpirePoints writes off remaining points of account lots expired by now
+// and returns the written off amount. Balance is decreased and expire
+// transaction is saved in the same transaction. Points of frozen and closed
+// accounts are not written off: they can't be spent, so they expire after
+// account is unfrozen, closed account keeps its balance as it was on closing.
+func (s *Storage) ExpirePoints(
+	ctx context.Context,
+	uuid string,
+	now time.Time,
+) (int, error) {
+	ctx, span := tracer.Start(
+		ctx, "data layer Patroni: ExpirePoints",
+		trace.WithAttributes(attribute.String("handler", "ExpirePoints")),
+	)
+	defer span.End()
+
+	tx, err := s.dbWrite.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf(
+			"DATA LAYER: storage.postgres.ExpirePoints: failed to begin transaction: %w", err,
+		)
+	}
+	defer tx.Rollback()
+
+	// account is locked, so withdraw doesn't consume lots being expired
+	err = lockActiveAccount(ctx, tx, uuid)
+	if errors.Is(err, storage.ErrAccountFrozen) || errors.Is(err, storage.ErrAccountClosed) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("DATA LAYER: storage.postgres.ExpirePoints: %w", err)
+	}
+	expired, err := expireLots(ctx, tx, uuid, now)
+	if err != nil {
+		return 0, err
+	}
+	if expired == 0 {
+		return 0, nil
+	}
+	return expired, tx.Commit()
+}
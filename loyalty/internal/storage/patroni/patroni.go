@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/AlexBlackNn/authloyalty/loyalty/internal/config"
 	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
@@ -24,6 +25,8 @@ type Storage struct {
 
 const (
 	Deposit           = "d"
+	Withdraw          = "w"
+	Expire            = "e"
 	CheckViolationErr = "23514"
 )
 
@@ -82,8 +85,10 @@ func (s *Storage) GetLoyalty(
 	)
 	defer span.End()
 
-	query := "SELECT balance FROM loyalty_app.accounts WHERE uuid = $1;"
-	err := s.dbRead.QueryRowContext(ctx, query, userLoyalty.UUID).Scan(&userLoyalty.Balance)
+	query := "SELECT balance, held FROM loyalty_app.accounts WHERE uuid = $1;"
+	err := s.dbRead.QueryRowContext(ctx, query, userLoyalty.UUID).Scan(
+		&userLoyalty.Balance, &userLoyalty.Held,
+	)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -117,12 +122,29 @@ func (s *Storage) AddLoyalty(
 	}
 	defer tx.Rollback()
 	fmt.Println("0000000000000000000000")
+	if err = markEventProcessed(ctx, tx, userLoyalty.EventID); err != nil {
+		return nil, err
+	}
+	if userLoyalty.IdempotencyKey != "" {
+		saved, err := claimIdempotencyKey(ctx, tx, userLoyalty)
+		if err != nil {
+			return nil, err
+		}
+		if saved != nil {
+			return saved, nil
+		}
+	}
 	balance := userLoyalty.Balance
 
 	//2. Block required row to avoid changing from other transactions
 	var userLoyaltyBlocked domain.UserLoyalty
-	query := "SELECT uuid, balance FROM loyalty_app.accounts WHERE uuid = $1 FOR UPDATE;"
-	err = tx.QueryRowContext(ctx, query, userLoyalty.UUID).Scan(&userLoyaltyBlocked.UUID, &userLoyaltyBlocked.Balance)
+	var closed, frozen bool
+	query := `SELECT uuid, balance, status = 'closed',
+		status = 'frozen' AND (frozen_until IS NULL OR frozen_until > CURRENT_TIMESTAMP)
+		FROM loyalty_app.accounts WHERE uuid = $1 FOR UPDATE;`
+	err = tx.QueryRowContext(ctx, query, userLoyalty.UUID).Scan(
+		&userLoyaltyBlocked.UUID, &userLoyaltyBlocked.Balance, &closed, &frozen,
+	)
 	fmt.Println("0101010101010", err, userLoyalty, userLoyaltyBlocked)
 	if err != nil {
 		//3. If no row is selected
@@ -135,10 +157,7 @@ func (s *Storage) AddLoyalty(
 				if err != nil {
 					return nil, err
 				}
-				query = "INSERT INTO loyalty_app.loyalty_transactions (account_uuid, transaction_amount, transaction_type, comment) VALUES ($1, $2, $3, $4);"
-				fmt.Println("11111111111111111111111111111111111111111111111111111111111", userLoyalty)
-				_, err = tx.ExecContext(ctx, query, userLoyalty.UUID, userLoyalty.Balance, Deposit, userLoyalty.Comment)
-				if err != nil {
+				if err = addRegistrationBonus(ctx, tx, userLoyalty); err != nil {
 					return nil, err
 				}
 				return userLoyalty, tx.Commit()
@@ -148,12 +167,44 @@ func (s *Storage) AddLoyalty(
 		return nil, storage.ErrUserNotFound
 	}
 
-	// 4. if user account exists, try to update account
+	// 3.3 account exists before registration if user was blocked or deleted
+	// before it (see UpdateAccountStatus). Registration event is applied once,
+	// so frozen account gets the bonus now, closed account gets no bonus.
+	// Registration without event id might be redelivered, it's rejected below.
+	if userLoyalty.Operation == "registration" && userLoyalty.EventID != "" {
+		if closed {
+			userLoyalty.Balance = userLoyaltyBlocked.Balance
+			return userLoyalty, tx.Commit()
+		}
+		query = "UPDATE loyalty_app.accounts SET balance = balance + $1 WHERE uuid = $2 RETURNING balance;"
+		err = tx.QueryRowContext(ctx, query, userLoyalty.Balance, userLoyalty.UUID).Scan(&userLoyaltyBlocked.Balance)
+		if err != nil {
+			return nil, fmt.Errorf("DATA LAYER: storage.postgres.AddLoyalty: %w", err)
+		}
+		if err = addRegistrationBonus(ctx, tx, userLoyalty); err != nil {
+			return nil, err
+		}
+		userLoyalty.Balance = userLoyaltyBlocked.Balance
+		return userLoyalty, tx.Commit()
+	}
+
+	// 4. if user account exists and is active, try to update account
+	if closed {
+		return nil, storage.ErrAccountClosed
+	}
+	if frozen {
+		return nil, storage.ErrAccountFrozen
+	}
 	if userLoyalty.Operation == "d" {
 		// 4.1 if deposit
 		query = "UPDATE loyalty_app.accounts SET balance = balance + $1 WHERE uuid = $2 RETURNING balance;"
 	} else if userLoyalty.Operation == "w" {
 		// 4.2 if withdraw
+		// expired points are not available, they are written off first
+		if _, err = expireLots(ctx, tx, userLoyalty.UUID, time.Now()); err != nil {
+			return nil, err
+		}
+		// held points are not available, available balance must not be negative
 		query = "UPDATE loyalty_app.accounts SET balance = balance - $1 WHERE uuid = $2 RETURNING balance;"
 	} else {
 		// 4.3 if other type of operations (i.e."registration" - to create exactly only once "registration" operation)
@@ -174,14 +225,70 @@ func (s *Storage) AddLoyalty(
 	}
 
 	// 5. Write data to account_transaction
-	query = "INSERT INTO loyalty_app.loyalty_transactions (account_uuid, transaction_amount, transaction_type, comment) VALUES ($1, $2, $3, $4);"
-	_, err = tx.ExecContext(ctx, query, userLoyalty.UUID, balance, userLoyalty.Operation, userLoyalty.Comment)
+	var transactionID string
+	query = "INSERT INTO loyalty_app.loyalty_transactions (account_uuid, transaction_amount, transaction_type, comment) VALUES ($1, $2, $3, $4) RETURNING id;"
+	err = tx.QueryRowContext(ctx, query, userLoyalty.UUID, balance, userLoyalty.Operation, userLoyalty.Comment).Scan(&transactionID)
+	if err != nil {
+		return nil, err
+	}
+	// 6. Deposit creates a lot, withdraw consumes lots expiring first
+	if userLoyalty.Operation == Deposit {
+		err = addLot(ctx, tx, userLoyalty.UUID, transactionID, balance, userLoyalty.ExpiresAt)
+	} else {
+		err = withdrawLots(ctx, tx, userLoyalty.UUID, balance)
+	}
 	if err != nil {
 		return nil, err
 	}
+	// 7. Save result to idempotency key, so repeated request gets it
+	if userLoyalty.IdempotencyKey != "" {
+		if err = saveIdempotencyResult(ctx, tx, userLoyalty, transactionID); err != nil {
+			return nil, err
+		}
+	}
 	return userLoyalty, tx.Commit()
 }
 
+// addRegistrationBonus saves registration bonus transaction and its lot,
+// account balance must already include the bonus. Registration without bonus
+// adds nothing, transaction amount must be positive.
+func addRegistrationBonus(ctx context.Context, tx *sql.Tx, userLoyalty *domain.UserLoyalty) error {
+	if userLoyalty.Balance <= 0 {
+		return nil
+	}
+	var transactionID string
+	query := "INSERT INTO loyalty_app.loyalty_transactions (account_uuid, transaction_amount, transaction_type, comment) VALUES ($1, $2, $3, $4) RETURNING id;"
+	err := tx.QueryRowContext(ctx, query, userLoyalty.UUID, userLoyalty.Balance, Deposit, userLoyalty.Comment).
+		Scan(&transactionID)
+	if err != nil {
+		return err
+	}
+	return addLot(ctx, tx, userLoyalty.UUID, transactionID, userLoyalty.Balance, userLoyalty.ExpiresAt)
+}
+
+// markEventProcessed saves id of event applied in transaction tx. Redelivered
+// event is a no-op, ErrEventProcessed is returned for it. Event is marked
+// processed in the same transaction, so it's not marked if the operation fails.
+func markEventProcessed(ctx context.Context, tx *sql.Tx, eventID string) error {
+	if eventID == "" {
+		return nil
+	}
+	query := `INSERT INTO loyalty_app.processed_events (event_id) VALUES ($1)
+		ON CONFLICT (event_id) DO NOTHING;`
+	result, err := tx.ExecContext(ctx, query, eventID)
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.markEventProcessed: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.markEventProcessed: %w", err)
+	}
+	if affected == 0 {
+		return storage.ErrEventProcessed
+	}
+	return nil
+}
+
 func (s *Storage) HealthCheck(ctx context.Context) error {
 	ctx, span := tracer.Start(ctx, "data layer Patroni: HealthCheck",
 		trace.WithAttributes(attribute.String("handler", "HealthCheck")))
@@ -0,0 +1,82 @@
+package patroni
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AddAccrual deposits points earned by purchase reduced to fit accrual caps.
+// Caps are checked under account lock, so concurrent purchases of the user
+// can't exceed them. Purchase is marked processed even if nothing is
+// deposited, ErrEventProcessed is returned for already processed purchase.
+func (s *Storage) AddAccrual(
+	ctx context.Context,
+	accrual *domain.Accrual,
+) (*domain.Accrual, error) {
+	ctx, span := tracer.Start(
+		ctx, "data layer Patroni: AddAccrual",
+		trace.WithAttributes(attribute.String("handler", "AddAccrual")),
+	)
+	defer span.End()
+
+	tx, err := s.dbWrite.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"DATA LAYER: storage.postgres.AddAccrual: failed to begin transaction: %w", err,
+		)
+	}
+	defer tx.Rollback()
+
+	if err = markEventProcessed(ctx, tx, accrual.EventID); err != nil {
+		return nil, err
+	}
+	if err = lockActiveAccount(ctx, tx, accrual.UUID); err != nil {
+		return nil, err
+	}
+
+	accrual.Deposited = accrual.Points
+	for _, earningCap := range accrual.Caps {
+		var earned int
+		query := `SELECT COALESCE(SUM(transaction_amount), 0) FROM loyalty_app.loyalty_transactions
+			WHERE account_uuid = $1 AND purchase_id IS NOT NULL
+				AND created_at > LOCALTIMESTAMP - make_interval(secs => $2);`
+		err = tx.QueryRowContext(ctx, query, accrual.UUID, earningCap.Period.Seconds()).Scan(&earned)
+		if err != nil {
+			return nil, fmt.Errorf("DATA LAYER: storage.postgres.AddAccrual: %w", err)
+		}
+		accrual.Deposited = max(min(accrual.Deposited, earningCap.Points-earned), 0)
+	}
+
+	// nothing is deposited if caps are reached, purchase is still processed
+	if accrual.Deposited == 0 {
+		query := "SELECT balance FROM loyalty_app.accounts WHERE uuid = $1;"
+		if err = tx.QueryRowContext(ctx, query, accrual.UUID).Scan(&accrual.Balance); err != nil {
+			return nil, fmt.Errorf("DATA LAYER: storage.postgres.AddAccrual: %w", err)
+		}
+		return accrual, tx.Commit()
+	}
+	query := "UPDATE loyalty_app.accounts SET balance = balance + $1, modified = CURRENT_TIMESTAMP WHERE uuid = $2 RETURNING balance;"
+	err = tx.QueryRowContext(ctx, query, accrual.Deposited, accrual.UUID).Scan(&accrual.Balance)
+	if err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.AddAccrual: %w", err)
+	}
+
+	query = `INSERT INTO loyalty_app.loyalty_transactions
+		(account_uuid, transaction_amount, transaction_type, comment, purchase_id)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id;`
+	err = tx.QueryRowContext(
+		ctx, query, accrual.UUID, accrual.Deposited, Deposit, accrual.Comment, accrual.PurchaseID,
+	).Scan(&accrual.TransactionID)
+	if err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.AddAccrual: %w", err)
+	}
+	err = addLot(ctx, tx, accrual.UUID, accrual.TransactionID, accrual.Deposited, accrual.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return accrual, tx.Commit()
+}
@@ -0,0 +1,140 @@
+package patroni
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/storage"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ReverseTransaction saves reversal of deposit or withdraw as the opposite
+// operation linked to the original transaction. Reversals of the transaction
+// are serialized by account lock, so their sum never exceeds its amount.
+func (s *Storage) ReverseTransaction(
+	ctx context.Context,
+	reversal *domain.Reversal,
+) (*domain.Reversal, error) {
+	ctx, span := tracer.Start(
+		ctx, "data layer Patroni: ReverseTransaction",
+		trace.WithAttributes(attribute.String("handler", "ReverseTransaction")),
+	)
+	defer span.End()
+
+	tx, err := s.dbWrite.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"DATA LAYER: storage.postgres.ReverseTransaction: failed to begin transaction: %w", err,
+		)
+	}
+	defer tx.Rollback()
+
+	var original domain.Transaction
+	var notReversible bool
+	// transfer is not reversed by a single side, it would break the pair
+	query := `SELECT account_uuid, transaction_amount, transaction_type,
+			reversal_of IS NOT NULL OR transfer_id IS NOT NULL
+		FROM loyalty_app.loyalty_transactions WHERE id = $1;`
+	err = tx.QueryRowContext(ctx, query, reversal.TransactionID).Scan(
+		&reversal.UUID, &original.Amount, &original.Type, &notReversible,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrTransactionNotFound
+		}
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.ReverseTransaction: %w", err)
+	}
+	if notReversible || (original.Type != Deposit && original.Type != Withdraw) {
+		return nil, storage.ErrTransactionNotReversible
+	}
+	if err = lockActiveAccount(ctx, tx, reversal.UUID); err != nil {
+		return nil, err
+	}
+
+	query = `SELECT COALESCE(SUM(transaction_amount), 0) FROM loyalty_app.loyalty_transactions
+		WHERE reversal_of = $1;`
+	if err = tx.QueryRowContext(ctx, query, reversal.TransactionID).Scan(&original.Reversed); err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.ReverseTransaction: %w", err)
+	}
+	reversible := original.Amount - original.Reversed
+	if reversal.Amount == 0 {
+		reversal.Amount = reversible
+	}
+	if reversible <= 0 || reversal.Amount > reversible {
+		return nil, storage.ErrReversalAmountExceeded
+	}
+
+	if original.Type == Deposit {
+		reversal.Type = Withdraw
+		query = "UPDATE loyalty_app.accounts SET balance = balance - $1, modified = CURRENT_TIMESTAMP WHERE uuid = $2 RETURNING balance;"
+	} else {
+		reversal.Type = Deposit
+		query = "UPDATE loyalty_app.accounts SET balance = balance + $1, modified = CURRENT_TIMESTAMP WHERE uuid = $2 RETURNING balance;"
+	}
+	err = tx.QueryRowContext(ctx, query, reversal.Amount, reversal.UUID).Scan(&reversal.Balance)
+	if err != nil {
+		var pgerr *pgconn.PgError
+		if errors.As(err, &pgerr) && pgerr.Code == CheckViolationErr {
+			return nil, storage.ErrNegativeBalance
+		}
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.ReverseTransaction: %w", err)
+	}
+
+	query = `INSERT INTO loyalty_app.loyalty_transactions
+		(account_uuid, transaction_amount, transaction_type, comment, reversal_of)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at;`
+	err = tx.QueryRowContext(
+		ctx, query, reversal.UUID, reversal.Amount, reversal.Type, reversal.Comment, reversal.TransactionID,
+	).Scan(&reversal.ID, &reversal.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.ReverseTransaction: %w", err)
+	}
+
+	// returned points are a new lot, taken back points are taken from the
+	// lot of reversed deposit first
+	if reversal.Type == Deposit {
+		err = addLot(ctx, tx, reversal.UUID, reversal.ID, reversal.Amount, reversal.ExpiresAt)
+	} else {
+		err = consumeDepositLot(ctx, tx, reversal.UUID, reversal.TransactionID, reversal.Amount)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return reversal, tx.Commit()
+}
+
+// consumeDepositLot withdraws amount from the lot of deposit transactionID,
+// the rest is withdrawn from other lots, expired lots first.
+func consumeDepositLot(
+	ctx context.Context,
+	tx *sql.Tx,
+	uuid string,
+	transactionID string,
+	amount int,
+) error {
+	var consumed int
+	query := `WITH consumed AS (
+			UPDATE loyalty_app.lots l
+			SET remaining = l.remaining - LEAST(old.remaining, $2)
+			FROM (
+				SELECT id, remaining FROM loyalty_app.lots
+				WHERE transaction_id = $1 AND remaining > 0
+			) old
+			WHERE l.id = old.id
+			RETURNING LEAST(old.remaining, $2) AS amount
+		)
+		SELECT COALESCE(SUM(amount), 0) FROM consumed;`
+	err := tx.QueryRowContext(ctx, query, transactionID, amount).Scan(&consumed)
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.consumeDepositLot: %w", err)
+	}
+	if consumed < amount {
+		return withdrawHeldLots(ctx, tx, uuid, amount-consumed)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package patroni
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GetTransactions returns up to filter.Limit account transactions from newest
+// to oldest, starting after filter.After. Replica is used, so the latest
+// transactions might be missing.
+func (s *Storage) GetTransactions(
+	ctx context.Context,
+	filter *domain.TransactionFilter,
+) ([]domain.Transaction, error) {
+	ctx, span := tracer.Start(
+		ctx, "data layer Patroni: GetTransactions",
+		trace.WithAttributes(attribute.String("handler", "GetTransactions")),
+	)
+	defer span.End()
+
+	conditions := []string{"account_uuid = $1"}
+	args := []any{filter.UUID}
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.From != nil {
+		addCondition("created_at >= $%d", filter.From.UTC())
+	}
+	if filter.To != nil {
+		addCondition("created_at < $%d", filter.To.UTC())
+	}
+	if filter.Type != "" {
+		addCondition("transaction_type = $%d", filter.Type)
+	}
+	if filter.MinAmount > 0 {
+		addCondition("transaction_amount >= $%d", filter.MinAmount)
+	}
+	if filter.MaxAmount > 0 {
+		addCondition("transaction_amount <= $%d", filter.MaxAmount)
+	}
+	if filter.After != nil {
+		args = append(args, filter.After.CreatedAt, filter.After.ID)
+		conditions = append(conditions, fmt.Sprintf(
+			"(created_at, id) < ($%d, $%d)", len(args)-1, len(args),
+		))
+	}
+	args = append(args, filter.Limit)
+	query := fmt.Sprintf(
+		`SELECT id, transaction_amount, transaction_type, comment, created_at,
+			COALESCE(reversal_of::text, ''), COALESCE(transfer_id::text, ''),
+			(SELECT COALESCE(SUM(r.transaction_amount), 0) FROM loyalty_app.loyalty_transactions r
+				WHERE r.reversal_of = t.id)
+		FROM loyalty_app.loyalty_transactions t WHERE %s
+		ORDER BY created_at DESC, id DESC LIMIT $%d;`,
+		strings.Join(conditions, " AND "), len(args),
+	)
+
+	rows, err := s.dbRead.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.GetTransactions: %w", err)
+	}
+	defer rows.Close()
+
+	var transactions []domain.Transaction
+	for rows.Next() {
+		var transaction domain.Transaction
+		err = rows.Scan(
+			&transaction.ID,
+			&transaction.Amount,
+			&transaction.Type,
+			&transaction.Comment,
+			&transaction.CreatedAt,
+			&transaction.ReversalOf,
+			&transaction.TransferID,
+			&transaction.Reversed,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("DATA LAYER: storage.postgres.GetTransactions: %w", err)
+		}
+		transactions = append(transactions, transaction)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.GetTransactions: %w", err)
+	}
+	return transactions, nil
+}
@@ -0,0 +1,107 @@
+package patroni
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/storage"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Transfer withdraws points from transfer.From and deposits them to
+// transfer.To in a single transaction. Both accounts are locked in uuid order,
+// so concurrent transfers between the same accounts in opposite directions
+// don't deadlock. Transfers of the sender are serialized by its lock, so
+// daily limit can't be exceeded by concurrent transfers.
+func (s *Storage) Transfer(
+	ctx context.Context,
+	transfer *domain.Transfer,
+) (*domain.Transfer, error) {
+	ctx, span := tracer.Start(
+		ctx, "data layer Patroni: Transfer",
+		trace.WithAttributes(attribute.String("handler", "Transfer")),
+	)
+	defer span.End()
+
+	tx, err := s.dbWrite.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"DATA LAYER: storage.postgres.Transfer: failed to begin transaction: %w", err,
+		)
+	}
+	defer tx.Rollback()
+
+	// uuids are canonical (see loyaltyservice.Transfer), so string order is
+	// the same for any transfer between the accounts
+	first, second := transfer.From, transfer.To
+	if second < first {
+		first, second = second, first
+	}
+	for _, uuid := range []string{first, second} {
+		if err = lockActiveAccount(ctx, tx, uuid); err != nil {
+			return nil, err
+		}
+	}
+
+	if transfer.DailyLimit > 0 {
+		var sent int
+		query := `SELECT COALESCE(SUM(transaction_amount), 0) FROM loyalty_app.loyalty_transactions
+			WHERE account_uuid = $1 AND transfer_id IS NOT NULL AND transaction_type = 'w'
+				AND created_at > LOCALTIMESTAMP - INTERVAL '1 day';`
+		if err = tx.QueryRowContext(ctx, query, transfer.From).Scan(&sent); err != nil {
+			return nil, fmt.Errorf("DATA LAYER: storage.postgres.Transfer: %w", err)
+		}
+		if sent+transfer.Amount > transfer.DailyLimit {
+			return nil, storage.ErrTransferLimitExceeded
+		}
+	}
+
+	// expired points of the sender are not available, they are written off first
+	if _, err = expireLots(ctx, tx, transfer.From, time.Now()); err != nil {
+		return nil, err
+	}
+	query := "UPDATE loyalty_app.accounts SET balance = balance - $1, modified = CURRENT_TIMESTAMP WHERE uuid = $2 RETURNING balance;"
+	err = tx.QueryRowContext(ctx, query, transfer.Amount, transfer.From).Scan(&transfer.Balance)
+	if err != nil {
+		var pgerr *pgconn.PgError
+		if errors.As(err, &pgerr) && pgerr.Code == CheckViolationErr {
+			return nil, storage.ErrNegativeBalance
+		}
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.Transfer: %w", err)
+	}
+	query = "UPDATE loyalty_app.accounts SET balance = balance + $1, modified = CURRENT_TIMESTAMP WHERE uuid = $2;"
+	if _, err = tx.ExecContext(ctx, query, transfer.Amount, transfer.To); err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.Transfer: %w", err)
+	}
+
+	query = `INSERT INTO loyalty_app.loyalty_transactions
+		(account_uuid, transaction_amount, transaction_type, comment, transfer_id)
+		VALUES ($1, $2, $3, $4, gen_random_uuid()) RETURNING transfer_id, created_at;`
+	err = tx.QueryRowContext(ctx, query, transfer.From, transfer.Amount, Withdraw, transfer.Comment).
+		Scan(&transfer.ID, &transfer.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.Transfer: %w", err)
+	}
+	var depositID string
+	query = `INSERT INTO loyalty_app.loyalty_transactions
+		(account_uuid, transaction_amount, transaction_type, comment, transfer_id)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id;`
+	err = tx.QueryRowContext(ctx, query, transfer.To, transfer.Amount, Deposit, transfer.Comment, transfer.ID).
+		Scan(&depositID)
+	if err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.Transfer: %w", err)
+	}
+
+	if err = withdrawLots(ctx, tx, transfer.From, transfer.Amount); err != nil {
+		return nil, err
+	}
+	if err = addLot(ctx, tx, transfer.To, depositID, transfer.Amount, transfer.ExpiresAt); err != nil {
+		return nil, err
+	}
+	return transfer, tx.Commit()
+}
@@ -8,4 +8,27 @@ var (
 	ErrConnection      = errors.New("no connection")
 	ErrNegativeBalance = errors.New("negative balance")
 	ErrInternalErr     = errors.New("internal error")
+	ErrEventProcessed  = errors.New("event already processed")
+	ErrAccountFrozen   = errors.New("account is frozen")
+	ErrAccountClosed   = errors.New("account is closed")
+	// ErrIdempotencyKeyConflict is returned if idempotency key is already used
+	// by other operation.
+	ErrIdempotencyKeyConflict = errors.New("idempotency key used by other operation")
+	ErrHoldNotFound           = errors.New("hold not found")
+	ErrHoldNotActive          = errors.New("hold is not active")
+	// ErrHoldAmountExceeded is returned if captured amount exceeds held amount.
+	ErrHoldAmountExceeded  = errors.New("captured amount exceeds hold")
+	ErrTransactionNotFound = errors.New("transaction not found")
+	// ErrTransactionNotReversible is returned for reversals and operations
+	// other than deposit and withdraw.
+	ErrTransactionNotReversible = errors.New("transaction can't be reversed")
+	// ErrReversalAmountExceeded is returned if reversals exceed amount of the
+	// original transaction.
+	ErrReversalAmountExceeded = errors.New("reversals exceed transaction amount")
+	// ErrTransferLimitExceeded is returned if sender exceeds daily transfer
+	// limit.
+	ErrTransferLimitExceeded = errors.New("daily transfer limit exceeded")
+	// ErrLotsMismatch is returned if account lots hold fewer points than
+	// withdrawn from balance, the operation is rolled back.
+	ErrLotsMismatch = errors.New("account lots don't match balance")
 )
@@ -2,8 +2,12 @@ package broker
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	log "log/slog"
+	"time"
 
+	eventsv1 "github.com/AlexBlackNn/authloyalty/commands/proto/events.v1/events.v1"
 	registrationv1 "github.com/AlexBlackNn/authloyalty/commands/proto/registration.v1/registration.v1"
 	"github.com/AlexBlackNn/authloyalty/loyalty/internal/config"
 	"github.com/confluentinc/confluent-kafka-go/schemaregistry"
@@ -15,26 +19,42 @@ import (
 	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
 )
 
 type Message struct {
-	UUID    string
-	Balance int
-	Type    string
-	Comment string
+	// EventID is unique for every event, it's the same if event is re-sent or
+	// redelivered.
+	EventID    string
+	UUID       string
+	Balance    int
+	Type       string
+	Comment    string
+	OccurredAt time.Time
+	// Payload is typed payload of user events, it's nil for registration.
+	Payload proto.Message
 }
 
 type MessageReceived struct {
 	Msg Message
 	Ctx context.Context
 	Err error
+	// Result receives result of message processing. Offset is committed only
+	// if message is processed successfully, otherwise it's consumed again.
+	Result chan error
 }
 
 type Broker struct {
-	consumer     *kafka.Consumer
+	cfg      *config.Config
+	consumer *kafka.Consumer
+	// producer publishes messages to dead-letter topic and replays them.
+	producer     *kafka.Producer
 	deserializer serde.Deserializer
 	MessageChan  chan *MessageReceived
 	workEnable   bool
+	// attempts counts failed processing of messages by event id, it's
+	// accessed only by Consume.
+	attempts map[string]int
 }
 
 var tracer = otel.Tracer(
@@ -46,9 +66,19 @@ var tracer = otel.Tracer(
 func New(cfg *config.Config) (*Broker, error) {
 	confluentConsumer, err := kafka.NewConsumer(&kafka.ConfigMap{
 		"bootstrap.servers":  cfg.Kafka.KafkaURL,
-		"group.id":           "1",
+		"group.id":           cfg.Kafka.GroupID,
 		"session.timeout.ms": 6000,
-		"auto.offset.reset":  "earliest"})
+		"auto.offset.reset":  "earliest",
+		// offset is committed after message is persisted
+		"enable.auto.commit": false})
+	if err != nil {
+		return nil, err
+	}
+
+	producer, err := kafka.NewProducer(&kafka.ConfigMap{
+		"bootstrap.servers": cfg.Kafka.KafkaURL,
+		"acks":              "all",
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -66,13 +96,19 @@ func New(cfg *config.Config) (*Broker, error) {
 	}
 
 	deser.ProtoRegistry.RegisterMessage((&registrationv1.RegistrationMessage{}).ProtoReflect().Type())
-	//TODO: registration should be got from config
-	err = confluentConsumer.Subscribe("registration", nil)
+	deser.ProtoRegistry.RegisterMessage((&eventsv1.UserEvent{}).ProtoReflect().Type())
+	err = confluentConsumer.SubscribeTopics([]string{cfg.Kafka.Topic, cfg.Kafka.UserEventsTopic}, nil)
+	if err != nil {
+		return nil, err
+	}
 
 	broker := &Broker{
+		cfg:          cfg,
 		consumer:     confluentConsumer,
+		producer:     producer,
 		deserializer: deser,
 		MessageChan:  MessageChan,
+		attempts:     make(map[string]int),
 	}
 	broker.workEnable = true
 	go broker.Consume()
@@ -93,6 +129,7 @@ func (b *Broker) Close() error {
 	b.deserializer.Close()
 	//https://docs.confluent.io/platform/current/clients/confluent-kafka-go/index.html#hdr-High_level_Consumer
 	err := b.consumer.Close()
+	b.producer.Close()
 	if err != nil {
 		return err
 	}
@@ -101,8 +138,7 @@ func (b *Broker) Close() error {
 
 func (b *Broker) Consume() {
 	for b.workEnable {
-		//TODO: get from config
-		ev := b.consumer.Poll(100)
+		ev := b.consumer.Poll(b.cfg.Kafka.PollTimeoutMs)
 		if ev == nil {
 			continue
 		}
@@ -113,15 +149,14 @@ func (b *Broker) Consume() {
 			ctx, span := tracer.Start(ctx, "kafka_message_processing")
 			defer span.End()
 
-			var msg registrationv1.RegistrationMessage
-
-			err := b.deserializer.DeserializeInto(*e.TopicPartition.Topic, e.Value, &msg)
-
+			msg, err := b.decode(e)
 			if err != nil {
 				log.Error("Failed to deserialize payload: %s\n", err)
+				// message can't be processed whatever times it's consumed
+				b.commitOrRewind(e, fmt.Errorf("failed to deserialize payload: %w", err), b.cfg.Kafka.MaxAttempts)
 				continue
 			} else {
-				log.Info("%% Message on %s:\n%+v\n", e.TopicPartition, msg)
+				log.Info("message received", "event-id", msg.EventID, "type", msg.Type)
 			}
 
 			if e.Headers != nil {
@@ -139,14 +174,15 @@ func (b *Broker) Consume() {
 					"tracer consumer1",
 					trace.WithSpanKind(trace.SpanKindConsumer),
 					trace.WithAttributes(
-						semconv.MessagingDestinationName("registration"),
+						semconv.MessagingDestinationName(*e.TopicPartition.Topic),
 					),
 				)
 				defer span.End()
 			}
 
-			// TODO: Balance might be transmitted from sso and extracted from protobuf, Err - take a look at docs to find out.
-			b.MessageChan <- &MessageReceived{Msg: Message{UUID: string(e.Key), Balance: 100, Comment: msg.Type, Type: msg.Type}, Ctx: ctx, Err: nil}
+			result := make(chan error, 1)
+			b.MessageChan <- &MessageReceived{Msg: msg, Ctx: ctx, Err: nil, Result: result}
+			b.commitOrRewind(e, <-result, 1)
 
 		case kafka.Error:
 			// Errors should generally be considered
@@ -158,3 +194,88 @@ func (b *Broker) Consume() {
 		}
 	}
 }
+
+// ErrNegativeBonus is returned for registration event with negative bonus,
+// such event can't be applied and goes to dead-letter topic.
+var ErrNegativeBonus = errors.New("registration bonus is negative")
+
+// RegistrationMessage returns message for registration event. Producers of
+// schema version 0 send uuid and type only, then event id is taken from kafka
+// message position and defaultBonus is granted.
+func RegistrationMessage(
+	e *kafka.Message,
+	msg *registrationv1.RegistrationMessage,
+	defaultBonus int,
+) (Message, error) {
+	message := Message{
+		EventID: msg.GetEventId(),
+		UUID:    string(e.Key),
+		Balance: int(msg.GetBonus()),
+		Type:    msg.GetType(),
+		Comment: msg.GetCampaign(),
+	}
+	if message.EventID == "" {
+		message.EventID = eventID(e.TopicPartition)
+	}
+	if msg.GetSchemaVersion() == 0 {
+		message.Balance = defaultBonus
+	}
+	if message.Balance < 0 {
+		return Message{}, fmt.Errorf("event %s: %w", message.EventID, ErrNegativeBonus)
+	}
+	if message.Comment == "" {
+		message.Comment = msg.GetType()
+	}
+	if msg.GetOccurredAt() != nil {
+		message.OccurredAt = msg.GetOccurredAt().AsTime()
+	} else {
+		message.OccurredAt = e.Timestamp
+	}
+	return message, nil
+}
+
+// eventID returns topic/partition/offset of the message.
+func eventID(tp kafka.TopicPartition) string {
+	var topic string
+	if tp.Topic != nil {
+		topic = *tp.Topic
+	}
+	return fmt.Sprintf("%s/%d/%d", topic, tp.Partition, tp.Offset)
+}
+
+// commitOrRewind commits offset of processed message. If processing failed,
+// partition is rewound to the message, so it's consumed again after delay.
+// After MaxAttempts failures message is moved to dead-letter topic and its
+// offset is committed. cost is how many attempts the failure uses up.
+func (b *Broker) commitOrRewind(msg *kafka.Message, processingErr error, cost int) {
+	id := eventID(msg.TopicPartition)
+	if processingErr != nil {
+		b.attempts[id] += cost
+		if b.attempts[id] < b.cfg.Kafka.MaxAttempts {
+			b.rewind(msg, processingErr)
+			return
+		}
+		if err := b.moveToDeadLetter(msg, processingErr, b.attempts[id]); err != nil {
+			log.Error("failed to move message to dead-letter topic", "err", err.Error())
+			b.rewind(msg, processingErr)
+			return
+		}
+	}
+	delete(b.attempts, id)
+	if _, err := b.consumer.CommitMessage(msg); err != nil {
+		log.Error("failed to commit offset", "err", err.Error())
+	}
+}
+
+// rewind seeks partition back to the message, so it's consumed again after delay.
+func (b *Broker) rewind(msg *kafka.Message, processingErr error) {
+	log.Error(
+		"failed to process message, it will be consumed again",
+		"err", processingErr.Error(),
+		"partition", msg.TopicPartition.String(),
+	)
+	if err := b.consumer.Seek(msg.TopicPartition, 0); err != nil {
+		log.Error("failed to rewind partition", "err", err.Error())
+	}
+	time.Sleep(b.cfg.Kafka.RetryDelay)
+}
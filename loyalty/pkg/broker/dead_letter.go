@@ -0,0 +1,159 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	log "log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+)
+
+// Headers added to messages moved to dead-letter topic.
+const (
+	HeaderDeadLetterError     = "dlq-error"
+	HeaderDeadLetterAttempts  = "dlq-attempts"
+	HeaderDeadLetterTopic     = "dlq-original-topic"
+	HeaderDeadLetterPartition = "dlq-original-partition"
+	HeaderDeadLetterOffset    = "dlq-original-offset"
+
+	deadLetterHeaderPrefix = "dlq-"
+	// replayIdleTimeout is how long dead-letter topic is read before replay
+	// decides there are no messages left.
+	replayIdleTimeout = time.Second
+)
+
+// DeadLetter returns copy of message to be published to dead-letter topic.
+// Key, value and headers of the original message are kept, the reason and the
+// original position are added as headers.
+func DeadLetter(msg *kafka.Message, topic string, reason error, attempts int) *kafka.Message {
+	var originalTopic string
+	if msg.TopicPartition.Topic != nil {
+		originalTopic = *msg.TopicPartition.Topic
+	}
+	headers := make([]kafka.Header, 0, len(msg.Headers)+5)
+	headers = append(headers, msg.Headers...)
+	headers = append(headers,
+		kafka.Header{Key: HeaderDeadLetterError, Value: []byte(reason.Error())},
+		kafka.Header{Key: HeaderDeadLetterAttempts, Value: []byte(strconv.Itoa(attempts))},
+		kafka.Header{Key: HeaderDeadLetterTopic, Value: []byte(originalTopic)},
+		kafka.Header{Key: HeaderDeadLetterPartition, Value: []byte(strconv.Itoa(int(msg.TopicPartition.Partition)))},
+		kafka.Header{Key: HeaderDeadLetterOffset, Value: []byte(msg.TopicPartition.Offset.String())},
+	)
+	return &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+		Key:            msg.Key,
+		Value:          msg.Value,
+		Headers:        headers,
+	}
+}
+
+// Replayed returns copy of dead letter to be published to its original topic,
+// headers added by DeadLetter are removed. fallbackTopic is used if the
+// original topic is unknown.
+func Replayed(msg *kafka.Message, fallbackTopic string) *kafka.Message {
+	topic := fallbackTopic
+	headers := make([]kafka.Header, 0, len(msg.Headers))
+	for _, header := range msg.Headers {
+		if header.Key == HeaderDeadLetterTopic && len(header.Value) > 0 {
+			topic = string(header.Value)
+		}
+		if strings.HasPrefix(header.Key, deadLetterHeaderPrefix) {
+			continue
+		}
+		headers = append(headers, header)
+	}
+	return &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+		Key:            msg.Key,
+		Value:          msg.Value,
+		Headers:        headers,
+	}
+}
+
+// moveToDeadLetter publishes message to dead-letter topic and waits until it's
+// delivered, so offset of the original message can be committed.
+func (b *Broker) moveToDeadLetter(msg *kafka.Message, reason error, attempts int) error {
+	err := b.produceSync(DeadLetter(msg, b.cfg.Kafka.DeadLetterTopic, reason, attempts))
+	if err != nil {
+		return err
+	}
+	log.Warn(
+		"message moved to dead-letter topic",
+		"event-id", eventID(msg.TopicPartition),
+		"attempts", attempts,
+		"reason", reason.Error(),
+	)
+	return nil
+}
+
+// produceSync publishes message and waits for delivery report.
+func (b *Broker) produceSync(msg *kafka.Message) error {
+	deliveryChan := make(chan kafka.Event, 1)
+	if err := b.producer.Produce(msg, deliveryChan); err != nil {
+		return fmt.Errorf("failed to produce message: %w", err)
+	}
+	ev := <-deliveryChan
+	delivered, ok := ev.(*kafka.Message)
+	if !ok {
+		return fmt.Errorf("unexpected delivery event: %v", ev)
+	}
+	if delivered.TopicPartition.Error != nil {
+		return fmt.Errorf("failed to deliver message: %w", delivered.TopicPartition.Error)
+	}
+	return nil
+}
+
+// ReplayDeadLetters publishes up to limit messages from dead-letter topic back
+// to their original topic, where they are consumed as new events. Replay stops
+// earlier if dead-letter topic has no more messages or ctx is done. Replayed
+// messages are committed in a separate consumer group, so a dead letter is
+// replayed only once.
+func (b *Broker) ReplayDeadLetters(ctx context.Context, limit int) (int, error) {
+	replayConsumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers":  b.cfg.Kafka.KafkaURL,
+		"group.id":           b.cfg.Kafka.GroupID + "-dlq-replay",
+		"session.timeout.ms": 6000,
+		"auto.offset.reset":  "earliest",
+		"enable.auto.commit": false})
+	if err != nil {
+		return 0, err
+	}
+	defer replayConsumer.Close()
+
+	if err = replayConsumer.Subscribe(b.cfg.Kafka.DeadLetterTopic, nil); err != nil {
+		return 0, err
+	}
+
+	var replayed int
+	for replayed < limit {
+		if err = ctx.Err(); err != nil {
+			return replayed, err
+		}
+		msg, err := replayConsumer.ReadMessage(replayIdleTimeout)
+		if err != nil {
+			var kafkaErr kafka.Error
+			if errors.As(err, &kafkaErr) && kafkaErr.IsTimeout() {
+				// partitions are assigned after consumer joins group, until
+				// then empty read doesn't mean there are no dead letters
+				assignment, err := replayConsumer.Assignment()
+				if err == nil && len(assignment) > 0 {
+					break
+				}
+				continue
+			}
+			return replayed, err
+		}
+		if err = b.produceSync(Replayed(msg, b.cfg.Kafka.Topic)); err != nil {
+			return replayed, err
+		}
+		if _, err = replayConsumer.CommitMessage(msg); err != nil {
+			return replayed, err
+		}
+		replayed++
+	}
+	return replayed, nil
+}
@@ -0,0 +1,73 @@
+package broker
+
+import (
+	eventsv1 "github.com/AlexBlackNn/authloyalty/commands/proto/events.v1/events.v1"
+	registrationv1 "github.com/AlexBlackNn/authloyalty/commands/proto/registration.v1/registration.v1"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"google.golang.org/protobuf/proto"
+)
+
+// Types of messages, service dispatches messages to handlers by type.
+const (
+	TypeRegistration  = "registration"
+	TypeUserDeleted   = "user_deleted"
+	TypeEmailChanged  = "email_changed"
+	TypeRoleChanged   = "role_changed"
+	TypeUserBlocked   = "user_blocked"
+	TypeUserUnblocked = "user_unblocked"
+)
+
+// decode deserializes kafka message according to the topic it's read from.
+func (b *Broker) decode(e *kafka.Message) (Message, error) {
+	topic := *e.TopicPartition.Topic
+	if topic == b.cfg.Kafka.UserEventsTopic {
+		var event eventsv1.UserEvent
+		if err := b.deserializer.DeserializeInto(topic, e.Value, &event); err != nil {
+			return Message{}, err
+		}
+		return UserEventMessage(e, &event), nil
+	}
+	var msg registrationv1.RegistrationMessage
+	if err := b.deserializer.DeserializeInto(topic, e.Value, &msg); err != nil {
+		return Message{}, err
+	}
+	return RegistrationMessage(e, &msg, b.cfg.RegistrationBonus)
+}
+
+// UserEventMessage returns message for user event envelope, typed payload of
+// the event is kept in Payload. Type is empty if payload is unknown, i.e.
+// added to the envelope by a newer producer.
+func UserEventMessage(e *kafka.Message, event *eventsv1.UserEvent) Message {
+	message := Message{
+		EventID: event.GetEventId(),
+		UUID:    event.GetUserId(),
+	}
+	if message.EventID == "" {
+		message.EventID = eventID(e.TopicPartition)
+	}
+	if message.UUID == "" {
+		message.UUID = string(e.Key)
+	}
+	if event.GetOccurredAt() != nil {
+		message.OccurredAt = event.GetOccurredAt().AsTime()
+	} else {
+		message.OccurredAt = e.Timestamp
+	}
+
+	var payload proto.Message
+	switch p := event.GetPayload().(type) {
+	case *eventsv1.UserEvent_UserDeleted:
+		message.Type, payload = TypeUserDeleted, p.UserDeleted
+	case *eventsv1.UserEvent_EmailChanged:
+		message.Type, payload = TypeEmailChanged, p.EmailChanged
+	case *eventsv1.UserEvent_RoleChanged:
+		message.Type, payload = TypeRoleChanged, p.RoleChanged
+	case *eventsv1.UserEvent_UserBlocked:
+		message.Type, payload = TypeUserBlocked, p.UserBlocked
+	case *eventsv1.UserEvent_UserUnblocked:
+		message.Type, payload = TypeUserUnblocked, p.UserUnblocked
+	}
+	message.Payload = payload
+	message.Comment = message.Type
+	return message
+}
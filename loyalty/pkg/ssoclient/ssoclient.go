@@ -9,7 +9,9 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
 type SSOClient struct {
@@ -29,26 +31,20 @@ func New(cfg *config.Config) (*SSOClient, error) {
 	return &SSOClient{AuthClient: authClient}, nil
 }
 
-func (sc *SSOClient) IsJWTValid(ctx context.Context, tracer trace.Tracer, token string) bool {
-	ctx, span := tracer.Start(ctx, "sso client: IsJWTValid",
-		trace.WithAttributes(attribute.String("operation", "IsJWTValid")))
+// CheckJWT asks sso whether token is still valid, i.e. it has not been revoked.
+// Error is returned only if sso can't answer.
+func (sc *SSOClient) CheckJWT(ctx context.Context, tracer trace.Tracer, token string) (bool, error) {
+	ctx, span := tracer.Start(ctx, "sso client: CheckJWT",
+		trace.WithAttributes(attribute.String("operation", "CheckJWT")))
 	defer span.End()
 
 	respIsValid, err := sc.AuthClient.Validate(ctx, &ssov1.ValidateRequest{Token: token})
 	if err != nil {
-		return false
+		switch status.Code(err) {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+			return false, err
+		}
+		return false, nil
 	}
-	return respIsValid.GetSuccess()
-}
-
-func (sc *SSOClient) IsAdmin(ctx context.Context, tracer trace.Tracer, uuid string) bool {
-	ctx, span := tracer.Start(ctx, "sso client: IsAdmin",
-		trace.WithAttributes(attribute.String("operation", "IsAdmin")))
-	defer span.End()
-
-	respIsValid, err := sc.AuthClient.IsAdmin(ctx, &ssov1.IsAdminRequest{UserId: uuid})
-	if err != nil {
-		return false
-	}
-	return respIsValid.GetIsAdmin()
+	return respIsValid.GetSuccess(), nil
 }
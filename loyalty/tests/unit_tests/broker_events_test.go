@@ -0,0 +1,301 @@
+package unit_tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	eventsv1 "github.com/AlexBlackNn/authloyalty/commands/proto/events.v1/events.v1"
+	registrationv1 "github.com/AlexBlackNn/authloyalty/commands/proto/registration.v1/registration.v1"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/config"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/logger"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/services/loyaltyservice"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/storage"
+	"github.com/AlexBlackNn/authloyalty/loyalty/pkg/broker"
+	"github.com/AlexBlackNn/authloyalty/loyalty/tests/unit_tests/mocks"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestBrokerMessageResult(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	msg := broker.Message{
+		EventID: "registration/0/42",
+		UUID:    "79d3ac44-5857-4185-ba92-1a224fbacb51",
+		Balance: 100,
+		Type:    "registration",
+		Comment: "registration",
+	}
+	next := msg
+	next.EventID = "registration/0/43"
+
+	loyaltyStorageMock := mocks.NewMockloyaltyStorage(ctrl)
+	gomock.InOrder(
+		loyaltyStorageMock.EXPECT().
+			AddLoyalty(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, l *domain.UserLoyalty) (*domain.UserLoyalty, error) {
+				require.Equal(t, msg.EventID, l.EventID)
+				return l, nil
+			}),
+		// storage reports the event was already applied
+		loyaltyStorageMock.EXPECT().
+			AddLoyalty(gomock.Any(), gomock.Any()).
+			Return(nil, storage.ErrEventProcessed),
+		loyaltyStorageMock.EXPECT().
+			AddLoyalty(gomock.Any(), gomock.Any()).
+			Return(nil, storage.ErrInternalErr),
+	)
+
+	msgChan := make(chan *broker.MessageReceived)
+	brokerMock := mocks.NewMockloyaltyBroker(ctrl)
+	brokerMock.EXPECT().
+		GetMessageChan().
+		Return(msgChan).
+		AnyTimes()
+
+	loyaltyservice.New(cfg, log, brokerMock, loyaltyStorageMock)
+
+	// offset is committed for processed and redelivered messages only
+	for _, tc := range []struct {
+		msg broker.Message
+		err error
+	}{
+		{msg: msg, err: nil},
+		{msg: msg, err: nil},
+		{msg: next, err: storage.ErrInternalErr},
+	} {
+		result := make(chan error, 1)
+		msgChan <- &broker.MessageReceived{Msg: tc.msg, Ctx: context.Background(), Result: result}
+		select {
+		case err := <-result:
+			require.ErrorIs(t, err, tc.err)
+		case <-time.After(time.Second):
+			t.Fatal("message is not processed")
+		}
+	}
+}
+
+func TestDeadLetterReplay(t *testing.T) {
+	topic := "registration"
+	msg := &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: 2, Offset: 42},
+		Key:            []byte("79d3ac44-5857-4185-ba92-1a224fbacb51"),
+		Value:          []byte("payload"),
+		Headers:        []kafka.Header{{Key: "traceparent", Value: []byte("trace")}},
+	}
+
+	deadLetter := broker.DeadLetter(msg, "registration.dlq", errors.New("db is down"), 5)
+	require.Equal(t, "registration.dlq", *deadLetter.TopicPartition.Topic)
+	require.Equal(t, msg.Key, deadLetter.Key)
+	require.Equal(t, msg.Value, deadLetter.Value)
+
+	headers := map[string]string{}
+	for _, header := range deadLetter.Headers {
+		headers[header.Key] = string(header.Value)
+	}
+	require.Equal(t, map[string]string{
+		"traceparent":                    "trace",
+		broker.HeaderDeadLetterError:     "db is down",
+		broker.HeaderDeadLetterAttempts:  "5",
+		broker.HeaderDeadLetterTopic:     "registration",
+		broker.HeaderDeadLetterPartition: "2",
+		broker.HeaderDeadLetterOffset:    "42",
+	}, headers)
+
+	// replayed message goes to the original topic without dead-letter headers
+	replayed := broker.Replayed(deadLetter, "fallback")
+	require.Equal(t, "registration", *replayed.TopicPartition.Topic)
+	require.Equal(t, msg.Key, replayed.Key)
+	require.Equal(t, msg.Value, replayed.Value)
+	require.Equal(t, msg.Headers, replayed.Headers)
+}
+
+func TestRegistrationMessage(t *testing.T) {
+	topic := "registration"
+	userID := "79d3ac44-5857-4185-ba92-1a224fbacb51"
+	occurredAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	kafkaMsg := &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: 0, Offset: 42},
+		Key:            []byte(userID),
+		Timestamp:      occurredAt.Add(time.Second),
+	}
+
+	// event of the current sso
+	msg, err := broker.RegistrationMessage(kafkaMsg, &registrationv1.RegistrationMessage{
+		Uuid:          userID,
+		Type:          "registration",
+		EventId:       "registration/" + userID,
+		OccurredAt:    timestamppb.New(occurredAt),
+		Bonus:         250,
+		Campaign:      "spring",
+		SchemaVersion: 2,
+	}, 100)
+	require.NoError(t, err)
+	require.Equal(t, broker.Message{
+		EventID:    "registration/" + userID,
+		UUID:       userID,
+		Balance:    250,
+		Type:       "registration",
+		Comment:    "spring",
+		OccurredAt: occurredAt,
+	}, msg)
+
+	// event of producers sending uuid and type only
+	msg, err = broker.RegistrationMessage(kafkaMsg, &registrationv1.RegistrationMessage{
+		Uuid: userID,
+		Type: "registration",
+	}, 100)
+	require.NoError(t, err)
+	require.Equal(t, broker.Message{
+		EventID:    "registration/0/42",
+		UUID:       userID,
+		Balance:    100,
+		Type:       "registration",
+		Comment:    "registration",
+		OccurredAt: kafkaMsg.Timestamp,
+	}, msg)
+
+	// zero bonus of the current sso is not replaced by the default one
+	msg, err = broker.RegistrationMessage(kafkaMsg, &registrationv1.RegistrationMessage{
+		Uuid:          userID,
+		Type:          "registration",
+		SchemaVersion: 2,
+	}, 100)
+	require.NoError(t, err)
+	require.Zero(t, msg.Balance)
+
+	_, err = broker.RegistrationMessage(kafkaMsg, &registrationv1.RegistrationMessage{
+		Uuid:          userID,
+		Type:          "registration",
+		Bonus:         -1,
+		SchemaVersion: 2,
+	}, 100)
+	require.ErrorIs(t, err, broker.ErrNegativeBonus)
+}
+
+func TestZeroBonusRegistration(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := "79d3ac44-5857-4185-ba92-1a224fbacb51"
+	// account is created without bonus
+	loyaltyStorageMock := mocks.NewMockloyaltyStorage(ctrl)
+	loyaltyStorageMock.EXPECT().
+		AddLoyalty(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, userLoyalty *domain.UserLoyalty) (*domain.UserLoyalty, error) {
+			require.Equal(t, userID, userLoyalty.UUID)
+			require.Equal(t, "registration", userLoyalty.Operation)
+			require.Zero(t, userLoyalty.Balance)
+			return userLoyalty, nil
+		})
+
+	msgChan := make(chan *broker.MessageReceived)
+	brokerMock := mocks.NewMockloyaltyBroker(ctrl)
+	brokerMock.EXPECT().
+		GetMessageChan().
+		Return(msgChan).
+		AnyTimes()
+
+	loyaltyservice.New(cfg, log, brokerMock, loyaltyStorageMock)
+
+	result := make(chan error, 1)
+	msgChan <- &broker.MessageReceived{
+		Msg: broker.Message{
+			EventID: "registration/" + userID,
+			UUID:    userID,
+			Type:    broker.TypeRegistration,
+			Comment: "registration",
+		},
+		Ctx:    context.Background(),
+		Result: result,
+	}
+	select {
+	case err := <-result:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("message is not processed")
+	}
+}
+
+func TestUserEventDispatch(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	topic := "user_events"
+	userID := "79d3ac44-5857-4185-ba92-1a224fbacb51"
+	until := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	kafkaMsg := &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: 0, Offset: 7},
+		Key:            []byte(userID),
+	}
+	newEvent := func(eventID string, event *eventsv1.UserEvent) broker.Message {
+		event.EventId = eventID
+		event.UserId = userID
+		event.SchemaVersion = 1
+		event.OccurredAt = timestamppb.New(until)
+		return broker.UserEventMessage(kafkaMsg, event)
+	}
+
+	blocked := newEvent("blocked", &eventsv1.UserEvent{Payload: &eventsv1.UserEvent_UserBlocked{
+		UserBlocked: &eventsv1.UserBlocked{Reason: "fraud", Until: timestamppb.New(until)},
+	}})
+	require.Equal(t, broker.TypeUserBlocked, blocked.Type)
+	require.Equal(t, userID, blocked.UUID)
+	require.Equal(t, until, blocked.OccurredAt)
+	deleted := newEvent("deleted", &eventsv1.UserEvent{Payload: &eventsv1.UserEvent_UserDeleted{
+		UserDeleted: &eventsv1.UserDeleted{},
+	}})
+	roleChanged := newEvent("role", &eventsv1.UserEvent{Payload: &eventsv1.UserEvent_RoleChanged{
+		RoleChanged: &eventsv1.RoleChanged{Role: "admin", Granted: true},
+	}})
+	// payload added to envelope by a newer producer
+	unknown := newEvent("unknown", &eventsv1.UserEvent{})
+	require.Empty(t, unknown.Type)
+
+	loyaltyStorageMock := mocks.NewMockloyaltyStorage(ctrl)
+	gomock.InOrder(
+		loyaltyStorageMock.EXPECT().
+			UpdateAccountStatus(gomock.Any(), &domain.AccountStatus{
+				UUID: userID, Status: domain.AccountFrozen, FrozenUntil: &until, EventID: "blocked",
+			}).
+			Return(nil),
+		loyaltyStorageMock.EXPECT().
+			UpdateAccountStatus(gomock.Any(), &domain.AccountStatus{
+				UUID: userID, Status: domain.AccountClosed, EventID: "deleted",
+			}).
+			Return(storage.ErrEventProcessed),
+	)
+
+	msgChan := make(chan *broker.MessageReceived)
+	brokerMock := mocks.NewMockloyaltyBroker(ctrl)
+	brokerMock.EXPECT().
+		GetMessageChan().
+		Return(msgChan).
+		AnyTimes()
+
+	loyaltyservice.New(cfg, log, brokerMock, loyaltyStorageMock)
+
+	// events not affecting loyalty and already processed events are committed
+	for _, msg := range []broker.Message{blocked, deleted, roleChanged, unknown} {
+		result := make(chan error, 1)
+		msgChan <- &broker.MessageReceived{Msg: msg, Ctx: context.Background(), Result: result}
+		select {
+		case err := <-result:
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("message is not processed")
+		}
+	}
+}
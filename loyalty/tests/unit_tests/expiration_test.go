@@ -0,0 +1,114 @@
+package unit_tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/config"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/logger"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/services/loyaltyservice"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/storage"
+	"github.com/AlexBlackNn/authloyalty/loyalty/tests/unit_tests/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpirePoints(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	loyaltyStorageMock := mocks.NewMockloyaltyStorage(ctrl)
+	loyaltyStorageMock.EXPECT().
+		GetAccountsWithExpiredPoints(gomock.Any(), gomock.Any(), cfg.PointsExpiration.BatchSize).
+		Return([]string{"first", "failed", "second"}, nil)
+	loyaltyStorageMock.EXPECT().
+		ExpirePoints(gomock.Any(), "first", gomock.Any()).
+		Return(30, nil)
+	// failed account doesn't stop the batch, it's retried by the next run
+	loyaltyStorageMock.EXPECT().
+		ExpirePoints(gomock.Any(), "failed", gomock.Any()).
+		Return(0, errors.New("connection reset"))
+	loyaltyStorageMock.EXPECT().
+		ExpirePoints(gomock.Any(), "second", gomock.Any()).
+		Return(70, nil)
+	brokerMock := mocks.NewMockloyaltyBroker(ctrl)
+	brokerMock.EXPECT().
+		GetMessageChan().
+		Return(nil).
+		AnyTimes()
+
+	loyalService := loyaltyservice.New(cfg, log, brokerMock, loyaltyStorageMock)
+	expired, err := loyalService.ExpirePoints(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 100, expired)
+}
+
+func TestDepositExpiresAt(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	loyaltyStorageMock := mocks.NewMockloyaltyStorage(ctrl)
+	loyaltyStorageMock.EXPECT().
+		AddLoyalty(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, l *domain.UserLoyalty) (*domain.UserLoyalty, error) {
+			switch l.Operation {
+			case "d":
+				// deposited points expire configured time after deposit
+				require.NotNil(t, l.ExpiresAt)
+				require.WithinDuration(t, time.Now().Add(cfg.PointsExpiration.Ttl), *l.ExpiresAt, time.Minute)
+			case "w":
+				require.Nil(t, l.ExpiresAt)
+			}
+			return l, nil
+		}).
+		Times(2)
+	brokerMock := mocks.NewMockloyaltyBroker(ctrl)
+	brokerMock.EXPECT().
+		GetMessageChan().
+		Return(nil).
+		AnyTimes()
+
+	loyalService := loyaltyservice.New(cfg, log, brokerMock, loyaltyStorageMock)
+	for _, operation := range []string{"d", "w"} {
+		_, err := loyalService.AddLoyalty(context.Background(), &domain.UserLoyalty{
+			UUID:      "79d3ac44-5857-4185-ba92-1a224fbacb51",
+			Operation: operation,
+			Comment:   "order 42",
+			Balance:   100,
+		})
+		require.NoError(t, err)
+	}
+}
+
+func TestLotsMismatch(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	loyaltyStorageMock := mocks.NewMockloyaltyStorage(ctrl)
+	loyaltyStorageMock.EXPECT().
+		AddLoyalty(gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("withdrawLots: %w", storage.ErrLotsMismatch))
+	brokerMock := mocks.NewMockloyaltyBroker(ctrl)
+	brokerMock.EXPECT().
+		GetMessageChan().
+		Return(nil).
+		AnyTimes()
+
+	loyalService := loyaltyservice.New(cfg, log, brokerMock, loyaltyStorageMock)
+	_, err := loyalService.AddLoyalty(context.Background(), &domain.UserLoyalty{
+		UUID:      "79d3ac44-5857-4185-ba92-1a224fbacb51",
+		Operation: "w",
+		Balance:   10,
+	})
+	require.ErrorIs(t, err, loyaltyservice.ErrLotsMismatch)
+}
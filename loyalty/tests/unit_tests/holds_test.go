@@ -0,0 +1,109 @@
+package unit_tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/config"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/logger"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/services/loyaltyservice"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/storage"
+	"github.com/AlexBlackNn/authloyalty/loyalty/tests/unit_tests/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHolds(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	loyaltyStorageMock := mocks.NewMockloyaltyStorage(ctrl)
+	loyaltyStorageMock.EXPECT().
+		CreateHold(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, hold *domain.Hold) (*domain.Hold, error) {
+			// hold expires configured time after it's created
+			require.WithinDuration(t, time.Now().Add(cfg.Holds.Ttl), hold.ExpiresAt, time.Minute)
+			hold.ID = "0d6b5bd4-6c3b-4c1e-a4a5-6c1d3f0a0b01"
+			hold.Status = domain.HoldActive
+			return hold, nil
+		})
+	loyaltyStorageMock.EXPECT().
+		CaptureHold(gomock.Any(), &domain.HoldAction{ID: "captured"}).
+		Return(nil, storage.ErrHoldNotActive)
+	loyaltyStorageMock.EXPECT().
+		VoidHold(gomock.Any(), &domain.HoldAction{ID: "other", UUID: "79d3ac44-5857-4185-ba92-1a224fbacb51"}).
+		Return(nil, storage.ErrHoldNotFound)
+	brokerMock := mocks.NewMockloyaltyBroker(ctrl)
+	brokerMock.EXPECT().
+		GetMessageChan().
+		Return(nil).
+		AnyTimes()
+
+	loyalService := loyaltyservice.New(cfg, log, brokerMock, loyaltyStorageMock)
+	ctx := context.Background()
+
+	hold, err := loyalService.CreateHold(ctx, &domain.Hold{
+		UUID: "79d3ac44-5857-4185-ba92-1a224fbacb51", Amount: 100, Comment: "order 42",
+	})
+	require.NoError(t, err)
+	require.Equal(t, domain.HoldActive, hold.Status)
+
+	_, err = loyalService.CaptureHold(ctx, &domain.HoldAction{ID: "captured"})
+	require.ErrorIs(t, err, loyaltyservice.ErrHoldNotActive)
+	_, err = loyalService.VoidHold(ctx, &domain.HoldAction{
+		ID: "other", UUID: "79d3ac44-5857-4185-ba92-1a224fbacb51",
+	})
+	require.ErrorIs(t, err, loyaltyservice.ErrHoldNotFound)
+}
+
+func TestExpireHolds(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	loyaltyStorageMock := mocks.NewMockloyaltyStorage(ctrl)
+	loyaltyStorageMock.EXPECT().
+		GetExpiredHolds(gomock.Any(), gomock.Any(), cfg.Holds.BatchSize).
+		Return([]string{"expired", "captured", "failed"}, nil)
+	loyaltyStorageMock.EXPECT().
+		ExpireHold(gomock.Any(), "expired").
+		Return(&domain.Hold{ID: "expired", Amount: 100, Status: domain.HoldExpired}, nil)
+	// hold captured after it was selected is skipped
+	loyaltyStorageMock.EXPECT().
+		ExpireHold(gomock.Any(), "captured").
+		Return(nil, storage.ErrHoldNotActive)
+	loyaltyStorageMock.EXPECT().
+		ExpireHold(gomock.Any(), "failed").
+		Return(nil, errors.New("connection reset"))
+	brokerMock := mocks.NewMockloyaltyBroker(ctrl)
+	brokerMock.EXPECT().
+		GetMessageChan().
+		Return(nil).
+		AnyTimes()
+
+	loyalService := loyaltyservice.New(cfg, log, brokerMock, loyaltyStorageMock)
+	expired, err := loyalService.ExpireHolds(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, expired)
+}
+
+func (ls *LoyaltyAddSuite) TestHttpCreateHoldWithoutToken() {
+	ls.Run("create hold without token", func() {
+		resp, err := ls.client.Post(
+			ls.srv.URL+"/loyalty/holds",
+			"application/json",
+			strings.NewReader(`{"amount": 100, "comment": "order 42"}`),
+		)
+		ls.NoError(err)
+		defer resp.Body.Close()
+		ls.Equal(http.StatusUnauthorized, resp.StatusCode)
+	})
+}
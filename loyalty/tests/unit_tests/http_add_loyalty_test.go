@@ -3,6 +3,7 @@ package unit_tests
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -70,6 +71,7 @@ func (ls *LoyaltyAddSuite) BeforeTest(suiteName, testName string) {
 		ls.application.Log,
 		ls.application.HandlersV1,
 		ls.application.HealthChecker,
+		ls.application.TokenVerifier,
 	))
 }
 
@@ -113,3 +115,20 @@ func (ls *LoyaltyAddSuite) TestHttpServerRegisterHappyPath() {
 
 	})
 }
+
+func (ls *LoyaltyAddSuite) TestHttpAddLoyaltyWithoutToken() {
+	ls.Run("add loyalty without token", func() {
+		req, err := http.NewRequest(
+			http.MethodPost,
+			ls.srv.URL+"/loyalty/",
+			strings.NewReader(`{"uuid":"79d3ac44-5857-4185-ba92-1a224fbacb51","balance":100,"operation":"d","comment":"test"}`),
+		)
+		ls.NoError(err)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := ls.client.Do(req)
+		ls.NoError(err)
+		defer resp.Body.Close()
+		ls.Equal(http.StatusUnauthorized, resp.StatusCode)
+	})
+}
@@ -45,6 +45,10 @@ func (ls *LoyaltySuite) SetupSuite() {
 		GetLoyalty(gomock.Any(), gomock.Any()).
 		Return(userLoyalty, nil).
 		AnyTimes()
+	loyaltyStorageMock.EXPECT().
+		GetExpiringPoints(gomock.Any(), userLoyalty.UUID, gomock.Any()).
+		Return(50, nil).
+		AnyTimes()
 
 	brokerMock := mocks.NewMockloyaltyBroker(ctrl)
 	brokerMock.EXPECT().
@@ -72,6 +76,7 @@ func (ls *LoyaltySuite) BeforeTest(suiteName, testName string) {
 		ls.application.Log,
 		ls.application.HandlersV1,
 		ls.application.HealthChecker,
+		ls.application.TokenVerifier,
 	))
 }
 
@@ -125,5 +130,10 @@ func (ls *LoyaltySuite) TestHttpServerRegisterHappyPath() {
 		ls.NoError(err)
 		ls.Equal(test.want.response.Balance, response.Balance)
 		ls.Equal(test.want.response.UUID, response.UUID)
+
+		var expiring dto.Response
+		err = json.Unmarshal(body, &expiring)
+		ls.NoError(err)
+		ls.Equal(50, expiring.ExpiringSoon)
 	})
 }
@@ -0,0 +1,128 @@
+package unit_tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/config"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/logger"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/services/loyaltyservice"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/storage"
+	"github.com/AlexBlackNn/authloyalty/loyalty/tests/unit_tests/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddLoyaltyIdempotencyKey(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newRequest := func(balance int) *domain.UserLoyalty {
+		return &domain.UserLoyalty{
+			UUID:           "79d3ac44-5857-4185-ba92-1a224fbacb51",
+			Operation:      "w",
+			Comment:        "order 42",
+			Balance:        balance,
+			IdempotencyKey: "order-42",
+			RequestedBy:    "79d3ac44-5857-4185-ba92-1a224fbacb51",
+			ResponseStatus: http.StatusOK,
+		}
+	}
+
+	// storage keeps hash and response status of the first request by key
+	hashes := map[string]string{}
+	statuses := map[string]int{}
+	loyaltyStorageMock := mocks.NewMockloyaltyStorage(ctrl)
+	loyaltyStorageMock.EXPECT().
+		AddLoyalty(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, l *domain.UserLoyalty) (*domain.UserLoyalty, error) {
+			require.NotEmpty(t, l.RequestHash)
+			hash, ok := hashes[l.IdempotencyKey]
+			if !ok {
+				hashes[l.IdempotencyKey] = l.RequestHash
+				statuses[l.IdempotencyKey] = l.ResponseStatus
+				return &domain.UserLoyalty{UUID: l.UUID, Balance: 900, ResponseStatus: l.ResponseStatus}, nil
+			}
+			if hash != l.RequestHash {
+				return nil, storage.ErrIdempotencyKeyConflict
+			}
+			return &domain.UserLoyalty{UUID: l.UUID, Balance: 900, ResponseStatus: statuses[l.IdempotencyKey]}, nil
+		}).
+		Times(3)
+	brokerMock := mocks.NewMockloyaltyBroker(ctrl)
+	brokerMock.EXPECT().
+		GetMessageChan().
+		Return(nil).
+		AnyTimes()
+
+	loyalService := loyaltyservice.New(cfg, log, brokerMock, loyaltyStorageMock)
+	ctx := context.Background()
+
+	first, err := loyalService.AddLoyalty(ctx, newRequest(100))
+	require.NoError(t, err)
+	// retry gets result of the first request
+	retry, err := loyalService.AddLoyalty(ctx, newRequest(100))
+	require.NoError(t, err)
+	require.Equal(t, first, retry)
+	require.Equal(t, http.StatusOK, retry.ResponseStatus)
+	// the same key with other amount is rejected
+	_, err = loyalService.AddLoyalty(ctx, newRequest(200))
+	require.ErrorIs(t, err, loyaltyservice.ErrIdempotencyKeyConflict)
+}
+
+func TestSaveRejectedLoyalty(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	request := &domain.UserLoyalty{
+		UUID:           "79d3ac44-5857-4185-ba92-1a224fbacb51",
+		Operation:      "w",
+		Balance:        1000,
+		IdempotencyKey: "order-43",
+		RequestedBy:    "79d3ac44-5857-4185-ba92-1a224fbacb51",
+		ResponseStatus: http.StatusBadRequest,
+		ResponseError:  "withdraw such amount of loyalty leads to negative balance",
+	}
+	// storage keeps rejected response by key
+	var saved *domain.UserLoyalty
+	loyaltyStorageMock := mocks.NewMockloyaltyStorage(ctrl)
+	loyaltyStorageMock.EXPECT().
+		SaveRejectedLoyalty(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, l *domain.UserLoyalty) error {
+			require.NotEmpty(t, l.RequestHash)
+			saved = l
+			return nil
+		})
+	loyaltyStorageMock.EXPECT().
+		AddLoyalty(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, l *domain.UserLoyalty) (*domain.UserLoyalty, error) {
+			require.Equal(t, saved.RequestHash, l.RequestHash)
+			return &domain.UserLoyalty{ResponseStatus: saved.ResponseStatus, ResponseError: saved.ResponseError}, nil
+		})
+	brokerMock := mocks.NewMockloyaltyBroker(ctrl)
+	brokerMock.EXPECT().
+		GetMessageChan().
+		Return(nil).
+		AnyTimes()
+
+	loyalService := loyaltyservice.New(cfg, log, brokerMock, loyaltyStorageMock)
+	ctx := context.Background()
+
+	require.NoError(t, loyalService.SaveRejectedLoyalty(ctx, request))
+	// operation without key is not saved
+	require.NoError(t, loyalService.SaveRejectedLoyalty(ctx, &domain.UserLoyalty{UUID: request.UUID}))
+
+	// retry gets the rejection instead of applying the operation
+	retry := *request
+	retry.ResponseStatus, retry.ResponseError, retry.RequestHash = 0, "", ""
+	replayed, err := loyalService.AddLoyalty(ctx, &retry)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, replayed.ResponseStatus)
+	require.Equal(t, request.ResponseError, replayed.ResponseError)
+}
@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
 	broker "github.com/AlexBlackNn/authloyalty/loyalty/pkg/broker"
@@ -50,6 +51,21 @@ func (mr *MockloyaltyBrokerMockRecorder) GetMessageChan() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessageChan", reflect.TypeOf((*MockloyaltyBroker)(nil).GetMessageChan))
 }
 
+// ReplayDeadLetters mocks base method.
+func (m *MockloyaltyBroker) ReplayDeadLetters(ctx context.Context, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayDeadLetters", ctx, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplayDeadLetters indicates an expected call of ReplayDeadLetters.
+func (mr *MockloyaltyBrokerMockRecorder) ReplayDeadLetters(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayDeadLetters", reflect.TypeOf((*MockloyaltyBroker)(nil).ReplayDeadLetters), ctx, limit)
+}
+
 // MockloyaltyStorage is a mock of loyaltyStorage interface.
 type MockloyaltyStorage struct {
 	ctrl     *gomock.Controller
@@ -73,6 +89,21 @@ func (m *MockloyaltyStorage) EXPECT() *MockloyaltyStorageMockRecorder {
 	return m.recorder
 }
 
+// AddAccrual mocks base method.
+func (m *MockloyaltyStorage) AddAccrual(ctx context.Context, accrual *domain.Accrual) (*domain.Accrual, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAccrual", ctx, accrual)
+	ret0, _ := ret[0].(*domain.Accrual)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAccrual indicates an expected call of AddAccrual.
+func (mr *MockloyaltyStorageMockRecorder) AddAccrual(ctx, accrual interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccrual", reflect.TypeOf((*MockloyaltyStorage)(nil).AddAccrual), ctx, accrual)
+}
+
 // AddLoyalty mocks base method.
 func (m *MockloyaltyStorage) AddLoyalty(ctx context.Context, loyalty *domain.UserLoyalty) (*domain.UserLoyalty, error) {
 	m.ctrl.T.Helper()
@@ -88,6 +119,111 @@ func (mr *MockloyaltyStorageMockRecorder) AddLoyalty(ctx, loyalty interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLoyalty", reflect.TypeOf((*MockloyaltyStorage)(nil).AddLoyalty), ctx, loyalty)
 }
 
+// CaptureHold mocks base method.
+func (m *MockloyaltyStorage) CaptureHold(ctx context.Context, action *domain.HoldAction) (*domain.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureHold", ctx, action)
+	ret0, _ := ret[0].(*domain.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockloyaltyStorageMockRecorder) CaptureHold(ctx, action interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockloyaltyStorage)(nil).CaptureHold), ctx, action)
+}
+
+// CreateHold mocks base method.
+func (m *MockloyaltyStorage) CreateHold(ctx context.Context, hold *domain.Hold) (*domain.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHold", ctx, hold)
+	ret0, _ := ret[0].(*domain.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHold indicates an expected call of CreateHold.
+func (mr *MockloyaltyStorageMockRecorder) CreateHold(ctx, hold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHold", reflect.TypeOf((*MockloyaltyStorage)(nil).CreateHold), ctx, hold)
+}
+
+// ExpireHold mocks base method.
+func (m *MockloyaltyStorage) ExpireHold(ctx context.Context, id string) (*domain.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireHold", ctx, id)
+	ret0, _ := ret[0].(*domain.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireHold indicates an expected call of ExpireHold.
+func (mr *MockloyaltyStorageMockRecorder) ExpireHold(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireHold", reflect.TypeOf((*MockloyaltyStorage)(nil).ExpireHold), ctx, id)
+}
+
+// ExpirePoints mocks base method.
+func (m *MockloyaltyStorage) ExpirePoints(ctx context.Context, uuid string, now time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpirePoints", ctx, uuid, now)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpirePoints indicates an expected call of ExpirePoints.
+func (mr *MockloyaltyStorageMockRecorder) ExpirePoints(ctx, uuid, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpirePoints", reflect.TypeOf((*MockloyaltyStorage)(nil).ExpirePoints), ctx, uuid, now)
+}
+
+// GetAccountsWithExpiredPoints mocks base method.
+func (m *MockloyaltyStorage) GetAccountsWithExpiredPoints(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountsWithExpiredPoints", ctx, now, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountsWithExpiredPoints indicates an expected call of GetAccountsWithExpiredPoints.
+func (mr *MockloyaltyStorageMockRecorder) GetAccountsWithExpiredPoints(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountsWithExpiredPoints", reflect.TypeOf((*MockloyaltyStorage)(nil).GetAccountsWithExpiredPoints), ctx, now, limit)
+}
+
+// GetExpiredHolds mocks base method.
+func (m *MockloyaltyStorage) GetExpiredHolds(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredHolds", ctx, now, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiredHolds indicates an expected call of GetExpiredHolds.
+func (mr *MockloyaltyStorageMockRecorder) GetExpiredHolds(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredHolds", reflect.TypeOf((*MockloyaltyStorage)(nil).GetExpiredHolds), ctx, now, limit)
+}
+
+// GetExpiringPoints mocks base method.
+func (m *MockloyaltyStorage) GetExpiringPoints(ctx context.Context, uuid string, before time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiringPoints", ctx, uuid, before)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiringPoints indicates an expected call of GetExpiringPoints.
+func (mr *MockloyaltyStorageMockRecorder) GetExpiringPoints(ctx, uuid, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiringPoints", reflect.TypeOf((*MockloyaltyStorage)(nil).GetExpiringPoints), ctx, uuid, before)
+}
+
 // GetLoyalty mocks base method.
 func (m *MockloyaltyStorage) GetLoyalty(ctx context.Context, loyalty *domain.UserLoyalty) (*domain.UserLoyalty, error) {
 	m.ctrl.T.Helper()
@@ -103,6 +239,21 @@ func (mr *MockloyaltyStorageMockRecorder) GetLoyalty(ctx, loyalty interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoyalty", reflect.TypeOf((*MockloyaltyStorage)(nil).GetLoyalty), ctx, loyalty)
 }
 
+// GetTransactions mocks base method.
+func (m *MockloyaltyStorage) GetTransactions(ctx context.Context, filter *domain.TransactionFilter) ([]domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactions", ctx, filter)
+	ret0, _ := ret[0].([]domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactions indicates an expected call of GetTransactions.
+func (mr *MockloyaltyStorageMockRecorder) GetTransactions(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockloyaltyStorage)(nil).GetTransactions), ctx, filter)
+}
+
 // HealthCheck mocks base method.
 func (m *MockloyaltyStorage) HealthCheck(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -117,6 +268,35 @@ func (mr *MockloyaltyStorageMockRecorder) HealthCheck(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealthCheck", reflect.TypeOf((*MockloyaltyStorage)(nil).HealthCheck), arg0)
 }
 
+// ReverseTransaction mocks base method.
+func (m *MockloyaltyStorage) ReverseTransaction(ctx context.Context, reversal *domain.Reversal) (*domain.Reversal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReverseTransaction", ctx, reversal)
+	ret0, _ := ret[0].(*domain.Reversal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReverseTransaction indicates an expected call of ReverseTransaction.
+func (mr *MockloyaltyStorageMockRecorder) ReverseTransaction(ctx, reversal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseTransaction", reflect.TypeOf((*MockloyaltyStorage)(nil).ReverseTransaction), ctx, reversal)
+}
+
+// SaveRejectedLoyalty mocks base method.
+func (m *MockloyaltyStorage) SaveRejectedLoyalty(ctx context.Context, loyalty *domain.UserLoyalty) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRejectedLoyalty", ctx, loyalty)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRejectedLoyalty indicates an expected call of SaveRejectedLoyalty.
+func (mr *MockloyaltyStorageMockRecorder) SaveRejectedLoyalty(ctx, loyalty interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRejectedLoyalty", reflect.TypeOf((*MockloyaltyStorage)(nil).SaveRejectedLoyalty), ctx, loyalty)
+}
+
 // Stop mocks base method.
 func (m *MockloyaltyStorage) Stop() error {
 	m.ctrl.T.Helper()
@@ -130,3 +310,47 @@ func (mr *MockloyaltyStorageMockRecorder) Stop() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockloyaltyStorage)(nil).Stop))
 }
+
+// Transfer mocks base method.
+func (m *MockloyaltyStorage) Transfer(ctx context.Context, transfer *domain.Transfer) (*domain.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transfer", ctx, transfer)
+	ret0, _ := ret[0].(*domain.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Transfer indicates an expected call of Transfer.
+func (mr *MockloyaltyStorageMockRecorder) Transfer(ctx, transfer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockloyaltyStorage)(nil).Transfer), ctx, transfer)
+}
+
+// UpdateAccountStatus mocks base method.
+func (m *MockloyaltyStorage) UpdateAccountStatus(ctx context.Context, accountStatus *domain.AccountStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountStatus", ctx, accountStatus)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAccountStatus indicates an expected call of UpdateAccountStatus.
+func (mr *MockloyaltyStorageMockRecorder) UpdateAccountStatus(ctx, accountStatus interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountStatus", reflect.TypeOf((*MockloyaltyStorage)(nil).UpdateAccountStatus), ctx, accountStatus)
+}
+
+// VoidHold mocks base method.
+func (m *MockloyaltyStorage) VoidHold(ctx context.Context, action *domain.HoldAction) (*domain.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoidHold", ctx, action)
+	ret0, _ := ret[0].(*domain.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoidHold indicates an expected call of VoidHold.
+func (mr *MockloyaltyStorageMockRecorder) VoidHold(ctx, action interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoidHold", reflect.TypeOf((*MockloyaltyStorage)(nil).VoidHold), ctx, action)
+}
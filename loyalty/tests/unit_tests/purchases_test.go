@@ -0,0 +1,175 @@
+package unit_tests
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/config"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/logger"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/services/loyaltyservice"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/storage"
+	"github.com/AlexBlackNn/authloyalty/loyalty/tests/unit_tests/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurchasePoints(t *testing.T) {
+	rules := config.EarningRulesConfig{
+		PointsPerUnit:       1,
+		MinorUnits:          100,
+		CategoryMultipliers: map[string]float64{"books": 2, "electronics": 0.5},
+		Campaigns: []config.CampaignConfig{
+			{
+				Name:       "black-friday",
+				From:       time.Date(2026, 11, 27, 0, 0, 0, 0, time.UTC),
+				To:         time.Date(2026, 11, 30, 0, 0, 0, 0, time.UTC),
+				Multiplier: 3,
+			},
+			{
+				Name:       "books-week",
+				From:       time.Date(2026, 11, 25, 0, 0, 0, 0, time.UTC),
+				To:         time.Date(2026, 12, 2, 0, 0, 0, 0, time.UTC),
+				Multiplier: 4,
+				Categories: []string{"books"},
+			},
+		},
+	}
+	beforeCampaigns := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	blackFriday := time.Date(2026, 11, 28, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		items      []domain.PurchaseItem
+		occurredAt time.Time
+		points     int
+	}{
+		{
+			name:       "points per unit",
+			items:      []domain.PurchaseItem{{Category: "food", Amount: 12345}},
+			occurredAt: beforeCampaigns,
+			points:     123,
+		},
+		{
+			name: "category multipliers",
+			items: []domain.PurchaseItem{
+				{Category: "books", Amount: 1000},
+				{Category: "electronics", Amount: 1000},
+			},
+			occurredAt: beforeCampaigns,
+			points:     25,
+		},
+		{
+			name:       "points of purchase are rounded down once",
+			items:      []domain.PurchaseItem{{Category: "food", Amount: 150}, {Category: "food", Amount: 150}},
+			occurredAt: beforeCampaigns,
+			points:     3,
+		},
+		{
+			name:       "campaign",
+			items:      []domain.PurchaseItem{{Category: "electronics", Amount: 1000}},
+			occurredAt: blackFriday,
+			points:     15,
+		},
+		{
+			name:       "largest of overlapping campaigns",
+			items:      []domain.PurchaseItem{{Category: "books", Amount: 1000}},
+			occurredAt: blackFriday,
+			points:     80,
+		},
+		{
+			name:       "campaign end is exclusive",
+			items:      []domain.PurchaseItem{{Category: "food", Amount: 1000}},
+			occurredAt: time.Date(2026, 11, 30, 0, 0, 0, 0, time.UTC),
+			points:     10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points := loyaltyservice.PurchasePoints(rules, &domain.Purchase{
+				Items:      tt.items,
+				OccurredAt: tt.occurredAt,
+			})
+			require.Equal(t, tt.points, points)
+		})
+	}
+}
+
+func TestAddPurchase(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// storage keeps processed purchases
+	processed := make(map[string]bool)
+	loyaltyStorageMock := mocks.NewMockloyaltyStorage(ctrl)
+	loyaltyStorageMock.EXPECT().
+		AddAccrual(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, accrual *domain.Accrual) (*domain.Accrual, error) {
+			require.Len(t, accrual.Caps, len(cfg.EarningRules.Caps))
+			require.NotNil(t, accrual.ExpiresAt)
+			if processed[accrual.EventID] {
+				return nil, storage.ErrEventProcessed
+			}
+			processed[accrual.EventID] = true
+			accrual.Deposited = min(accrual.Points, accrual.Caps[0].Points)
+			accrual.Balance = accrual.Deposited
+			return accrual, nil
+		}).
+		Times(2)
+	brokerMock := mocks.NewMockloyaltyBroker(ctrl)
+	brokerMock.EXPECT().
+		GetMessageChan().
+		Return(nil).
+		AnyTimes()
+
+	loyalService := loyaltyservice.New(cfg, log, brokerMock, loyaltyStorageMock)
+	ctx := context.Background()
+
+	purchase := &domain.Purchase{
+		ID:    "order-1",
+		UUID:  "79d3ac44-5857-4185-ba92-1a224fbacb51",
+		Items: []domain.PurchaseItem{{Category: "food", Amount: 500000}},
+	}
+	accrual, err := loyalService.AddPurchase(ctx, purchase)
+	require.NoError(t, err)
+	require.Equal(t, 5000, accrual.Points)
+	// daily cap from config limits deposited points
+	require.Equal(t, cfg.EarningRules.Caps[0].Points, accrual.Deposited)
+
+	_, err = loyalService.AddPurchase(ctx, purchase)
+	require.ErrorIs(t, err, loyaltyservice.ErrPurchaseProcessed)
+
+	// client can't pick time of a finished campaign
+	_, err = loyalService.AddPurchase(ctx, &domain.Purchase{
+		ID:         "order-2",
+		UUID:       purchase.UUID,
+		Items:      purchase.Items,
+		OccurredAt: time.Now().Add(-cfg.EarningRules.OccurredAtWindow - time.Minute),
+	})
+	require.ErrorIs(t, err, loyaltyservice.ErrPurchaseTimeOutOfWindow)
+	_, err = loyalService.AddPurchase(ctx, &domain.Purchase{
+		ID:         "order-3",
+		UUID:       purchase.UUID,
+		Items:      purchase.Items,
+		OccurredAt: time.Now().Add(cfg.EarningRules.OccurredAtWindow + time.Minute),
+	})
+	require.ErrorIs(t, err, loyaltyservice.ErrPurchaseTimeOutOfWindow)
+}
+
+func (ls *LoyaltyAddSuite) TestHttpAddPurchaseWithoutToken() {
+	ls.Run("add purchase without token", func() {
+		resp, err := ls.client.Post(
+			ls.srv.URL+"/loyalty/purchases",
+			"application/json",
+			strings.NewReader(`{"id": "order-1", "uuid": "79d3ac44-5857-4185-ba92-1a224fbacb51", "items": [{"category": "books", "amount": 1000}]}`),
+		)
+		ls.NoError(err)
+		defer resp.Body.Close()
+		ls.Equal(http.StatusUnauthorized, resp.StatusCode)
+	})
+}
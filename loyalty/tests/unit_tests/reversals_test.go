@@ -0,0 +1,83 @@
+package unit_tests
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/config"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/logger"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/services/loyaltyservice"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/storage"
+	"github.com/AlexBlackNn/authloyalty/loyalty/tests/unit_tests/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReverseTransaction(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const withdrawID = "3b0e1a6c-0a3e-4a8e-9d5f-2f1a8c2b7d01"
+	// storage keeps reversed amount of withdraw of 100 points
+	var reversed int
+	loyaltyStorageMock := mocks.NewMockloyaltyStorage(ctrl)
+	loyaltyStorageMock.EXPECT().
+		ReverseTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, r *domain.Reversal) (*domain.Reversal, error) {
+			// returned points expire like deposited ones
+			require.NotNil(t, r.ExpiresAt)
+			if r.Amount == 0 {
+				r.Amount = 100 - reversed
+			}
+			if r.Amount > 100-reversed || r.Amount == 0 {
+				return nil, storage.ErrReversalAmountExceeded
+			}
+			reversed += r.Amount
+			r.Type = "d"
+			return r, nil
+		}).
+		Times(3)
+	brokerMock := mocks.NewMockloyaltyBroker(ctrl)
+	brokerMock.EXPECT().
+		GetMessageChan().
+		Return(nil).
+		AnyTimes()
+
+	loyalService := loyaltyservice.New(cfg, log, brokerMock, loyaltyStorageMock)
+	ctx := context.Background()
+
+	// partial reversal and the rest of the transaction
+	reversal, err := loyalService.ReverseTransaction(ctx, &domain.Reversal{
+		TransactionID: withdrawID, Amount: 30, Comment: "order 42 partially cancelled",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 30, reversal.Amount)
+	reversal, err = loyalService.ReverseTransaction(ctx, &domain.Reversal{
+		TransactionID: withdrawID, Comment: "order 42 cancelled",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 70, reversal.Amount)
+	// fully reversed transaction can't be reversed again
+	_, err = loyalService.ReverseTransaction(ctx, &domain.Reversal{
+		TransactionID: withdrawID, Amount: 1, Comment: "order 42 cancelled twice",
+	})
+	require.ErrorIs(t, err, loyaltyservice.ErrReversalAmountExceeded)
+}
+
+func (ls *LoyaltyAddSuite) TestHttpReverseTransactionWithoutToken() {
+	ls.Run("reverse transaction without token", func() {
+		resp, err := ls.client.Post(
+			ls.srv.URL+"/loyalty/transactions/3b0e1a6c-0a3e-4a8e-9d5f-2f1a8c2b7d01/reverse",
+			"application/json",
+			strings.NewReader(`{"comment": "order 42 cancelled"}`),
+		)
+		ls.NoError(err)
+		defer resp.Body.Close()
+		ls.Equal(http.StatusUnauthorized, resp.StatusCode)
+	})
+}
@@ -0,0 +1,73 @@
+package unit_tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/config"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/logger"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/services/loyaltyservice"
+	"github.com/AlexBlackNn/authloyalty/loyalty/tests/unit_tests/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTransactionsPagination(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	transactions := []domain.Transaction{
+		{ID: "3b0e1a6c-0a3e-4a8e-9d5f-2f1a8c2b7d01", Amount: 50, Type: "w", CreatedAt: createdAt.Add(2 * time.Hour)},
+		{ID: "3b0e1a6c-0a3e-4a8e-9d5f-2f1a8c2b7d02", Amount: 70, Type: "d", CreatedAt: createdAt.Add(time.Hour)},
+		{ID: "3b0e1a6c-0a3e-4a8e-9d5f-2f1a8c2b7d03", Amount: 100, Type: "d", CreatedAt: createdAt},
+	}
+	filter := &domain.TransactionFilter{UUID: "79d3ac44-5857-4185-ba92-1a224fbacb51", Limit: 2}
+
+	loyaltyStorageMock := mocks.NewMockloyaltyStorage(ctrl)
+	gomock.InOrder(
+		// one more transaction is requested to find out if there is a next page
+		loyaltyStorageMock.EXPECT().
+			GetTransactions(gomock.Any(), &domain.TransactionFilter{UUID: filter.UUID, Limit: 3}).
+			Return(transactions, nil),
+		loyaltyStorageMock.EXPECT().
+			GetTransactions(gomock.Any(), &domain.TransactionFilter{
+				UUID:  filter.UUID,
+				Limit: 3,
+				After: &domain.TransactionCursor{CreatedAt: transactions[1].CreatedAt, ID: transactions[1].ID},
+			}).
+			Return(transactions[2:], nil),
+	)
+	brokerMock := mocks.NewMockloyaltyBroker(ctrl)
+	brokerMock.EXPECT().
+		GetMessageChan().
+		Return(nil).
+		AnyTimes()
+
+	loyalService := loyaltyservice.New(cfg, log, brokerMock, loyaltyStorageMock)
+
+	page, err := loyalService.GetTransactions(context.Background(), filter)
+	require.NoError(t, err)
+	require.Equal(t, transactions[:2], page.Transactions)
+	require.Equal(t, &domain.TransactionCursor{CreatedAt: transactions[1].CreatedAt, ID: transactions[1].ID}, page.Next)
+
+	filter.After = page.Next
+	page, err = loyalService.GetTransactions(context.Background(), filter)
+	require.NoError(t, err)
+	require.Equal(t, transactions[2:], page.Transactions)
+	require.Nil(t, page.Next)
+}
+
+func (ls *LoyaltyAddSuite) TestHttpGetTransactionsWithoutToken() {
+	ls.Run("get transactions without token", func() {
+		resp, err := ls.client.Get(ls.srv.URL + "/loyalty/79d3ac44-5857-4185-ba92-1a224fbacb51/transactions")
+		ls.NoError(err)
+		defer resp.Body.Close()
+		ls.Equal(http.StatusUnauthorized, resp.StatusCode)
+	})
+}
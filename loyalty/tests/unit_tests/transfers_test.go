@@ -0,0 +1,91 @@
+package unit_tests
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/config"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/logger"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/services/loyaltyservice"
+	"github.com/AlexBlackNn/authloyalty/loyalty/internal/storage"
+	"github.com/AlexBlackNn/authloyalty/loyalty/tests/unit_tests/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransfer(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const (
+		sender    = "79d3ac44-5857-4185-ba92-1a224fbacb51"
+		recipient = "1f6c2f4e-3b1a-4d7e-9c51-0a2b3c4d5e6f"
+	)
+	// storage keeps points sent within a day
+	var sent int
+	loyaltyStorageMock := mocks.NewMockloyaltyStorage(ctrl)
+	loyaltyStorageMock.EXPECT().
+		Transfer(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, transfer *domain.Transfer) (*domain.Transfer, error) {
+			require.Equal(t, cfg.Transfers.DailyLimit, transfer.DailyLimit)
+			// storage orders account locks by canonical uuids
+			require.Equal(t, sender, transfer.From)
+			require.Equal(t, recipient, transfer.To)
+			// received points expire like deposited ones
+			require.NotNil(t, transfer.ExpiresAt)
+			if sent+transfer.Amount > transfer.DailyLimit {
+				return nil, storage.ErrTransferLimitExceeded
+			}
+			sent += transfer.Amount
+			transfer.ID = "5a1c3e2d-7b6f-4a9e-8d0c-1b2a3c4d5e6f"
+			return transfer, nil
+		}).
+		Times(2)
+	brokerMock := mocks.NewMockloyaltyBroker(ctrl)
+	brokerMock.EXPECT().
+		GetMessageChan().
+		Return(nil).
+		AnyTimes()
+
+	loyalService := loyaltyservice.New(cfg, log, brokerMock, loyaltyStorageMock)
+	ctx := context.Background()
+
+	transfer, err := loyalService.Transfer(ctx, &domain.Transfer{
+		From: sender, To: recipient, Amount: cfg.Transfers.DailyLimit, Comment: "gift",
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, transfer.ID)
+	_, err = loyalService.Transfer(ctx, &domain.Transfer{
+		From: sender, To: strings.ToUpper(recipient), Amount: 1, Comment: "gift",
+	})
+	require.ErrorIs(t, err, loyaltyservice.ErrTransferLimitExceeded)
+	// transfer to the same account doesn't reach storage
+	_, err = loyalService.Transfer(ctx, &domain.Transfer{
+		From: sender, To: sender, Amount: 1, Comment: "gift",
+	})
+	require.ErrorIs(t, err, loyaltyservice.ErrSelfTransfer)
+	// uuids are compared canonicalized, the same account in upper case is
+	// still self-transfer
+	_, err = loyalService.Transfer(ctx, &domain.Transfer{
+		From: sender, To: strings.ToUpper(sender), Amount: 1, Comment: "gift",
+	})
+	require.ErrorIs(t, err, loyaltyservice.ErrSelfTransfer)
+}
+
+func (ls *LoyaltyAddSuite) TestHttpTransferWithoutToken() {
+	ls.Run("transfer without token", func() {
+		resp, err := ls.client.Post(
+			ls.srv.URL+"/loyalty/transfer",
+			"application/json",
+			strings.NewReader(`{"to": "1f6c2f4e-3b1a-4d7e-9c51-0a2b3c4d5e6f", "amount": 100, "comment": "gift"}`),
+		)
+		ls.NoError(err)
+		defer resp.Body.Close()
+		ls.Equal(http.StatusUnauthorized, resp.StatusCode)
+	})
+}
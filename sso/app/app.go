@@ -11,8 +11,10 @@ import (
 	"github.com/AlexBlackNn/authloyalty/sso/internal/config"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/dto"
+	jwtlib "github.com/AlexBlackNn/authloyalty/sso/internal/lib/jwt"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/logger"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/services/authservice"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/services/outboxrelay"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/storage"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/storage/objectstorage"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/storage/patroni"
@@ -34,6 +36,7 @@ type userStorage interface {
 		ctx context.Context,
 		email string,
 		passHash []byte,
+		newEvent func(uuid string) (domain.OutboxMessage, error),
 	) (string, error)
 	GetUser(
 		ctx context.Context,
@@ -76,6 +79,8 @@ type App struct {
 	ServerProducer      sendCloser
 	ServerOpenTelemetry *trace.TracerProvider
 	ServerObjectStorage objectStorage
+	OutboxRelay         *outboxrelay.Relay
+	AuthService         *authservice.Auth
 }
 
 func New() (*App, error) {
@@ -106,6 +111,11 @@ func New() (*App, error) {
 		return nil, err
 	}
 
+	signingKey, err := jwtlib.NewSigningKey(cfg.JWT.KeyID, cfg.JWT.Algorithm, cfg.JWT.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
 	authService := authservice.New(
 		cfg,
 		log,
@@ -113,8 +123,13 @@ func New() (*App, error) {
 		tknStorage,
 		producer,
 		objStorage,
+		usrStorage,
+		jwtlib.NewKeyring(signingKey),
 	)
 
+	// publishes events saved to outbox with data changes
+	relay := outboxrelay.New(cfg, log, usrStorage, producer)
+
 	// http server
 	serverHttp, err := serverhttp.New(cfg, log, authService)
 	if err != nil {
@@ -141,6 +156,8 @@ func New() (*App, error) {
 		ServerProducer:      producer,
 		ServerOpenTelemetry: tp,
 		ServerObjectStorage: objStorage,
+		OutboxRelay:         relay,
+		AuthService:         authService,
 	}, nil
 }
 
@@ -169,6 +186,10 @@ func (a *App) Start(ctx context.Context) error {
 	errGRPCChan := a.startGRPCServer()
 	log.Info("http server starting")
 	errHTTPChan := a.startHTTPServer()
+	log.Info("outbox relay starting")
+	go a.OutboxRelay.Run(ctx)
+	log.Info("registration retry starting")
+	go a.AuthService.RunRegistrationRetry(ctx)
 	select {
 	case <-ctx.Done():
 		return a.Stop()
@@ -9,6 +9,7 @@ import (
 	"github.com/AlexBlackNn/authloyalty/sso/internal/config"
 	v1 "github.com/AlexBlackNn/authloyalty/sso/internal/handlersgrpc/grpc/v1"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/interceptors"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/lib/clientip"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/services/authservice"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
@@ -28,13 +29,17 @@ func New(
 	log *slog.Logger,
 	authService *authservice.Auth,
 ) (*App, error) {
+	proxies, err := clientip.NewTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
 	// Создаем gRPC сервер с опциями
 	server := grpc.NewServer(
 		grpc.UnaryInterceptor(interceptors.NewTracing(otel.Tracer("sso service")).GetInterceptor()),
 	)
 
 	// Регистрируем gRPC сервисы
-	v1.Register(server, authService)
+	v1.Register(server, authService, proxies)
 
 	// Включаем gRPC Reflection для удобства тестирования
 	reflection.Register(server)
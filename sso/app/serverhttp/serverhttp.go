@@ -1,6 +1,7 @@
 package serverhttp
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -8,8 +9,11 @@ import (
 
 	"github.com/AlexBlackNn/authloyalty/sso/cmd/sso/router"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/config"
+	grpcv1 "github.com/AlexBlackNn/authloyalty/sso/internal/handlersgrpc/grpc/v1"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/handlershttp/http/v1"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/lib/clientip"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/services/authservice"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 )
 
 // App service consists all entities needed to work.
@@ -20,6 +24,7 @@ type App struct {
 	authService   *authservice.Auth
 	HandlersV1    v1.AuthHandlers
 	HealthChecker v1.HealthHandlers
+	GatewayV2     http.Handler
 }
 
 // New creates App collecting handlers and server
@@ -29,8 +34,18 @@ func New(
 	authService *authservice.Auth,
 ) (*App, error) {
 
-	projectHandlersV1 := v1.New(log, cfg, authService)
+	proxies, err := clientip.NewTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	projectHandlersV1 := v1.New(log, cfg, authService, proxies)
 	healthHandlersV1 := v1.NewHealth(log, authService)
+	// REST facade generated from the grpc service, see /api/v2 routes
+	gatewayV2 := runtime.NewServeMux()
+	err = grpcv1.RegisterGateway(context.Background(), gatewayV2, authService, proxies)
+	if err != nil {
+		return nil, err
+	}
 	srv := &http.Server{
 		Addr: fmt.Sprintf(cfg.Address),
 		Handler: router.NewChiRouter(
@@ -38,6 +53,7 @@ func New(
 			log,
 			projectHandlersV1,
 			healthHandlersV1,
+			gatewayV2,
 		),
 		ReadTimeout:  time.Duration(cfg.ServerTimeout.ReadTimeout) * time.Second,
 		WriteTimeout: time.Duration(cfg.ServerTimeout.WriteTimeout) * time.Second,
@@ -49,5 +65,6 @@ func New(
 		Srv:           srv,
 		HandlersV1:    projectHandlersV1,
 		HealthChecker: healthHandlersV1,
+		GatewayV2:     gatewayV2,
 	}, nil
 }
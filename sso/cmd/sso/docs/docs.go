@@ -20,6 +20,133 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Provides public keys in JWK format to verify tokens offline.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "JWKS",
+                "responses": {
+                    "200": {
+                        "description": "public keys",
+                        "schema": {
+                            "$ref": "#/definitions/dto.JWKS"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/email/change": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces email of the token owner. The new email is not verified, verification email is sent to it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "ChangeEmail",
+                "parameters": [
+                    {
+                        "description": "ChangeEmail request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ChangeEmail"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "email changed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/email/verify": {
+            "post": {
+                "description": "Verifies user email using token sent to the email on registration.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "VerifyEmail",
+                "parameters": [
+                    {
+                        "description": "VerifyEmail request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyEmail"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "email verified",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/email/verify/resend": {
+            "post": {
+                "description": "Sends a new email verification token to user email. Responds with success for unknown and already verified emails too.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "ResendEmailVerification",
+                "parameters": [
+                    {
+                        "description": "ResendEmailVerification request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResendEmailVerification"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "email verification requested",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/auth/healthz": {
             "get": {
                 "description": "Определяет, нужно ли перезагрузить сервис",
@@ -65,6 +192,45 @@ const docTemplate = `{
                 }
             }
         },
+        "/auth/keys/rotate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Promotes a new signing key. The previous key is accepted until the grace period ends. Admins only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "RotateSigningKey",
+                "parameters": [
+                    {
+                        "description": "RotateSigningKey request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RotateSigningKey"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "key rotated",
+                        "schema": {
+                            "$ref": "#/definitions/dto.KeyRotationResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/auth/login": {
             "post": {
                 "description": "Authenticates a user and returns access and refresh tokens.",
@@ -91,7 +257,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "201": {
-                        "description": "Login successful",
+                        "description": "Login successful, only mfa_token is returned if MFA is enabled",
                         "schema": {
                             "$ref": "#/definitions/dto.Response"
                         }
@@ -133,28 +299,73 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/ready": {
-            "get": {
-                "description": "Определяет можно ли подавать трафик на сервис",
+        "/auth/mfa/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Enables MFA if code matches enrolled secret, returns one-time recovery codes.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Health"
+                    "Auth"
+                ],
+                "summary": "ConfirmMFA",
+                "parameters": [
+                    {
+                        "description": "ConfirmMFA request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ConfirmMFA"
+                        }
+                    }
                 ],
-                "summary": "Проверка готовности приложения",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "mfa enabled",
                         "schema": {
-                            "$ref": "#/definitions/dto.Response"
+                            "$ref": "#/definitions/dto.RecoveryCodesResponse"
                         }
                     }
                 }
             }
         },
-        "/auth/refresh": {
+        "/auth/mfa/enroll": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generates TOTP secret, MFA is enabled after the secret is confirmed with a code.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "EnrollMFA",
+                "responses": {
+                    "200": {
+                        "description": "secret and otpauth uri",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MFAEnrollmentResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/mfa/verify": {
             "post": {
+                "description": "Completes login with mfa_token returned by Login and TOTP or recovery code.",
                 "consumes": [
                     "application/json"
                 ],
@@ -164,21 +375,21 @@ const docTemplate = `{
                 "tags": [
                     "Auth"
                 ],
-                "summary": "Refresh",
+                "summary": "VerifyMFA",
                 "parameters": [
                     {
-                        "description": "Refresh request",
+                        "description": "VerifyMFA request",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.Refresh"
+                            "$ref": "#/definitions/dto.VerifyMFA"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Refresh successful",
+                        "description": "Login successful",
                         "schema": {
                             "$ref": "#/definitions/dto.Response"
                         }
@@ -186,9 +397,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/registration": {
+        "/auth/password/reset": {
             "post": {
-                "description": "User registration",
+                "description": "Sends password reset token to user email. Responds with success for unknown emails too.",
                 "consumes": [
                     "application/json"
                 ],
@@ -198,53 +409,665 @@ const docTemplate = `{
                 "tags": [
                     "Auth"
                 ],
-                "summary": "Registration",
+                "summary": "RequestPasswordReset",
                 "parameters": [
                     {
-                        "description": "Register request",
+                        "description": "RequestPasswordReset request",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.Register"
+                            "$ref": "#/definitions/dto.RequestPasswordReset"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Register successful",
+                    "200": {
+                        "description": "password reset requested",
                         "schema": {
                             "$ref": "#/definitions/dto.Response"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.Login": {
-            "type": "object",
-            "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string"
+        },
+        "/auth/password/reset/confirm": {
+            "post": {
+                "description": "Sets new password using password reset token and revokes all user sessions.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "ConfirmPasswordReset",
+                "parameters": [
+                    {
+                        "description": "ConfirmPasswordReset request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ConfirmPasswordReset"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "password changed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
                 }
             }
         },
-        "dto.Logout": {
-            "type": "object",
-            "properties": {
-                "token": {
-                    "type": "string"
+        "/auth/ready": {
+            "get": {
+                "description": "Определяет можно ли подавать трафик на сервис",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Проверка готовности приложения",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
                 }
             }
         },
-        "dto.Refresh": {
-            "type": "object",
-            "properties": {
-                "token": {
+        "/auth/refresh": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Refresh",
+                "parameters": [
+                    {
+                        "description": "Refresh request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.Refresh"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Refresh successful",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/registration": {
+            "post": {
+                "description": "User registration",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Registration",
+                "parameters": [
+                    {
+                        "description": "Register request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.Register"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Register successful",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/roles/grant": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Assigns role to user. Permissions of the role are added to tokens issued afterwards. Admins only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "GrantRole",
+                "parameters": [
+                    {
+                        "description": "GrantRole request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserRole"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "role granted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/roles/revoke": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes role from user. Tokens issued before keep the role permissions until they expire. Admins only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "RevokeRole",
+                "parameters": [
+                    {
+                        "description": "RevokeRole request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserRole"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "role revoked",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists sessions (devices) of the user. Sessions of other users are available to admins only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "ListSessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, token owner if empty",
+                        "name": "user_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "sessions",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SessionsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions/revoke": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes all tokens of the session. Sessions of other users can be revoked by admins only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "RevokeSession",
+                "parameters": [
+                    {
+                        "description": "RevokeSession request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RevokeSession"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions/revoke-all": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Logs out everywhere: revokes tokens of all user sessions, including the current one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "RevokeAllSessions",
+                "parameters": [
+                    {
+                        "description": "RevokeAllSessions request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RevokeAllSessions"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "sessions revoked",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RevokeSessionsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/users/block": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Blocks user login until the time or until unblocked and revokes user sessions. Admins only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "BlockUser",
+                "parameters": [
+                    {
+                        "description": "BlockUser request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BlockUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "user blocked",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/users/delete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes user and revokes user sessions. Admins only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "DeleteUser",
+                "parameters": [
+                    {
+                        "description": "DeleteUser request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserID"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "user deleted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/users/unblock": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes login block of the user. Admins only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "UnblockUser",
+                "parameters": [
+                    {
+                        "description": "UnblockUser request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserID"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "user unblocked",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/users/unlock": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes login lockout after failed attempts of the user account and/or IP. Admins only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "UnlockLogin",
+                "parameters": [
+                    {
+                        "description": "UnlockLogin request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UnlockLogin"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "login unlocked",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.BlockUser": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "until": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ChangeEmail": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ConfirmMFA": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ConfirmPasswordReset": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "description": "OKP (Ed25519) public key params.",
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "description": "RSA public key params.",
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.JWKS": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.JWK"
+                    }
+                }
+            }
+        },
+        "dto.KeyRotationResponse": {
+            "type": "object",
+            "properties": {
+                "active_key_id": {
+                    "type": "string"
+                },
+                "retire_at": {
+                    "type": "string"
+                },
+                "retiring_key_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Login": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Logout": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.MFAEnrollmentResponse": {
+            "type": "object",
+            "properties": {
+                "otpauth_uri": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Refresh": {
+            "type": "object",
+            "properties": {
+                "token": {
                     "type": "string"
                 }
             }
@@ -272,6 +1095,22 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.RequestPasswordReset": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ResendEmailVerification": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
         "dto.Response": {
             "type": "object",
             "properties": {
@@ -281,6 +1120,9 @@ const docTemplate = `{
                 "error": {
                     "type": "string"
                 },
+                "mfa_token": {
+                    "type": "string"
+                },
                 "refresh_token": {
                     "type": "string"
                 },
@@ -292,6 +1134,112 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.RevokeAllSessions": {
+            "type": "object",
+            "properties": {
+                "user_id": {
+                    "description": "UserID is required to revoke sessions of other users, own sessions are revoked if empty.",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RevokeSession": {
+            "type": "object",
+            "required": [
+                "session_id"
+            ],
+            "properties": {
+                "session_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "UserID is required to revoke sessions of other users, own sessions are revoked if empty.",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RevokeSessionsResponse": {
+            "type": "object",
+            "properties": {
+                "revoked": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RotateSigningKey": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "type": "string",
+                    "enum": [
+                        "RS256",
+                        "EdDSA"
+                    ]
+                }
+            }
+        },
+        "dto.Session": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "refreshed_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.SessionsResponse": {
+            "type": "object",
+            "properties": {
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Session"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UnlockLogin": {
+            "type": "object",
+            "properties": {
+                "ip": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UserID": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
         "dto.UserResponse": {
             "type": "object",
             "properties": {
@@ -317,6 +1265,49 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "dto.UserRole": {
+            "type": "object",
+            "required": [
+                "role",
+                "user_id"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.VerifyEmail": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.VerifyMFA": {
+            "type": "object",
+            "required": [
+                "mfa_token"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "mfa_token": {
+                    "type": "string"
+                },
+                "recovery_code": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -3,6 +3,7 @@ package router
 import (
 	"compress/gzip"
 	"log/slog"
+	"net/http"
 	"time"
 
 	_ "github.com/AlexBlackNn/authloyalty/sso/cmd/sso/docs"
@@ -22,6 +23,7 @@ func NewChiRouter(
 	log *slog.Logger,
 	authHandlerV1 v1.AuthHandlers,
 	healthHandlerV1 v1.HealthHandlers,
+	gatewayV2 http.Handler,
 ) *chi.Mux {
 
 	router := chi.NewRouter()
@@ -46,12 +48,33 @@ func NewChiRouter(
 		r.Post("/registration", authHandlerV1.Register)
 		r.Post("/refresh", authHandlerV1.Refresh)
 		r.Get("/info", authHandlerV1.Info)
+		r.Post("/keys/rotate", authHandlerV1.RotateSigningKey)
+		r.Post("/roles/grant", authHandlerV1.GrantRole)
+		r.Post("/roles/revoke", authHandlerV1.RevokeRole)
+		r.Get("/sessions", authHandlerV1.ListSessions)
+		r.Post("/sessions/revoke", authHandlerV1.RevokeSession)
+		r.Post("/sessions/revoke-all", authHandlerV1.RevokeAllSessions)
+		r.Post("/password/reset", authHandlerV1.RequestPasswordReset)
+		r.Post("/password/reset/confirm", authHandlerV1.ConfirmPasswordReset)
+		r.Post("/email/verify", authHandlerV1.VerifyEmail)
+		r.Post("/email/verify/resend", authHandlerV1.ResendEmailVerification)
+		r.Post("/mfa/enroll", authHandlerV1.EnrollMFA)
+		r.Post("/mfa/confirm", authHandlerV1.ConfirmMFA)
+		r.Post("/mfa/verify", authHandlerV1.VerifyMFA)
+		r.Post("/users/unlock", authHandlerV1.UnlockLogin)
+		r.Post("/users/block", authHandlerV1.BlockUser)
+		r.Post("/users/unblock", authHandlerV1.UnblockUser)
+		r.Post("/users/delete", authHandlerV1.DeleteUser)
+		r.Post("/email/change", authHandlerV1.ChangeEmail)
 	})
+	// generated from grpc service, so both transports share behaviour and error mapping
+	router.Mount("/api/v2", gatewayV2)
 	router.Route("/", func(r chi.Router) {
 		r.Get("/swagger/*", httpSwagger.Handler(
 			httpSwagger.URL("http://localhost:8000/swagger/doc.json"),
 		))
 		r.Get("/metrics", promhttp.Handler().ServeHTTP)
+		r.Get("/.well-known/jwks.json", authHandlerV1.JWKS)
 	})
 
 	return router
@@ -30,6 +30,25 @@ type KafkaConfig struct {
 	URL               string `yaml:"kafkaUrl" env-required:"true"`
 	SchemaRegistryURL string `yaml:"schemaRegistryURL" env-required:"true"`
 	Topic             string `yaml:"topic" env-required:"true"`
+	// PasswordResetTopic is read by mailer to deliver password reset links.
+	PasswordResetTopic string `yaml:"passwordResetTopic" env-default:"password_reset"`
+	// EmailVerificationTopic is read by mailer to deliver email verification links.
+	EmailVerificationTopic string `yaml:"emailVerificationTopic" env-default:"email_verification"`
+	// UserEventsTopic is read by services reacting to user lifecycle changes.
+	UserEventsTopic string `yaml:"userEventsTopic" env-default:"user_events"`
+}
+
+type EmailVerificationConfig struct {
+	// AllowUnverifiedLogin lets users get tokens before email is verified.
+	AllowUnverifiedLogin bool `yaml:"allowUnverifiedLogin" env-default:"true"`
+	// HoldRegistrationBonus delays registration event until email is verified,
+	// so loyalty adds registration bonus to verified users only.
+	HoldRegistrationBonus bool `yaml:"holdRegistrationBonus" env-default:"false"`
+	// TokenTtl is how long an email verification link is valid.
+	TokenTtl time.Duration `yaml:"tokenTtl" env-default:"24h"`
+	// ResendInterval is the minimal interval between verification emails
+	// requested by ResendEmailVerification to the same user.
+	ResendInterval time.Duration `yaml:"resendInterval" env-default:"1m"`
 }
 
 type MinioConfig struct {
@@ -53,15 +72,114 @@ type ServerHandlersTimeoutsCongig struct {
 	RefreshTimeoutMs  int64 `yaml:"refreshTimeoutMs" env-required:"true"`
 }
 
+type JWTConfig struct {
+	// Algorithm is used to sign tokens: RS256 or EdDSA.
+	Algorithm string `yaml:"algorithm" env-default:"EdDSA"`
+	// KeyID is stamped to "kid" header of tokens signed with PrivateKey.
+	KeyID string `yaml:"keyId" env-required:"true"`
+	// PrivateKey is a PEM encoded PKCS#8 private key (PKCS#1 is also accepted for RS256).
+	PrivateKey string `yaml:"privateKey" env:"JWT_PRIVATE_KEY" env-required:"true"`
+	// RotationGracePeriod is how long a previous key is accepted after rotation,
+	// should not be less than refresh token ttl to avoid logging out users.
+	RotationGracePeriod time.Duration `yaml:"rotationGracePeriod" env-default:"240h"`
+	// ReloadInterval is how often keys rotated by other instances are loaded from storage.
+	ReloadInterval time.Duration `yaml:"reloadInterval" env-default:"1m"`
+	// UnknownKeyReloadInterval limits how often token signed by unknown key
+	// makes keys load from storage before the next ReloadInterval.
+	UnknownKeyReloadInterval time.Duration `yaml:"unknownKeyReloadInterval" env-default:"10s"`
+}
+
+type MFAConfig struct {
+	// Issuer is shown by authenticator apps next to the account name.
+	Issuer string `yaml:"issuer" env-default:"authloyalty"`
+	// EncryptionKey is a base64 encoded 32 bytes key to encrypt TOTP secrets in storage.
+	EncryptionKey string `yaml:"encryptionKey" env:"MFA_ENCRYPTION_KEY" env-required:"true"`
+	// ChallengeTokenTtl is how long a user may complete login with a TOTP code.
+	ChallengeTokenTtl time.Duration `yaml:"challengeTokenTtl" env-default:"5m"`
+	// RecoveryCodes is a number of one-time recovery codes issued on MFA confirmation.
+	RecoveryCodes int `yaml:"recoveryCodes" env-default:"10"`
+	// RequiredForAdmins rejects admin operations made with tokens issued without MFA.
+	RequiredForAdmins bool `yaml:"requiredForAdmins" env-default:"true"`
+}
+
+// LoginThrottlingConfig sets backoff of logins after failed attempts. Attempts
+// are counted per account and per IP, so both password guessing for a single
+// account and credential stuffing from a single IP are slowed down.
+type LoginThrottlingConfig struct {
+	// Window is how long failed attempts are counted since the last one.
+	Window time.Duration `yaml:"window" env-default:"1h"`
+	// FreeAttempts is a number of failed attempts before backoff starts.
+	FreeAttempts int64 `yaml:"freeAttempts" env-default:"3"`
+	// BaseDelay is a delay after the first failed attempt over FreeAttempts,
+	// the delay doubles with each next failed attempt up to MaxDelay.
+	BaseDelay time.Duration `yaml:"baseDelay" env-default:"1s"`
+	MaxDelay  time.Duration `yaml:"maxDelay" env-default:"5m"`
+	// LockoutAttempts is a number of failed attempts locking the account for
+	// LockoutDuration or until an admin unlocks it.
+	LockoutAttempts int64         `yaml:"lockoutAttempts" env-default:"10"`
+	LockoutDuration time.Duration `yaml:"lockoutDuration" env-default:"1h"`
+	// IPFreeAttempts and IPLockoutAttempts are thresholds of failed attempts
+	// from one IP, they are higher as many users might share one IP.
+	IPFreeAttempts    int64 `yaml:"ipFreeAttempts" env-default:"20"`
+	IPLockoutAttempts int64 `yaml:"ipLockoutAttempts" env-default:"100"`
+}
+
+// OutboxConfig sets relay publishing events saved to outbox table together
+// with data changes they describe.
+type OutboxConfig struct {
+	// PollInterval is how often the relay looks for pending events.
+	PollInterval time.Duration `yaml:"pollInterval" env-default:"1s"`
+	BatchSize    int           `yaml:"batchSize" env-default:"100"`
+	// DeliveryTimeout is how long the relay waits for kafka to confirm delivery,
+	// events not confirmed in time are published again. Claimed batch is leased
+	// for BatchSize * DeliveryTimeout, events of a crashed relay are published
+	// by other instances after the lease.
+	DeliveryTimeout time.Duration `yaml:"deliveryTimeout" env-default:"30s"`
+	// RetryDelay is a delay after the first failed attempt, the delay doubles
+	// with each next failed attempt up to MaxRetryDelay.
+	RetryDelay    time.Duration `yaml:"retryDelay" env-default:"1s"`
+	MaxRetryDelay time.Duration `yaml:"maxRetryDelay" env-default:"5m"`
+}
+
+// RegistrationRetryConfig sets reconciler re-sending registration message to
+// users stuck in failed or inProgress message status.
+type RegistrationRetryConfig struct {
+	// Interval is how often users are scanned.
+	Interval time.Duration `yaml:"interval" env-default:"1m"`
+	// StaleAfter is how long after registration users in inProgress status are
+	// considered stuck.
+	StaleAfter time.Duration `yaml:"staleAfter" env-default:"10m"`
+	BatchSize  int           `yaml:"batchSize" env-default:"100"`
+	// MaxAttempts is a number of re-sends after which the user is given up.
+	MaxAttempts int `yaml:"maxAttempts" env-default:"5"`
+	// RetryDelay is a delay before the user is scanned again after the first
+	// re-send, the delay doubles with each next re-send up to MaxRetryDelay.
+	RetryDelay    time.Duration `yaml:"retryDelay" env-default:"1m"`
+	MaxRetryDelay time.Duration `yaml:"maxRetryDelay" env-default:"1h"`
+}
+
+// RegistrationBonusConfig sets loyalty bonus carried by registration event.
+type RegistrationBonusConfig struct {
+	// Amount is loyalty points granted to registered user, 0 creates loyalty
+	// account without bonus. Negative amount is rejected on config load.
+	Amount int64 `yaml:"amount" env-default:"100"`
+	// Campaign is code of marketing campaign granting the bonus, loyalty
+	// stores it as operation comment.
+	Campaign string `yaml:"campaign" env-default:"registration"`
+}
+
 type Config struct {
 	// without this param will be used "local" as param value
 	Env             string        `yaml:"env" env-default:"local"`
 	AccessTokenTtl  time.Duration `yaml:"access_token_ttl"  env-required:"true"`
 	RefreshTokenTtl time.Duration `yaml:"refresh_token_ttl"  env-required:"true"`
-	RedisAddress    string        `yaml:"redis_address"`
+	// PasswordResetTokenTtl is how long a password reset link is valid.
+	PasswordResetTokenTtl time.Duration `yaml:"password_reset_token_ttl" env-default:"30m"`
+	RedisAddress          string        `yaml:"redis_address"`
 	// without this param can't work
 	StoragePath            string                       `yaml:"storage_path"`
 	ServiceSecret          string                       `yaml:"service_secret" env-required:"true"`
+	JWT                    JWTConfig                    `yaml:"jwt"`
 	ServerTimeout          ServerTimeoutConfig          `yaml:"server_timeout"`
 	ServerHandlersTimeouts ServerHandlersTimeoutsCongig `yaml:"server_handlers_timeouts"`
 	GRPC                   GRPCConfig                   `yaml:"grpc"`
@@ -69,9 +187,19 @@ type Config struct {
 	StoragePatroni         StoragePatroniConfig         `yaml:"storage_patroni"`
 	Kafka                  KafkaConfig                  `yaml:"kafka"`
 	Minio                  MinioConfig                  `yaml:"minio"`
+	EmailVerification      EmailVerificationConfig      `yaml:"email_verification"`
+	MFA                    MFAConfig                    `yaml:"mfa"`
+	LoginThrottling        LoginThrottlingConfig        `yaml:"login_throttling"`
+	Outbox                 OutboxConfig                 `yaml:"outbox"`
+	RegistrationRetry      RegistrationRetryConfig      `yaml:"registration_retry"`
+	RegistrationBonus      RegistrationBonusConfig      `yaml:"registration_bonus"`
 	JaegerUrl              string                       `yaml:"jaeger_url"`
 	RateLimit              int                          `yaml:"rate_limit" `
 	Address                string                       `yaml:"address"`
+	// TrustedProxies are CIDRs or addresses of proxies allowed to forward client
+	// address in X-Forwarded-For and X-Real-IP, the headers of other clients are
+	// ignored.
+	TrustedProxies []string `yaml:"trusted_proxies"`
 }
 
 func New() *Config {
@@ -92,6 +220,12 @@ func MustLoadByPath(configPath string) *Config {
 	if err := cleanenv.ReadConfig(configPath, &cfg); err != nil {
 		panic("failed to read config " + err.Error())
 	}
+	if cfg.RegistrationBonus.Amount < 0 {
+		panic("registration_bonus.amount must not be negative")
+	}
+	if cfg.JWT.ReloadInterval <= 0 {
+		panic("jwt.reloadInterval must be positive")
+	}
 	return &cfg
 }
 
@@ -0,0 +1,14 @@
+package domain
+
+// Authentication methods put into "amr" claim of tokens (RFC 8176).
+const (
+	AuthMethodPassword = "pwd"
+	AuthMethodOTP      = "otp"
+)
+
+// MFAEnrollment is a TOTP secret waiting to be confirmed with a code.
+type MFAEnrollment struct {
+	Secret string
+	// URI is otpauth URI, authenticator apps scan it as QR code.
+	URI string
+}
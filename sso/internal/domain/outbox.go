@@ -0,0 +1,21 @@
+package domain
+
+// OutboxMessage is an event saved together with the data change it describes
+// and published to kafka by the outbox relay.
+type OutboxMessage struct {
+	ID    int64
+	Topic string
+	Key   string
+	// Type is a full protobuf message name of Payload.
+	Type     string
+	Payload  []byte
+	Attempts int
+}
+
+// UndeliveredRegistration is a user whose registration message was not
+// delivered to kafka.
+type UndeliveredRegistration struct {
+	UserID string
+	// Attempts is a number of times the message was re-sent.
+	Attempts int
+}
@@ -0,0 +1,34 @@
+package domain
+
+import "slices"
+
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// Permissions are embedded into access tokens, so services can authorize
+// requests without calling sso.
+const (
+	PermissionLoyaltyDeposit     = "loyalty:deposit"
+	PermissionLoyaltyWithdraw    = "loyalty:withdraw"
+	PermissionLoyaltyWithdrawAny = "loyalty:withdraw:any"
+	PermissionLoyaltyDLQReplay   = "loyalty:dlq:replay"
+	PermissionLoyaltyReadAny     = "loyalty:transactions:read:any"
+	PermissionLoyaltyReverse     = "loyalty:reverse"
+	PermissionKeysRotate         = "sso:keys:rotate"
+	PermissionRolesManage        = "sso:roles:manage"
+	PermissionSessionsManage     = "sso:sessions:manage"
+	PermissionUsersUnlock        = "sso:users:unlock"
+	PermissionUsersManage        = "sso:users:manage"
+)
+
+// HasRole checks if user has the role.
+func (u *User) HasRole(role string) bool {
+	return slices.Contains(u.Roles, role)
+}
+
+// HasPermission checks if any of user roles grants the permission.
+func (u *User) HasPermission(permission string) bool {
+	return slices.Contains(u.Permissions, permission)
+}
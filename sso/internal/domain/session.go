@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// Session is a login on a device. Its ID is the id of the token family
+// issued at Login or Register, so revoking a session revokes its tokens.
+type Session struct {
+	ID          string
+	UserID      string
+	UserAgent   string
+	IP          string
+	CreatedAt   time.Time
+	RefreshedAt time.Time
+	// Current is true if the session issued the token used in request.
+	Current bool
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"crypto"
+	"time"
+)
+
+// PublicKey is a key to verify tokens issued by sso.
+type PublicKey struct {
+	ID        string
+	Algorithm string
+	Key       crypto.PublicKey
+}
+
+// SigningKey is a stored signing key. Retiring keys are not used to sign new
+// tokens, but are accepted for verification until ExpiresAt.
+type SigningKey struct {
+	ID         string
+	Algorithm  string
+	PrivateKey string
+	Active     bool
+	ExpiresAt  time.Time
+}
+
+// KeyRotation is a result of signing key rotation.
+type KeyRotation struct {
+	ActiveKeyID   string
+	RetiringKeyID string
+	RetireAt      time.Time
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type User struct {
 	ID       string
 	Email    string
@@ -8,10 +10,30 @@ type User struct {
 	Name     string
 	Birthday string
 	Avatar   string
+	// VerifiedAt is nil until user email is verified.
+	VerifiedAt *time.Time
+	// MFAEnabled means login has to be completed with TOTP or recovery code.
+	MFAEnabled bool
+	// Blocked means user can't login until unblocked or block expires.
+	Blocked bool
+	// Roles and Permissions are loaded from user_roles and role_permissions.
+	Roles       []string
+	Permissions []string
+}
+
+// Verified reports whether user email is verified.
+func (u *User) Verified() bool {
+	return u.VerifiedAt != nil
 }
 
 type UserWithTokens struct {
 	User
 	AccessToken  string
 	RefreshToken string
+	// FamilyID links tokens issued since login, RefreshTokenID is jti of RefreshToken.
+	FamilyID       string
+	RefreshTokenID string
+	// MFAToken is issued instead of access and refresh tokens if login has to
+	// be completed with a second factor.
+	MFAToken string
 }
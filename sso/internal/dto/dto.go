@@ -1,9 +1,16 @@
 package dto
 
 import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
 	"fmt"
+	"math"
+	"math/big"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
 	"github.com/go-playground/validator/v10"
@@ -12,9 +19,16 @@ import (
 
 // DTO http and grpc structures.
 
+// Client describes device a request came from, it is filled by handlers.
+type Client struct {
+	UserAgent string `json:"-"`
+	IP        string `json:"-"`
+}
+
 type Login struct {
 	Email    string `json:"email" validate:"email"`
 	Password string `json:"password"`
+	Client   `json:"-"`
 }
 
 type UserInfo struct {
@@ -27,16 +41,91 @@ type Register struct {
 	Name     string `json:"name"`
 	Birthday string `json:"birthday"`
 	Avatar   string `json:"avatar"`
+	Client   `json:"-"`
 }
 
 type Refresh struct {
-	Token string `json:"token" validate:"jwt"`
+	Token  string `json:"token" validate:"jwt"`
+	Client `json:"-"`
 }
 
 type Logout struct {
 	Token string `json:"token" validate:"jwt"`
 }
 
+type RotateSigningKey struct {
+	Algorithm string `json:"algorithm" validate:"omitempty,oneof=RS256 EdDSA"`
+}
+
+type UserRole struct {
+	UserID string `json:"user_id" validate:"required,uuid"`
+	Role   string `json:"role" validate:"required"`
+}
+
+type RevokeSession struct {
+	SessionID string `json:"session_id" validate:"required,uuid"`
+	// UserID is required to revoke sessions of other users, own sessions are revoked if empty.
+	UserID string `json:"user_id" validate:"omitempty,uuid"`
+}
+
+type RevokeAllSessions struct {
+	// UserID is required to revoke sessions of other users, own sessions are revoked if empty.
+	UserID string `json:"user_id" validate:"omitempty,uuid"`
+}
+
+type RequestPasswordReset struct {
+	Email string `json:"email" validate:"email"`
+}
+
+type ConfirmPasswordReset struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type VerifyEmail struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type ResendEmailVerification struct {
+	Email string `json:"email" validate:"email"`
+}
+
+type ConfirmMFA struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// VerifyMFA completes login with either TOTP code or recovery code.
+type VerifyMFA struct {
+	MFAToken     string `json:"mfa_token" validate:"required"`
+	Code         string `json:"code" validate:"omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recovery_code" validate:"required_without=Code"`
+	Client       `json:"-"`
+}
+
+// UnlockLogin removes login lockout of the user account and/or IP.
+type UnlockLogin struct {
+	UserID string `json:"user_id" validate:"required_without=IP,omitempty,uuid"`
+	IP     string `json:"ip" validate:"omitempty,ip"`
+}
+
+// BlockUser blocks user login until Until, or until unblocked if Until is nil.
+type BlockUser struct {
+	UserID string     `json:"user_id" validate:"required,uuid"`
+	Reason string     `json:"reason" validate:"max=255"`
+	Until  *time.Time `json:"until"`
+}
+
+// UserID identifies user managed by admin.
+type UserID struct {
+	UserID string `json:"user_id" validate:"required,uuid"`
+}
+
+// ChangeEmail replaces email of the token owner, current password is required.
+type ChangeEmail struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
 // Output http structures.
 
 type Response struct {
@@ -45,6 +134,7 @@ type Response struct {
 	UserID       string `json:"user_id,omitempty"`
 	AccessToken  string `json:"access_token,omitempty"`
 	RefreshToken string `json:"refresh_token,omitempty"`
+	MFAToken     string `json:"mfa_token,omitempty"`
 }
 
 type UserResponse struct {
@@ -57,6 +147,61 @@ type UserResponse struct {
 	Avatar string `json:"avatar,omitempty"`
 }
 
+// JWK is a public key in JSON Web Key format (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid,omitempty"`
+	// RSA public key params.
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// OKP (Ed25519) public key params.
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type KeyRotationResponse struct {
+	Status        string `json:"status"`
+	ActiveKeyID   string `json:"active_key_id"`
+	RetiringKeyID string `json:"retiring_key_id"`
+	RetireAt      string `json:"retire_at"`
+}
+
+type Session struct {
+	ID          string `json:"id"`
+	UserAgent   string `json:"user_agent"`
+	IP          string `json:"ip"`
+	CreatedAt   string `json:"created_at"`
+	RefreshedAt string `json:"refreshed_at"`
+	Current     bool   `json:"current"`
+}
+
+type SessionsResponse struct {
+	Status   string    `json:"status"`
+	Sessions []Session `json:"sessions"`
+}
+
+type RevokeSessionsResponse struct {
+	Status  string `json:"status"`
+	Revoked int    `json:"revoked"`
+}
+
+type MFAEnrollmentResponse struct {
+	Status string `json:"status"`
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type RecoveryCodesResponse struct {
+	Status        string   `json:"status"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
 const StatusError = "Error"
 const StatusSuccess = "Success"
 
@@ -112,6 +257,32 @@ func ResponseErrorStatusConflict(
 	sendJSON(w, http.StatusMethodNotAllowed, dataMarshal)
 }
 
+func ResponseErrorForbidden(
+	w http.ResponseWriter,
+	message string,
+) {
+	dataMarshal, _ := easyjson.Marshal(Response{
+		Status: StatusError,
+		Error:  message,
+	})
+	sendJSON(w, http.StatusForbidden, dataMarshal)
+}
+
+// ResponseErrorTooManyRequests tells client when to retry with Retry-After header.
+func ResponseErrorTooManyRequests(
+	w http.ResponseWriter,
+	message string,
+	retryAfter time.Duration,
+) {
+	dataMarshal, _ := easyjson.Marshal(Response{
+		Status: StatusError,
+		Error:  message,
+	})
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+	sendJSON(w, http.StatusTooManyRequests, dataMarshal)
+}
+
 func ResponseErrorBadRequest(
 	w http.ResponseWriter,
 	message string,
@@ -144,6 +315,7 @@ func ResponseOKAccessRefresh(
 			UserID:       userWithTokens.ID,
 			AccessToken:  userWithTokens.AccessToken,
 			RefreshToken: userWithTokens.RefreshToken,
+			MFAToken:     userWithTokens.MFAToken,
 		},
 	)
 	sendJSON(w, http.StatusCreated, dataMarshal)
@@ -166,6 +338,102 @@ func UserResponseOk(
 	sendJSON(w, http.StatusOK, dataMarshal)
 }
 
+func ResponseOKKeyRotation(
+	w http.ResponseWriter,
+	keyRotation *domain.KeyRotation,
+) {
+	dataMarshal, _ := easyjson.Marshal(
+		KeyRotationResponse{
+			Status:        StatusSuccess,
+			ActiveKeyID:   keyRotation.ActiveKeyID,
+			RetiringKeyID: keyRotation.RetiringKeyID,
+			RetireAt:      keyRotation.RetireAt.Format(time.RFC3339),
+		},
+	)
+	sendJSON(w, http.StatusOK, dataMarshal)
+}
+
+func ResponseOKSessions(
+	w http.ResponseWriter,
+	sessions []domain.Session,
+) {
+	response := SessionsResponse{
+		Status:   StatusSuccess,
+		Sessions: make([]Session, 0, len(sessions)),
+	}
+	for _, session := range sessions {
+		response.Sessions = append(response.Sessions, Session{
+			ID:          session.ID,
+			UserAgent:   session.UserAgent,
+			IP:          session.IP,
+			CreatedAt:   session.CreatedAt.Format(time.RFC3339),
+			RefreshedAt: session.RefreshedAt.Format(time.RFC3339),
+			Current:     session.Current,
+		})
+	}
+	dataMarshal, _ := easyjson.Marshal(response)
+	sendJSON(w, http.StatusOK, dataMarshal)
+}
+
+func ResponseOKRevokeSessions(
+	w http.ResponseWriter,
+	revoked int,
+) {
+	dataMarshal, _ := easyjson.Marshal(
+		RevokeSessionsResponse{Status: StatusSuccess, Revoked: revoked},
+	)
+	sendJSON(w, http.StatusOK, dataMarshal)
+}
+
+func ResponseOKMFAEnrollment(
+	w http.ResponseWriter,
+	enrollment *domain.MFAEnrollment,
+) {
+	dataMarshal, _ := easyjson.Marshal(
+		MFAEnrollmentResponse{
+			Status: StatusSuccess,
+			Secret: enrollment.Secret,
+			URI:    enrollment.URI,
+		},
+	)
+	sendJSON(w, http.StatusOK, dataMarshal)
+}
+
+func ResponseOKRecoveryCodes(
+	w http.ResponseWriter,
+	recoveryCodes []string,
+) {
+	dataMarshal, _ := easyjson.Marshal(
+		RecoveryCodesResponse{Status: StatusSuccess, RecoveryCodes: recoveryCodes},
+	)
+	sendJSON(w, http.StatusOK, dataMarshal)
+}
+
+func ResponseOKJWKS(
+	w http.ResponseWriter,
+	publicKeys []domain.PublicKey,
+) {
+	jwks := JWKS{Keys: make([]JWK, 0, len(publicKeys))}
+	for _, publicKey := range publicKeys {
+		jwk := JWK{Use: "sig", Alg: publicKey.Algorithm, Kid: publicKey.ID}
+		switch key := publicKey.Key.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(key)
+		default:
+			continue
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+	dataMarshal, _ := easyjson.Marshal(jwks)
+	sendJSON(w, http.StatusOK, dataMarshal)
+}
+
 func ValidationError(errs validator.ValidationErrors) string {
 	var errMsgs []string
 
@@ -7,6 +7,7 @@ import (
 	easyjson "github.com/mailru/easyjson"
 	jlexer "github.com/mailru/easyjson/jlexer"
 	jwriter "github.com/mailru/easyjson/jwriter"
+	time "time"
 )
 
 // suppress unused package warning
@@ -17,7 +18,7 @@ var (
 	_ easyjson.Marshaler
 )
 
-func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto(in *jlexer.Lexer, out *UserResponse) {
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto(in *jlexer.Lexer, out *VerifyMFA) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -36,20 +37,12 @@ func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto(in *jle
 			continue
 		}
 		switch key {
-		case "status":
-			out.Status = string(in.String())
-		case "error":
-			out.Error = string(in.String())
-		case "user_id":
-			out.UserID = string(in.String())
-		case "name":
-			out.Name = string(in.String())
-		case "birthday":
-			out.Birth = string(in.String())
-		case "email":
-			out.Email = string(in.String())
-		case "avatar":
-			out.Avatar = string(in.String())
+		case "mfa_token":
+			out.MFAToken = string(in.String())
+		case "code":
+			out.Code = string(in.String())
+		case "recovery_code":
+			out.RecoveryCode = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -60,72 +53,52 @@ func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto(in *jle
 		in.Consumed()
 	}
 }
-func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto(out *jwriter.Writer, in UserResponse) {
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto(out *jwriter.Writer, in VerifyMFA) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"status\":"
+		const prefix string = ",\"mfa_token\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Status))
-	}
-	if in.Error != "" {
-		const prefix string = ",\"error\":"
-		out.RawString(prefix)
-		out.String(string(in.Error))
-	}
-	if in.UserID != "" {
-		const prefix string = ",\"user_id\":"
-		out.RawString(prefix)
-		out.String(string(in.UserID))
-	}
-	if in.Name != "" {
-		const prefix string = ",\"name\":"
-		out.RawString(prefix)
-		out.String(string(in.Name))
-	}
-	if in.Birth != "" {
-		const prefix string = ",\"birthday\":"
-		out.RawString(prefix)
-		out.String(string(in.Birth))
+		out.String(string(in.MFAToken))
 	}
-	if in.Email != "" {
-		const prefix string = ",\"email\":"
+	{
+		const prefix string = ",\"code\":"
 		out.RawString(prefix)
-		out.String(string(in.Email))
+		out.String(string(in.Code))
 	}
-	if in.Avatar != "" {
-		const prefix string = ",\"avatar\":"
+	{
+		const prefix string = ",\"recovery_code\":"
 		out.RawString(prefix)
-		out.String(string(in.Avatar))
+		out.String(string(in.RecoveryCode))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v UserResponse) MarshalJSON() ([]byte, error) {
+func (v VerifyMFA) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v UserResponse) MarshalEasyJSON(w *jwriter.Writer) {
+func (v VerifyMFA) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *UserResponse) UnmarshalJSON(data []byte) error {
+func (v *VerifyMFA) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *UserResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *VerifyMFA) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto(l, v)
 }
-func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto1(in *jlexer.Lexer, out *UserInfo) {
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto1(in *jlexer.Lexer, out *VerifyEmail) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -144,8 +117,8 @@ func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto1(in *jl
 			continue
 		}
 		switch key {
-		case "file_name":
-			out.FileName = string(in.String())
+		case "token":
+			out.Token = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -156,42 +129,42 @@ func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto1(in *jl
 		in.Consumed()
 	}
 }
-func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto1(out *jwriter.Writer, in UserInfo) {
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto1(out *jwriter.Writer, in VerifyEmail) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"file_name\":"
+		const prefix string = ",\"token\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.FileName))
+		out.String(string(in.Token))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v UserInfo) MarshalJSON() ([]byte, error) {
+func (v VerifyEmail) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto1(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v UserInfo) MarshalEasyJSON(w *jwriter.Writer) {
+func (v VerifyEmail) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto1(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *UserInfo) UnmarshalJSON(data []byte) error {
+func (v *VerifyEmail) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto1(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *UserInfo) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *VerifyEmail) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto1(l, v)
 }
-func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto2(in *jlexer.Lexer, out *Response) {
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto2(in *jlexer.Lexer, out *UserRole) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -210,16 +183,10 @@ func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto2(in *jl
 			continue
 		}
 		switch key {
-		case "status":
-			out.Status = string(in.String())
-		case "error":
-			out.Error = string(in.String())
 		case "user_id":
 			out.UserID = string(in.String())
-		case "access_token":
-			out.AccessToken = string(in.String())
-		case "refresh_token":
-			out.RefreshToken = string(in.String())
+		case "role":
+			out.Role = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -230,62 +197,47 @@ func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto2(in *jl
 		in.Consumed()
 	}
 }
-func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto2(out *jwriter.Writer, in Response) {
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto2(out *jwriter.Writer, in UserRole) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"status\":"
-		out.RawString(prefix[1:])
-		out.String(string(in.Status))
-	}
-	if in.Error != "" {
-		const prefix string = ",\"error\":"
-		out.RawString(prefix)
-		out.String(string(in.Error))
-	}
-	if in.UserID != "" {
 		const prefix string = ",\"user_id\":"
-		out.RawString(prefix)
+		out.RawString(prefix[1:])
 		out.String(string(in.UserID))
 	}
-	if in.AccessToken != "" {
-		const prefix string = ",\"access_token\":"
-		out.RawString(prefix)
-		out.String(string(in.AccessToken))
-	}
-	if in.RefreshToken != "" {
-		const prefix string = ",\"refresh_token\":"
+	{
+		const prefix string = ",\"role\":"
 		out.RawString(prefix)
-		out.String(string(in.RefreshToken))
+		out.String(string(in.Role))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Response) MarshalJSON() ([]byte, error) {
+func (v UserRole) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto2(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Response) MarshalEasyJSON(w *jwriter.Writer) {
+func (v UserRole) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto2(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Response) UnmarshalJSON(data []byte) error {
+func (v *UserRole) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto2(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Response) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *UserRole) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto2(l, v)
 }
-func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto3(in *jlexer.Lexer, out *Register) {
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto3(in *jlexer.Lexer, out *UserResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -304,14 +256,18 @@ func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto3(in *jl
 			continue
 		}
 		switch key {
-		case "email":
-			out.Email = string(in.String())
-		case "password":
-			out.Password = string(in.String())
+		case "status":
+			out.Status = string(in.String())
+		case "error":
+			out.Error = string(in.String())
+		case "user_id":
+			out.UserID = string(in.String())
 		case "name":
 			out.Name = string(in.String())
 		case "birthday":
-			out.Birthday = string(in.String())
+			out.Birth = string(in.String())
+		case "email":
+			out.Email = string(in.String())
 		case "avatar":
 			out.Avatar = string(in.String())
 		default:
@@ -324,31 +280,41 @@ func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto3(in *jl
 		in.Consumed()
 	}
 }
-func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto3(out *jwriter.Writer, in Register) {
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto3(out *jwriter.Writer, in UserResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"email\":"
+		const prefix string = ",\"status\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Email))
+		out.String(string(in.Status))
 	}
-	{
-		const prefix string = ",\"password\":"
+	if in.Error != "" {
+		const prefix string = ",\"error\":"
 		out.RawString(prefix)
-		out.String(string(in.Password))
+		out.String(string(in.Error))
 	}
-	{
+	if in.UserID != "" {
+		const prefix string = ",\"user_id\":"
+		out.RawString(prefix)
+		out.String(string(in.UserID))
+	}
+	if in.Name != "" {
 		const prefix string = ",\"name\":"
 		out.RawString(prefix)
 		out.String(string(in.Name))
 	}
-	{
+	if in.Birth != "" {
 		const prefix string = ",\"birthday\":"
 		out.RawString(prefix)
-		out.String(string(in.Birthday))
+		out.String(string(in.Birth))
 	}
-	{
+	if in.Email != "" {
+		const prefix string = ",\"email\":"
+		out.RawString(prefix)
+		out.String(string(in.Email))
+	}
+	if in.Avatar != "" {
 		const prefix string = ",\"avatar\":"
 		out.RawString(prefix)
 		out.String(string(in.Avatar))
@@ -357,29 +323,29 @@ func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto3(out *j
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Register) MarshalJSON() ([]byte, error) {
+func (v UserResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto3(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Register) MarshalEasyJSON(w *jwriter.Writer) {
+func (v UserResponse) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto3(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Register) UnmarshalJSON(data []byte) error {
+func (v *UserResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto3(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Register) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *UserResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto3(l, v)
 }
-func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto4(in *jlexer.Lexer, out *Refresh) {
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto4(in *jlexer.Lexer, out *UserInfo) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -398,8 +364,8 @@ func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto4(in *jl
 			continue
 		}
 		switch key {
-		case "token":
-			out.Token = string(in.String())
+		case "file_name":
+			out.FileName = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -410,42 +376,42 @@ func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto4(in *jl
 		in.Consumed()
 	}
 }
-func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto4(out *jwriter.Writer, in Refresh) {
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto4(out *jwriter.Writer, in UserInfo) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"token\":"
+		const prefix string = ",\"file_name\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Token))
+		out.String(string(in.FileName))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Refresh) MarshalJSON() ([]byte, error) {
+func (v UserInfo) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto4(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Refresh) MarshalEasyJSON(w *jwriter.Writer) {
+func (v UserInfo) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto4(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Refresh) UnmarshalJSON(data []byte) error {
+func (v *UserInfo) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto4(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Refresh) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *UserInfo) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto4(l, v)
 }
-func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto5(in *jlexer.Lexer, out *Logout) {
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto5(in *jlexer.Lexer, out *UserID) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -464,8 +430,8 @@ func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto5(in *jl
 			continue
 		}
 		switch key {
-		case "token":
-			out.Token = string(in.String())
+		case "user_id":
+			out.UserID = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -476,42 +442,42 @@ func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto5(in *jl
 		in.Consumed()
 	}
 }
-func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto5(out *jwriter.Writer, in Logout) {
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto5(out *jwriter.Writer, in UserID) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"token\":"
+		const prefix string = ",\"user_id\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Token))
+		out.String(string(in.UserID))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Logout) MarshalJSON() ([]byte, error) {
+func (v UserID) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto5(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Logout) MarshalEasyJSON(w *jwriter.Writer) {
+func (v UserID) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto5(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Logout) UnmarshalJSON(data []byte) error {
+func (v *UserID) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto5(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Logout) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *UserID) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto5(l, v)
 }
-func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto6(in *jlexer.Lexer, out *Login) {
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto6(in *jlexer.Lexer, out *UnlockLogin) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -530,10 +496,10 @@ func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto6(in *jl
 			continue
 		}
 		switch key {
-		case "email":
-			out.Email = string(in.String())
-		case "password":
-			out.Password = string(in.String())
+		case "user_id":
+			out.UserID = string(in.String())
+		case "ip":
+			out.IP = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -544,43 +510,1909 @@ func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto6(in *jl
 		in.Consumed()
 	}
 }
-func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto6(out *jwriter.Writer, in Login) {
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto6(out *jwriter.Writer, in UnlockLogin) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"email\":"
+		const prefix string = ",\"user_id\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Email))
+		out.String(string(in.UserID))
 	}
 	{
-		const prefix string = ",\"password\":"
+		const prefix string = ",\"ip\":"
 		out.RawString(prefix)
-		out.String(string(in.Password))
+		out.String(string(in.IP))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Login) MarshalJSON() ([]byte, error) {
+func (v UnlockLogin) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto6(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Login) MarshalEasyJSON(w *jwriter.Writer) {
+func (v UnlockLogin) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto6(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Login) UnmarshalJSON(data []byte) error {
+func (v *UnlockLogin) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto6(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Login) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *UnlockLogin) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto6(l, v)
 }
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto7(in *jlexer.Lexer, out *SessionsResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "status":
+			out.Status = string(in.String())
+		case "sessions":
+			if in.IsNull() {
+				in.Skip()
+				out.Sessions = nil
+			} else {
+				in.Delim('[')
+				if out.Sessions == nil {
+					if !in.IsDelim(']') {
+						out.Sessions = make([]Session, 0, 0)
+					} else {
+						out.Sessions = []Session{}
+					}
+				} else {
+					out.Sessions = (out.Sessions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 Session
+					(v1).UnmarshalEasyJSON(in)
+					out.Sessions = append(out.Sessions, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto7(out *jwriter.Writer, in SessionsResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"sessions\":"
+		out.RawString(prefix)
+		if in.Sessions == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.Sessions {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				(v3).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SessionsResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto7(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SessionsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto7(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SessionsResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto7(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SessionsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto7(l, v)
+}
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto8(in *jlexer.Lexer, out *Session) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "user_agent":
+			out.UserAgent = string(in.String())
+		case "ip":
+			out.IP = string(in.String())
+		case "created_at":
+			out.CreatedAt = string(in.String())
+		case "refreshed_at":
+			out.RefreshedAt = string(in.String())
+		case "current":
+			out.Current = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto8(out *jwriter.Writer, in Session) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"user_agent\":"
+		out.RawString(prefix)
+		out.String(string(in.UserAgent))
+	}
+	{
+		const prefix string = ",\"ip\":"
+		out.RawString(prefix)
+		out.String(string(in.IP))
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.String(string(in.CreatedAt))
+	}
+	{
+		const prefix string = ",\"refreshed_at\":"
+		out.RawString(prefix)
+		out.String(string(in.RefreshedAt))
+	}
+	{
+		const prefix string = ",\"current\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Current))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Session) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto8(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Session) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto8(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Session) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto8(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Session) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto8(l, v)
+}
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto9(in *jlexer.Lexer, out *RotateSigningKey) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "algorithm":
+			out.Algorithm = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto9(out *jwriter.Writer, in RotateSigningKey) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"algorithm\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Algorithm))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RotateSigningKey) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto9(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RotateSigningKey) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto9(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RotateSigningKey) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto9(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RotateSigningKey) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto9(l, v)
+}
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto10(in *jlexer.Lexer, out *RevokeSessionsResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "status":
+			out.Status = string(in.String())
+		case "revoked":
+			out.Revoked = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto10(out *jwriter.Writer, in RevokeSessionsResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"revoked\":"
+		out.RawString(prefix)
+		out.Int(int(in.Revoked))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RevokeSessionsResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto10(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RevokeSessionsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto10(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RevokeSessionsResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto10(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RevokeSessionsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto10(l, v)
+}
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto11(in *jlexer.Lexer, out *RevokeSession) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "session_id":
+			out.SessionID = string(in.String())
+		case "user_id":
+			out.UserID = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto11(out *jwriter.Writer, in RevokeSession) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"session_id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.SessionID))
+	}
+	{
+		const prefix string = ",\"user_id\":"
+		out.RawString(prefix)
+		out.String(string(in.UserID))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RevokeSession) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto11(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RevokeSession) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto11(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RevokeSession) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto11(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RevokeSession) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto11(l, v)
+}
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto12(in *jlexer.Lexer, out *RevokeAllSessions) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "user_id":
+			out.UserID = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto12(out *jwriter.Writer, in RevokeAllSessions) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"user_id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.UserID))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RevokeAllSessions) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto12(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RevokeAllSessions) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto12(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RevokeAllSessions) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto12(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RevokeAllSessions) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto12(l, v)
+}
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto13(in *jlexer.Lexer, out *Response) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "status":
+			out.Status = string(in.String())
+		case "error":
+			out.Error = string(in.String())
+		case "user_id":
+			out.UserID = string(in.String())
+		case "access_token":
+			out.AccessToken = string(in.String())
+		case "refresh_token":
+			out.RefreshToken = string(in.String())
+		case "mfa_token":
+			out.MFAToken = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto13(out *jwriter.Writer, in Response) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Status))
+	}
+	if in.Error != "" {
+		const prefix string = ",\"error\":"
+		out.RawString(prefix)
+		out.String(string(in.Error))
+	}
+	if in.UserID != "" {
+		const prefix string = ",\"user_id\":"
+		out.RawString(prefix)
+		out.String(string(in.UserID))
+	}
+	if in.AccessToken != "" {
+		const prefix string = ",\"access_token\":"
+		out.RawString(prefix)
+		out.String(string(in.AccessToken))
+	}
+	if in.RefreshToken != "" {
+		const prefix string = ",\"refresh_token\":"
+		out.RawString(prefix)
+		out.String(string(in.RefreshToken))
+	}
+	if in.MFAToken != "" {
+		const prefix string = ",\"mfa_token\":"
+		out.RawString(prefix)
+		out.String(string(in.MFAToken))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Response) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto13(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Response) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto13(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Response) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto13(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Response) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto13(l, v)
+}
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto14(in *jlexer.Lexer, out *ResendEmailVerification) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "email":
+			out.Email = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto14(out *jwriter.Writer, in ResendEmailVerification) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"email\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Email))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ResendEmailVerification) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto14(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ResendEmailVerification) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto14(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ResendEmailVerification) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto14(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ResendEmailVerification) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto14(l, v)
+}
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto15(in *jlexer.Lexer, out *RequestPasswordReset) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "email":
+			out.Email = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto15(out *jwriter.Writer, in RequestPasswordReset) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"email\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Email))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RequestPasswordReset) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto15(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RequestPasswordReset) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto15(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RequestPasswordReset) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto15(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RequestPasswordReset) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto15(l, v)
+}
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto16(in *jlexer.Lexer, out *Register) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "email":
+			out.Email = string(in.String())
+		case "password":
+			out.Password = string(in.String())
+		case "name":
+			out.Name = string(in.String())
+		case "birthday":
+			out.Birthday = string(in.String())
+		case "avatar":
+			out.Avatar = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto16(out *jwriter.Writer, in Register) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"email\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Email))
+	}
+	{
+		const prefix string = ",\"password\":"
+		out.RawString(prefix)
+		out.String(string(in.Password))
+	}
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix)
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"birthday\":"
+		out.RawString(prefix)
+		out.String(string(in.Birthday))
+	}
+	{
+		const prefix string = ",\"avatar\":"
+		out.RawString(prefix)
+		out.String(string(in.Avatar))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Register) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto16(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Register) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto16(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Register) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto16(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Register) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto16(l, v)
+}
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto17(in *jlexer.Lexer, out *Refresh) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "token":
+			out.Token = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto17(out *jwriter.Writer, in Refresh) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"token\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Token))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Refresh) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto17(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Refresh) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto17(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Refresh) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto17(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Refresh) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto17(l, v)
+}
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto18(in *jlexer.Lexer, out *RecoveryCodesResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "status":
+			out.Status = string(in.String())
+		case "recovery_codes":
+			if in.IsNull() {
+				in.Skip()
+				out.RecoveryCodes = nil
+			} else {
+				in.Delim('[')
+				if out.RecoveryCodes == nil {
+					if !in.IsDelim(']') {
+						out.RecoveryCodes = make([]string, 0, 4)
+					} else {
+						out.RecoveryCodes = []string{}
+					}
+				} else {
+					out.RecoveryCodes = (out.RecoveryCodes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 string
+					v4 = string(in.String())
+					out.RecoveryCodes = append(out.RecoveryCodes, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto18(out *jwriter.Writer, in RecoveryCodesResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"recovery_codes\":"
+		out.RawString(prefix)
+		if in.RecoveryCodes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v5, v6 := range in.RecoveryCodes {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v6))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RecoveryCodesResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto18(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RecoveryCodesResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto18(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RecoveryCodesResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto18(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RecoveryCodesResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto18(l, v)
+}
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto19(in *jlexer.Lexer, out *MFAEnrollmentResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "status":
+			out.Status = string(in.String())
+		case "secret":
+			out.Secret = string(in.String())
+		case "otpauth_uri":
+			out.URI = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto19(out *jwriter.Writer, in MFAEnrollmentResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"secret\":"
+		out.RawString(prefix)
+		out.String(string(in.Secret))
+	}
+	{
+		const prefix string = ",\"otpauth_uri\":"
+		out.RawString(prefix)
+		out.String(string(in.URI))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MFAEnrollmentResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto19(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MFAEnrollmentResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto19(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MFAEnrollmentResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto19(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MFAEnrollmentResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto19(l, v)
+}
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto20(in *jlexer.Lexer, out *Logout) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "token":
+			out.Token = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto20(out *jwriter.Writer, in Logout) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"token\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Token))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Logout) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto20(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Logout) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto20(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Logout) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto20(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Logout) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto20(l, v)
+}
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto21(in *jlexer.Lexer, out *Login) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "email":
+			out.Email = string(in.String())
+		case "password":
+			out.Password = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto21(out *jwriter.Writer, in Login) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"email\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Email))
+	}
+	{
+		const prefix string = ",\"password\":"
+		out.RawString(prefix)
+		out.String(string(in.Password))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Login) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto21(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Login) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto21(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Login) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto21(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Login) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto21(l, v)
+}
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto22(in *jlexer.Lexer, out *KeyRotationResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "status":
+			out.Status = string(in.String())
+		case "active_key_id":
+			out.ActiveKeyID = string(in.String())
+		case "retiring_key_id":
+			out.RetiringKeyID = string(in.String())
+		case "retire_at":
+			out.RetireAt = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto22(out *jwriter.Writer, in KeyRotationResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"active_key_id\":"
+		out.RawString(prefix)
+		out.String(string(in.ActiveKeyID))
+	}
+	{
+		const prefix string = ",\"retiring_key_id\":"
+		out.RawString(prefix)
+		out.String(string(in.RetiringKeyID))
+	}
+	{
+		const prefix string = ",\"retire_at\":"
+		out.RawString(prefix)
+		out.String(string(in.RetireAt))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v KeyRotationResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto22(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v KeyRotationResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto22(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *KeyRotationResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto22(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *KeyRotationResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto22(l, v)
+}
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto23(in *jlexer.Lexer, out *JWKS) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "keys":
+			if in.IsNull() {
+				in.Skip()
+				out.Keys = nil
+			} else {
+				in.Delim('[')
+				if out.Keys == nil {
+					if !in.IsDelim(']') {
+						out.Keys = make([]JWK, 0, 0)
+					} else {
+						out.Keys = []JWK{}
+					}
+				} else {
+					out.Keys = (out.Keys)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v7 JWK
+					(v7).UnmarshalEasyJSON(in)
+					out.Keys = append(out.Keys, v7)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto23(out *jwriter.Writer, in JWKS) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"keys\":"
+		out.RawString(prefix[1:])
+		if in.Keys == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v8, v9 := range in.Keys {
+				if v8 > 0 {
+					out.RawByte(',')
+				}
+				(v9).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v JWKS) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto23(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v JWKS) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto23(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *JWKS) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto23(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *JWKS) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto23(l, v)
+}
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto24(in *jlexer.Lexer, out *JWK) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "kty":
+			out.Kty = string(in.String())
+		case "use":
+			out.Use = string(in.String())
+		case "alg":
+			out.Alg = string(in.String())
+		case "kid":
+			out.Kid = string(in.String())
+		case "n":
+			out.N = string(in.String())
+		case "e":
+			out.E = string(in.String())
+		case "crv":
+			out.Crv = string(in.String())
+		case "x":
+			out.X = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto24(out *jwriter.Writer, in JWK) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"kty\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Kty))
+	}
+	{
+		const prefix string = ",\"use\":"
+		out.RawString(prefix)
+		out.String(string(in.Use))
+	}
+	{
+		const prefix string = ",\"alg\":"
+		out.RawString(prefix)
+		out.String(string(in.Alg))
+	}
+	if in.Kid != "" {
+		const prefix string = ",\"kid\":"
+		out.RawString(prefix)
+		out.String(string(in.Kid))
+	}
+	if in.N != "" {
+		const prefix string = ",\"n\":"
+		out.RawString(prefix)
+		out.String(string(in.N))
+	}
+	if in.E != "" {
+		const prefix string = ",\"e\":"
+		out.RawString(prefix)
+		out.String(string(in.E))
+	}
+	if in.Crv != "" {
+		const prefix string = ",\"crv\":"
+		out.RawString(prefix)
+		out.String(string(in.Crv))
+	}
+	if in.X != "" {
+		const prefix string = ",\"x\":"
+		out.RawString(prefix)
+		out.String(string(in.X))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v JWK) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto24(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v JWK) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto24(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *JWK) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto24(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *JWK) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto24(l, v)
+}
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto25(in *jlexer.Lexer, out *ConfirmPasswordReset) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "token":
+			out.Token = string(in.String())
+		case "password":
+			out.Password = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto25(out *jwriter.Writer, in ConfirmPasswordReset) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"token\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Token))
+	}
+	{
+		const prefix string = ",\"password\":"
+		out.RawString(prefix)
+		out.String(string(in.Password))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ConfirmPasswordReset) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto25(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ConfirmPasswordReset) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto25(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ConfirmPasswordReset) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto25(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ConfirmPasswordReset) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto25(l, v)
+}
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto26(in *jlexer.Lexer, out *ConfirmMFA) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "code":
+			out.Code = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto26(out *jwriter.Writer, in ConfirmMFA) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"code\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Code))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ConfirmMFA) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto26(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ConfirmMFA) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto26(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ConfirmMFA) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto26(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ConfirmMFA) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto26(l, v)
+}
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto27(in *jlexer.Lexer, out *Client) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto27(out *jwriter.Writer, in Client) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Client) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto27(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Client) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto27(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Client) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto27(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Client) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto27(l, v)
+}
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto28(in *jlexer.Lexer, out *ChangeEmail) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "email":
+			out.Email = string(in.String())
+		case "password":
+			out.Password = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto28(out *jwriter.Writer, in ChangeEmail) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"email\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Email))
+	}
+	{
+		const prefix string = ",\"password\":"
+		out.RawString(prefix)
+		out.String(string(in.Password))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ChangeEmail) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto28(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ChangeEmail) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto28(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ChangeEmail) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto28(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ChangeEmail) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto28(l, v)
+}
+func easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto29(in *jlexer.Lexer, out *BlockUser) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "user_id":
+			out.UserID = string(in.String())
+		case "reason":
+			out.Reason = string(in.String())
+		case "until":
+			if in.IsNull() {
+				in.Skip()
+				out.Until = nil
+			} else {
+				if out.Until == nil {
+					out.Until = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.Until).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto29(out *jwriter.Writer, in BlockUser) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"user_id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.UserID))
+	}
+	{
+		const prefix string = ",\"reason\":"
+		out.RawString(prefix)
+		out.String(string(in.Reason))
+	}
+	{
+		const prefix string = ",\"until\":"
+		out.RawString(prefix)
+		if in.Until == nil {
+			out.RawString("null")
+		} else {
+			out.Raw((*in.Until).MarshalJSON())
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v BlockUser) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto29(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v BlockUser) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComAlexBlackNnAuthloyaltySsoInternalDto29(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *BlockUser) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto29(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *BlockUser) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComAlexBlackNnAuthloyaltySsoInternalDto29(l, v)
+}
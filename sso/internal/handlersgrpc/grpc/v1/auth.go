@@ -4,18 +4,27 @@ import (
 	"context"
 	"errors"
 	log "log/slog"
+	"net"
+	"net/mail"
+	"strings"
+	"time"
 
 	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/dto"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/lib/clientip"
+	jwtlib "github.com/AlexBlackNn/authloyalty/sso/internal/lib/jwt"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/storage"
 
 	ssov1 "github.com/AlexBlackNn/authloyalty/commands/proto/sso/gen"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/services/authservice"
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
@@ -44,6 +53,90 @@ type authService interface {
 		ctx context.Context,
 		reqData *dto.Refresh,
 	) (userWithTokens *domain.UserWithTokens, err error)
+	RotateSigningKey(
+		ctx context.Context,
+		token string,
+		reqData *dto.RotateSigningKey,
+	) (keyRotation *domain.KeyRotation, err error)
+	GrantRole(
+		ctx context.Context,
+		token string,
+		reqData *dto.UserRole,
+	) (err error)
+	RevokeRole(
+		ctx context.Context,
+		token string,
+		reqData *dto.UserRole,
+	) (err error)
+	ListSessions(
+		ctx context.Context,
+		token string,
+		userID string,
+	) (sessions []domain.Session, err error)
+	RevokeSession(
+		ctx context.Context,
+		token string,
+		reqData *dto.RevokeSession,
+	) (err error)
+	RevokeAllSessions(
+		ctx context.Context,
+		token string,
+		reqData *dto.RevokeAllSessions,
+	) (revoked int, err error)
+	RequestPasswordReset(
+		ctx context.Context,
+		reqData *dto.RequestPasswordReset,
+	) (ctxOut context.Context, err error)
+	ConfirmPasswordReset(
+		ctx context.Context,
+		reqData *dto.ConfirmPasswordReset,
+	) (ctxOut context.Context, err error)
+	VerifyEmail(
+		ctx context.Context,
+		reqData *dto.VerifyEmail,
+	) (ctxOut context.Context, err error)
+	ResendEmailVerification(
+		ctx context.Context,
+		reqData *dto.ResendEmailVerification,
+	) (ctxOut context.Context, err error)
+	EnrollMFA(
+		ctx context.Context,
+		token string,
+	) (enrollment *domain.MFAEnrollment, err error)
+	ConfirmMFA(
+		ctx context.Context,
+		token string,
+		reqData *dto.ConfirmMFA,
+	) (recoveryCodes []string, err error)
+	VerifyMFA(
+		ctx context.Context,
+		reqData *dto.VerifyMFA,
+	) (userWithTokens *domain.UserWithTokens, err error)
+	UnlockLogin(
+		ctx context.Context,
+		token string,
+		reqData *dto.UnlockLogin,
+	) (err error)
+	BlockUser(
+		ctx context.Context,
+		token string,
+		reqData *dto.BlockUser,
+	) (err error)
+	UnblockUser(
+		ctx context.Context,
+		token string,
+		reqData *dto.UserID,
+	) (err error)
+	DeleteUser(
+		ctx context.Context,
+		token string,
+		reqData *dto.UserID,
+	) (err error)
+	ChangeEmail(
+		ctx context.Context,
+		token string,
+		reqData *dto.ChangeEmail,
+	) (err error)
 }
 
 // serverAPI TRANSPORT layer
@@ -53,10 +146,25 @@ type serverAPI struct {
 	// service layer
 	auth   authService
 	tracer trace.Tracer
+	// proxies are allowed to forward client address in x-forwarded-for
+	proxies clientip.TrustedProxies
 }
 
-func Register(gRPC *grpc.Server, auth authService) {
-	ssov1.RegisterAuthServer(gRPC, &serverAPI{auth: auth, tracer: otel.Tracer("sso service")})
+func Register(gRPC *grpc.Server, auth authService, proxies clientip.TrustedProxies) {
+	ssov1.RegisterAuthServer(gRPC, &serverAPI{auth: auth, tracer: otel.Tracer("sso service"), proxies: proxies})
+}
+
+// RegisterGateway registers REST facade of the grpc service. Requests are
+// served by the same serverAPI in process, without a network hop.
+func RegisterGateway(
+	ctx context.Context,
+	mux *runtime.ServeMux,
+	auth authService,
+	proxies clientip.TrustedProxies,
+) error {
+	return ssov1.RegisterAuthHandlerServer(
+		ctx, mux, &serverAPI{auth: auth, tracer: otel.Tracer("sso service"), proxies: proxies},
+	)
 }
 
 const (
@@ -80,17 +188,31 @@ func (s *serverAPI) Login(
 	}
 
 	userWithTokens, err := s.auth.Login(
-		ctx, &dto.Login{Email: req.GetEmail(), Password: req.GetPassword()},
+		ctx, &dto.Login{
+			Email:    req.GetEmail(),
+			Password: req.GetPassword(),
+			Client:   s.clientFromContext(ctx),
+		},
 	)
 	if err != nil {
+		if errors.Is(err, authservice.ErrLoginLocked) {
+			return nil, status.Error(codes.ResourceExhausted, "too many failed login attempts")
+		}
 		if errors.Is(err, authservice.ErrInvalidCredentials) {
 			return nil, status.Error(codes.InvalidArgument, "invalid credentials")
 		}
+		if errors.Is(err, authservice.ErrEmailNotVerified) {
+			return nil, status.Error(codes.FailedPrecondition, "email not verified")
+		}
+		if errors.Is(err, authservice.ErrUserBlocked) {
+			return nil, status.Error(codes.PermissionDenied, "user is blocked")
+		}
 		return nil, status.Error(codes.Internal, "internal error")
 	}
 	return &ssov1.LoginResponse{
 		AccessToken:  userWithTokens.AccessToken,
 		RefreshToken: userWithTokens.RefreshToken,
+		MfaToken:     userWithTokens.MFAToken,
 	}, nil
 }
 
@@ -105,15 +227,19 @@ func (s *serverAPI) Refresh(
 	}
 
 	userWithTokens, err := s.auth.Refresh(
-		ctx, &dto.Refresh{Token: req.GetRefreshToken()},
+		ctx, &dto.Refresh{Token: req.GetRefreshToken(), Client: s.clientFromContext(ctx)},
 	)
 	if err != nil {
 		if errors.Is(err, authservice.ErrTokenWrongType) {
 			return nil, status.Error(codes.InvalidArgument, "Provide valid refresh token")
 		}
-		if errors.Is(err, authservice.ErrTokenRevoked) {
+		if errors.Is(err, authservice.ErrTokenRevoked) ||
+			errors.Is(err, authservice.ErrTokenReused) {
 			return nil, status.Error(codes.Unauthenticated, "Provide valid refresh token")
 		}
+		if errors.Is(err, authservice.ErrUserBlocked) {
+			return nil, status.Error(codes.PermissionDenied, "user is blocked")
+		}
 		return nil, status.Error(codes.Internal, "internal error")
 	}
 
@@ -136,7 +262,11 @@ func (s *serverAPI) Register(
 		return nil, err
 	}
 	ctx, userWithTokens, err := s.auth.Register(
-		ctx, &dto.Register{Email: req.GetEmail(), Password: req.GetPassword()},
+		ctx, &dto.Register{
+			Email:    req.GetEmail(),
+			Password: req.GetPassword(),
+			Client:   s.clientFromContext(ctx),
+		},
 	)
 	if err != nil {
 		if errors.Is(err, storage.ErrUserExists) {
@@ -147,7 +277,9 @@ func (s *serverAPI) Register(
 		return nil, status.Error(codes.Internal, "internal error")
 	}
 	return &ssov1.RegisterResponse{
-		UserId: userWithTokens.ID,
+		UserId:       userWithTokens.ID,
+		AccessToken:  userWithTokens.AccessToken,
+		RefreshToken: userWithTokens.RefreshToken,
 	}, nil
 }
 
@@ -209,6 +341,476 @@ func (s *serverAPI) Validate(
 	return &ssov1.ValidateResponse{Success: success}, nil
 }
 
+func (s *serverAPI) RotateSigningKey(
+	ctx context.Context,
+	req *ssov1.RotateSigningKeyRequest,
+) (*ssov1.RotateSigningKeyResponse, error) {
+	ctx, err := getContextWithTraceId(ctx)
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	if err = validateRotateSigningKey(req); err != nil {
+		return nil, err
+	}
+	keyRotation, err := s.auth.RotateSigningKey(
+		ctx, req.GetToken(), &dto.RotateSigningKey{Algorithm: req.GetAlgorithm()},
+	)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+	return &ssov1.RotateSigningKeyResponse{
+		ActiveKeyId:   keyRotation.ActiveKeyID,
+		RetiringKeyId: keyRotation.RetiringKeyID,
+		RetireAt:      keyRotation.RetireAt.Unix(),
+	}, nil
+}
+
+func (s *serverAPI) GrantRole(
+	ctx context.Context,
+	req *ssov1.GrantRoleRequest,
+) (*ssov1.GrantRoleResponse, error) {
+	ctx, err := getContextWithTraceId(ctx)
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	if err = validateUserRole(req.GetToken(), req.GetUserId(), req.GetRole()); err != nil {
+		return nil, err
+	}
+	err = s.auth.GrantRole(
+		ctx, req.GetToken(), &dto.UserRole{UserID: req.GetUserId(), Role: req.GetRole()},
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, authservice.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		case errors.Is(err, authservice.ErrRoleNotFound):
+			return nil, status.Error(codes.NotFound, "role not found")
+		}
+		return nil, authorizationError(err)
+	}
+	return &ssov1.GrantRoleResponse{Success: true}, nil
+}
+
+func (s *serverAPI) RevokeRole(
+	ctx context.Context,
+	req *ssov1.RevokeRoleRequest,
+) (*ssov1.RevokeRoleResponse, error) {
+	ctx, err := getContextWithTraceId(ctx)
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	if err = validateUserRole(req.GetToken(), req.GetUserId(), req.GetRole()); err != nil {
+		return nil, err
+	}
+	err = s.auth.RevokeRole(
+		ctx, req.GetToken(), &dto.UserRole{UserID: req.GetUserId(), Role: req.GetRole()},
+	)
+	if err != nil {
+		if errors.Is(err, authservice.ErrRoleNotGranted) {
+			return nil, status.Error(codes.NotFound, "role not granted")
+		}
+		return nil, authorizationError(err)
+	}
+	return &ssov1.RevokeRoleResponse{Success: true}, nil
+}
+
+func (s *serverAPI) ListSessions(
+	ctx context.Context,
+	req *ssov1.ListSessionsRequest,
+) (*ssov1.ListSessionsResponse, error) {
+	ctx, err := getContextWithTraceId(ctx)
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+	sessions, err := s.auth.ListSessions(ctx, req.GetToken(), req.GetUserId())
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+	response := &ssov1.ListSessionsResponse{
+		Sessions: make([]*ssov1.Session, 0, len(sessions)),
+	}
+	for _, session := range sessions {
+		response.Sessions = append(response.Sessions, &ssov1.Session{
+			Id:          session.ID,
+			UserAgent:   session.UserAgent,
+			Ip:          session.IP,
+			CreatedAt:   session.CreatedAt.Unix(),
+			RefreshedAt: session.RefreshedAt.Unix(),
+			Current:     session.Current,
+		})
+	}
+	return response, nil
+}
+
+func (s *serverAPI) RevokeSession(
+	ctx context.Context,
+	req *ssov1.RevokeSessionRequest,
+) (*ssov1.RevokeSessionResponse, error) {
+	ctx, err := getContextWithTraceId(ctx)
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	if err = validateRevokeSession(req); err != nil {
+		return nil, err
+	}
+	err = s.auth.RevokeSession(ctx, req.GetToken(), &dto.RevokeSession{
+		SessionID: req.GetSessionId(),
+		UserID:    req.GetUserId(),
+	})
+	if err != nil {
+		if errors.Is(err, authservice.ErrSessionNotFound) {
+			return nil, status.Error(codes.NotFound, "session not found")
+		}
+		return nil, authorizationError(err)
+	}
+	return &ssov1.RevokeSessionResponse{Success: true}, nil
+}
+
+func (s *serverAPI) RevokeAllSessions(
+	ctx context.Context,
+	req *ssov1.RevokeAllSessionsRequest,
+) (*ssov1.RevokeAllSessionsResponse, error) {
+	ctx, err := getContextWithTraceId(ctx)
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+	revoked, err := s.auth.RevokeAllSessions(
+		ctx, req.GetToken(), &dto.RevokeAllSessions{UserID: req.GetUserId()},
+	)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+	return &ssov1.RevokeAllSessionsResponse{Revoked: int64(revoked)}, nil
+}
+
+func (s *serverAPI) RequestPasswordReset(
+	ctx context.Context,
+	req *ssov1.RequestPasswordResetRequest,
+) (*ssov1.RequestPasswordResetResponse, error) {
+	ctx, err := getContextWithTraceId(ctx)
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	if req.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+	_, err = s.auth.RequestPasswordReset(
+		ctx, &dto.RequestPasswordReset{Email: req.GetEmail()},
+	)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	return &ssov1.RequestPasswordResetResponse{Success: true}, nil
+}
+
+func (s *serverAPI) ConfirmPasswordReset(
+	ctx context.Context,
+	req *ssov1.ConfirmPasswordResetRequest,
+) (*ssov1.ConfirmPasswordResetResponse, error) {
+	ctx, err := getContextWithTraceId(ctx)
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	if err = validateConfirmPasswordReset(req); err != nil {
+		return nil, err
+	}
+	_, err = s.auth.ConfirmPasswordReset(
+		ctx, &dto.ConfirmPasswordReset{
+			Token:    req.GetToken(),
+			Password: req.GetPassword(),
+		},
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, authservice.ErrResetTokenInvalid):
+			return nil, status.Error(
+				codes.InvalidArgument, "password reset token is invalid or expired",
+			)
+		case errors.Is(err, authservice.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	return &ssov1.ConfirmPasswordResetResponse{Success: true}, nil
+}
+
+func (s *serverAPI) VerifyEmail(
+	ctx context.Context,
+	req *ssov1.VerifyEmailRequest,
+) (*ssov1.VerifyEmailResponse, error) {
+	ctx, err := getContextWithTraceId(ctx)
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+	_, err = s.auth.VerifyEmail(ctx, &dto.VerifyEmail{Token: req.GetToken()})
+	if err != nil {
+		switch {
+		case errors.Is(err, authservice.ErrVerificationTokenInvalid):
+			return nil, status.Error(
+				codes.InvalidArgument, "email verification token is invalid or expired",
+			)
+		case errors.Is(err, authservice.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	return &ssov1.VerifyEmailResponse{Success: true}, nil
+}
+
+func (s *serverAPI) ResendEmailVerification(
+	ctx context.Context,
+	req *ssov1.ResendEmailVerificationRequest,
+) (*ssov1.ResendEmailVerificationResponse, error) {
+	ctx, err := getContextWithTraceId(ctx)
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	if req.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+	_, err = s.auth.ResendEmailVerification(
+		ctx, &dto.ResendEmailVerification{Email: req.GetEmail()},
+	)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	return &ssov1.ResendEmailVerificationResponse{Success: true}, nil
+}
+
+func (s *serverAPI) EnrollMFA(
+	ctx context.Context,
+	req *ssov1.EnrollMFARequest,
+) (*ssov1.EnrollMFAResponse, error) {
+	ctx, err := getContextWithTraceId(ctx)
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+	enrollment, err := s.auth.EnrollMFA(ctx, req.GetToken())
+	if err != nil {
+		if errors.Is(err, authservice.ErrMFAEnabled) {
+			return nil, status.Error(codes.AlreadyExists, "mfa already enabled")
+		}
+		return nil, authorizationError(err)
+	}
+	return &ssov1.EnrollMFAResponse{
+		Secret:     enrollment.Secret,
+		OtpauthUri: enrollment.URI,
+	}, nil
+}
+
+func (s *serverAPI) ConfirmMFA(
+	ctx context.Context,
+	req *ssov1.ConfirmMFARequest,
+) (*ssov1.ConfirmMFAResponse, error) {
+	ctx, err := getContextWithTraceId(ctx)
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+	if req.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+	recoveryCodes, err := s.auth.ConfirmMFA(
+		ctx, req.GetToken(), &dto.ConfirmMFA{Code: req.GetCode()},
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, authservice.ErrMFAEnabled):
+			return nil, status.Error(codes.AlreadyExists, "mfa already enabled")
+		case errors.Is(err, authservice.ErrMFANotEnrolled):
+			return nil, status.Error(codes.FailedPrecondition, "mfa not enrolled")
+		case errors.Is(err, authservice.ErrMFAInvalid):
+			return nil, status.Error(codes.InvalidArgument, "invalid code")
+		}
+		return nil, authorizationError(err)
+	}
+	return &ssov1.ConfirmMFAResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+func (s *serverAPI) VerifyMFA(
+	ctx context.Context,
+	req *ssov1.VerifyMFARequest,
+) (*ssov1.VerifyMFAResponse, error) {
+	ctx, err := getContextWithTraceId(ctx)
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	if err = validateVerifyMFA(req); err != nil {
+		return nil, err
+	}
+	userWithTokens, err := s.auth.VerifyMFA(
+		ctx, &dto.VerifyMFA{
+			MFAToken:     req.GetMfaToken(),
+			Code:         req.GetCode(),
+			RecoveryCode: req.GetRecoveryCode(),
+			Client:       s.clientFromContext(ctx),
+		},
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, authservice.ErrMFAInvalid):
+			return nil, status.Error(
+				codes.InvalidArgument, "mfa challenge or code is invalid, login again",
+			)
+		case errors.Is(err, authservice.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		case errors.Is(err, authservice.ErrUserBlocked):
+			return nil, status.Error(codes.PermissionDenied, "user is blocked")
+		}
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	return &ssov1.VerifyMFAResponse{
+		AccessToken:  userWithTokens.AccessToken,
+		RefreshToken: userWithTokens.RefreshToken,
+	}, nil
+}
+
+func (s *serverAPI) UnlockLogin(
+	ctx context.Context,
+	req *ssov1.UnlockLoginRequest,
+) (*ssov1.UnlockLoginResponse, error) {
+	ctx, err := getContextWithTraceId(ctx)
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	if err = validateUnlockLogin(req); err != nil {
+		return nil, err
+	}
+	err = s.auth.UnlockLogin(
+		ctx, req.GetToken(), &dto.UnlockLogin{UserID: req.GetUserId(), IP: req.GetIp()},
+	)
+	if err != nil {
+		if errors.Is(err, authservice.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+		return nil, authorizationError(err)
+	}
+	return &ssov1.UnlockLoginResponse{Success: true}, nil
+}
+
+func (s *serverAPI) BlockUser(
+	ctx context.Context,
+	req *ssov1.BlockUserRequest,
+) (*ssov1.BlockUserResponse, error) {
+	ctx, err := getContextWithTraceId(ctx)
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	if err = validateManageUser(req.GetToken(), req.GetUserId()); err != nil {
+		return nil, err
+	}
+	reqData := &dto.BlockUser{UserID: req.GetUserId(), Reason: req.GetReason()}
+	if req.GetUntil() != 0 {
+		until := time.Unix(req.GetUntil(), 0)
+		reqData.Until = &until
+	}
+	if err = s.auth.BlockUser(ctx, req.GetToken(), reqData); err != nil {
+		return nil, manageUserError(err)
+	}
+	return &ssov1.BlockUserResponse{Success: true}, nil
+}
+
+func (s *serverAPI) UnblockUser(
+	ctx context.Context,
+	req *ssov1.UnblockUserRequest,
+) (*ssov1.UnblockUserResponse, error) {
+	ctx, err := getContextWithTraceId(ctx)
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	if err = validateManageUser(req.GetToken(), req.GetUserId()); err != nil {
+		return nil, err
+	}
+	err = s.auth.UnblockUser(ctx, req.GetToken(), &dto.UserID{UserID: req.GetUserId()})
+	if err != nil {
+		return nil, manageUserError(err)
+	}
+	return &ssov1.UnblockUserResponse{Success: true}, nil
+}
+
+func (s *serverAPI) DeleteUser(
+	ctx context.Context,
+	req *ssov1.DeleteUserRequest,
+) (*ssov1.DeleteUserResponse, error) {
+	ctx, err := getContextWithTraceId(ctx)
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	if err = validateManageUser(req.GetToken(), req.GetUserId()); err != nil {
+		return nil, err
+	}
+	err = s.auth.DeleteUser(ctx, req.GetToken(), &dto.UserID{UserID: req.GetUserId()})
+	if err != nil {
+		return nil, manageUserError(err)
+	}
+	return &ssov1.DeleteUserResponse{Success: true}, nil
+}
+
+func (s *serverAPI) ChangeEmail(
+	ctx context.Context,
+	req *ssov1.ChangeEmailRequest,
+) (*ssov1.ChangeEmailResponse, error) {
+	ctx, err := getContextWithTraceId(ctx)
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	if err = validateChangeEmail(req); err != nil {
+		return nil, err
+	}
+	err = s.auth.ChangeEmail(
+		ctx, req.GetToken(), &dto.ChangeEmail{Email: req.GetEmail(), Password: req.GetPassword()},
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, authservice.ErrInvalidCredentials):
+			return nil, status.Error(codes.InvalidArgument, "invalid credentials")
+		case errors.Is(err, storage.ErrUserExists):
+			return nil, status.Error(codes.AlreadyExists, "user with this email already exists")
+		case errors.Is(err, authservice.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+		return nil, authorizationError(err)
+	}
+	return &ssov1.ChangeEmailResponse{Success: true}, nil
+}
+
+// manageUserError maps errors of user management to grpc status.
+func manageUserError(err error) error {
+	if errors.Is(err, authservice.ErrUserNotFound) {
+		return status.Error(codes.NotFound, "user not found")
+	}
+	return authorizationError(err)
+}
+
+// authorizationError maps token validation and permission errors to grpc status.
+func authorizationError(err error) error {
+	switch {
+	case errors.Is(err, authservice.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, "admin rights required")
+	case errors.Is(err, authservice.ErrMFARequired):
+		return status.Error(codes.PermissionDenied, "login with mfa required")
+	case errors.Is(err, authservice.ErrTokenRevoked),
+		errors.Is(err, authservice.ErrTokenParsing),
+		errors.Is(err, authservice.ErrTokenWrongType):
+		return status.Error(codes.Unauthenticated, "Provide valid access token")
+	}
+	return status.Error(codes.Internal, "internal error")
+}
+
 func validateLogin(req *ssov1.LoginRequest) error {
 	//TODO: use special packet for data validation
 	if req.GetEmail() == "" {
@@ -239,6 +841,132 @@ func validateIsAdmin(req *ssov1.IsAdminRequest) error {
 	return nil
 }
 
+func validateRotateSigningKey(req *ssov1.RotateSigningKeyRequest) error {
+	//TODO: use special packet for data validation
+	if req.GetToken() == "" {
+		return status.Error(codes.InvalidArgument, "token is required")
+	}
+	switch req.GetAlgorithm() {
+	case "", jwtlib.AlgorithmRS256, jwtlib.AlgorithmEdDSA:
+		return nil
+	default:
+		return status.Error(codes.InvalidArgument, "algorithm must be RS256 or EdDSA")
+	}
+}
+
+func validateUserRole(token, userID, role string) error {
+	//TODO: use special packet for data validation
+	if token == "" {
+		return status.Error(codes.InvalidArgument, "token is required")
+	}
+	if userID == emptyId {
+		return status.Error(codes.InvalidArgument, "userid is required")
+	}
+	if role == "" {
+		return status.Error(codes.InvalidArgument, "role is required")
+	}
+	return nil
+}
+
+func validateRevokeSession(req *ssov1.RevokeSessionRequest) error {
+	//TODO: use special packet for data validation
+	if req.GetToken() == "" {
+		return status.Error(codes.InvalidArgument, "token is required")
+	}
+	if req.GetSessionId() == "" {
+		return status.Error(codes.InvalidArgument, "session id is required")
+	}
+	return nil
+}
+
+func validateConfirmPasswordReset(req *ssov1.ConfirmPasswordResetRequest) error {
+	if req.GetToken() == "" {
+		return status.Error(codes.InvalidArgument, "token is required")
+	}
+	if req.GetPassword() == "" {
+		return status.Error(codes.InvalidArgument, "password is required")
+	}
+	return nil
+}
+
+func validateVerifyMFA(req *ssov1.VerifyMFARequest) error {
+	if req.GetMfaToken() == "" {
+		return status.Error(codes.InvalidArgument, "mfa_token is required")
+	}
+	if req.GetCode() == "" && req.GetRecoveryCode() == "" {
+		return status.Error(codes.InvalidArgument, "code or recovery_code is required")
+	}
+	return nil
+}
+
+func validateUnlockLogin(req *ssov1.UnlockLoginRequest) error {
+	if req.GetToken() == "" {
+		return status.Error(codes.InvalidArgument, "token is required")
+	}
+	if req.GetUserId() == "" && req.GetIp() == "" {
+		return status.Error(codes.InvalidArgument, "user_id or ip is required")
+	}
+	if req.GetUserId() != "" {
+		if _, err := uuid.Parse(req.GetUserId()); err != nil {
+			return status.Error(codes.InvalidArgument, "user_id must be uuid")
+		}
+	}
+	if req.GetIp() != "" && net.ParseIP(req.GetIp()) == nil {
+		return status.Error(codes.InvalidArgument, "ip is invalid")
+	}
+	return nil
+}
+
+func validateManageUser(token, userID string) error {
+	if token == "" {
+		return status.Error(codes.InvalidArgument, "token is required")
+	}
+	if _, err := uuid.Parse(userID); err != nil {
+		return status.Error(codes.InvalidArgument, "user_id must be uuid")
+	}
+	return nil
+}
+
+func validateChangeEmail(req *ssov1.ChangeEmailRequest) error {
+	if req.GetToken() == "" {
+		return status.Error(codes.InvalidArgument, "token is required")
+	}
+	if _, err := mail.ParseAddress(req.GetEmail()); err != nil {
+		return status.Error(codes.InvalidArgument, "email is invalid")
+	}
+	if req.GetPassword() == "" {
+		return status.Error(codes.InvalidArgument, "password is required")
+	}
+	return nil
+}
+
+// clientFromContext extracts device info saved with user session. Requests
+// proxied by grpc-gateway carry http user agent in grpcgateway-user-agent.
+// Client address is the peer address, x-forwarded-for is read only when the
+// peer is a trusted proxy.
+func (s *serverAPI) clientFromContext(ctx context.Context) dto.Client {
+	var client dto.Client
+	md, _ := metadata.FromIncomingContext(ctx)
+	if userAgent := md.Get("grpcgateway-user-agent"); len(userAgent) > 0 {
+		client.UserAgent = userAgent[0]
+	} else if userAgent = md.Get("user-agent"); len(userAgent) > 0 {
+		client.UserAgent = userAgent[0]
+	}
+	forwardedFor := strings.Join(md.Get("x-forwarded-for"), ",")
+	if p, ok := peer.FromContext(ctx); ok {
+		client.IP = s.proxies.ClientIP(p.Addr.String(), forwardedFor)
+		return client
+	}
+	// grpc-gateway serves requests in process without grpc peer, it appends
+	// http remote address to x-forwarded-for, so the last address is the peer
+	if i := strings.LastIndex(forwardedFor, ","); i >= 0 {
+		client.IP = s.proxies.ClientIP(strings.TrimSpace(forwardedFor[i+1:]), forwardedFor[:i])
+	} else {
+		client.IP = strings.TrimSpace(forwardedFor)
+	}
+	return client
+}
+
 func getContextWithTraceId(ctx context.Context) (context.Context, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
@@ -12,6 +12,7 @@ import (
 
 	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/dto"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/lib/clientip"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/storage"
 
 	"github.com/AlexBlackNn/authloyalty/sso/internal/config"
@@ -40,16 +41,110 @@ type authService interface {
 		ctx context.Context,
 		token string,
 	) (user *domain.User, err error)
+	JWKS(
+		ctx context.Context,
+	) (publicKeys []domain.PublicKey)
+	RotateSigningKey(
+		ctx context.Context,
+		token string,
+		reqData *dto.RotateSigningKey,
+	) (keyRotation *domain.KeyRotation, err error)
+	GrantRole(
+		ctx context.Context,
+		token string,
+		reqData *dto.UserRole,
+	) (err error)
+	RevokeRole(
+		ctx context.Context,
+		token string,
+		reqData *dto.UserRole,
+	) (err error)
+	ListSessions(
+		ctx context.Context,
+		token string,
+		userID string,
+	) (sessions []domain.Session, err error)
+	RevokeSession(
+		ctx context.Context,
+		token string,
+		reqData *dto.RevokeSession,
+	) (err error)
+	RevokeAllSessions(
+		ctx context.Context,
+		token string,
+		reqData *dto.RevokeAllSessions,
+	) (revoked int, err error)
+	RequestPasswordReset(
+		ctx context.Context,
+		reqData *dto.RequestPasswordReset,
+	) (ctxOut context.Context, err error)
+	ConfirmPasswordReset(
+		ctx context.Context,
+		reqData *dto.ConfirmPasswordReset,
+	) (ctxOut context.Context, err error)
+	VerifyEmail(
+		ctx context.Context,
+		reqData *dto.VerifyEmail,
+	) (ctxOut context.Context, err error)
+	ResendEmailVerification(
+		ctx context.Context,
+		reqData *dto.ResendEmailVerification,
+	) (ctxOut context.Context, err error)
+	EnrollMFA(
+		ctx context.Context,
+		token string,
+	) (enrollment *domain.MFAEnrollment, err error)
+	ConfirmMFA(
+		ctx context.Context,
+		token string,
+		reqData *dto.ConfirmMFA,
+	) (recoveryCodes []string, err error)
+	VerifyMFA(
+		ctx context.Context,
+		reqData *dto.VerifyMFA,
+	) (userWithTokens *domain.UserWithTokens, err error)
+	UnlockLogin(
+		ctx context.Context,
+		token string,
+		reqData *dto.UnlockLogin,
+	) (err error)
+	BlockUser(
+		ctx context.Context,
+		token string,
+		reqData *dto.BlockUser,
+	) (err error)
+	UnblockUser(
+		ctx context.Context,
+		token string,
+		reqData *dto.UserID,
+	) (err error)
+	DeleteUser(
+		ctx context.Context,
+		token string,
+		reqData *dto.UserID,
+	) (err error)
+	ChangeEmail(
+		ctx context.Context,
+		token string,
+		reqData *dto.ChangeEmail,
+	) (err error)
 }
 
 type AuthHandlers struct {
 	log  *slog.Logger
 	auth authService
 	cfg  *config.Config
+	// proxies are allowed to forward client address in X-Real-IP and X-Forwarded-For
+	proxies clientip.TrustedProxies
 }
 
-func New(log *slog.Logger, cfg *config.Config, authService authService) AuthHandlers {
-	return AuthHandlers{log: log, cfg: cfg, auth: authService}
+func New(
+	log *slog.Logger,
+	cfg *config.Config,
+	authService authService,
+	proxies clientip.TrustedProxies,
+) AuthHandlers {
+	return AuthHandlers{log: log, cfg: cfg, auth: authService, proxies: proxies}
 }
 
 // handleBadRequest validates post body and writes messages to client. In case of using "err := render.DecodeJSON(r.Body, &reqData)"
@@ -86,6 +181,25 @@ func handleBadRequest[T json.Unmarshaler](w http.ResponseWriter, r *http.Request
 	return reqData, nil
 }
 
+// bearerToken extracts token from Authorization header.
+func bearerToken(r *http.Request) string {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer")
+	return strings.TrimSpace(token)
+}
+
+// clientFromRequest extracts device info saved with user session. Client
+// address is the remote address, forwarding headers are read only when the
+// remote address is a trusted proxy.
+func (a *AuthHandlers) clientFromRequest(r *http.Request) dto.Client {
+	ip := clientip.Host(r.RemoteAddr)
+	if realIP := r.Header.Get("X-Real-IP"); realIP != "" && a.proxies.Trusted(ip) {
+		ip = strings.TrimSpace(realIP)
+	} else {
+		ip = a.proxies.ClientIP(r.RemoteAddr, r.Header.Values("X-Forwarded-For")...)
+	}
+	return dto.Client{UserAgent: r.UserAgent(), IP: ip}
+}
+
 func ctxWithTimeoutCause(r *http.Request, cfg *config.Config, textError string) (context.Context, context.CancelFunc) {
 	ctx, cancel := context.WithTimeoutCause(
 		r.Context(),
@@ -101,7 +215,7 @@ func ctxWithTimeoutCause(r *http.Request, cfg *config.Config, textError string)
 // @Accept json
 // @Produce json
 // @Param body body dto.Login true "Login request"
-// @Success 201 {object} dto.Response "Login successful"
+// @Success 201 {object} dto.Response "Login successful, only mfa_token is returned if MFA is enabled"
 // @Router /auth/login [post]
 func (a *AuthHandlers) Login(w http.ResponseWriter, r *http.Request) {
 
@@ -109,16 +223,30 @@ func (a *AuthHandlers) Login(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		return
 	}
+	reqData.Client = a.clientFromRequest(r)
 
 	ctx, cancel := ctxWithTimeoutCause(r, a.cfg, "login timeout")
 	defer cancel()
 
 	userWithTokens, err := a.auth.Login(ctx, reqData)
 	if err != nil {
+		var lockedErr *authservice.LoginLockedError
+		if errors.As(err, &lockedErr) {
+			dto.ResponseErrorTooManyRequests(w, "too many failed login attempts", lockedErr.RetryAfter)
+			return
+		}
 		if errors.Is(err, authservice.ErrInvalidCredentials) {
 			dto.ResponseErrorNotFound(w, "user not found")
 			return
 		}
+		if errors.Is(err, authservice.ErrEmailNotVerified) {
+			dto.ResponseErrorForbidden(w, "email not verified")
+			return
+		}
+		if errors.Is(err, authservice.ErrUserBlocked) {
+			dto.ResponseErrorForbidden(w, "user is blocked")
+			return
+		}
 		dto.ResponseErrorInternal(w, "internal server error")
 		return
 	}
@@ -152,6 +280,8 @@ func (a *AuthHandlers) Logout(w http.ResponseWriter, r *http.Request) {
 			dto.ResponseErrorBadRequest(w, "token error")
 		case errors.Is(err, authservice.ErrTokenTTLExpired):
 			dto.ResponseErrorStatusConflict(w, "token ttl expired")
+		case errors.Is(err, authservice.ErrUserBlocked):
+			dto.ResponseErrorForbidden(w, "user is blocked")
 		default:
 			dto.ResponseErrorInternal(w, "internal server error")
 		}
@@ -173,6 +303,7 @@ func (a *AuthHandlers) Register(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		return
 	}
+	reqData.Client = a.clientFromRequest(r)
 	ctx, cancel := ctxWithTimeoutCause(r, a.cfg, "register timeout")
 	defer cancel()
 
@@ -202,6 +333,7 @@ func (a *AuthHandlers) Refresh(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		return
 	}
+	reqData.Client = a.clientFromRequest(r)
 
 	ctx, cancel := ctxWithTimeoutCause(r, a.cfg, "refresh timeout")
 	defer cancel()
@@ -216,10 +348,14 @@ func (a *AuthHandlers) Refresh(w http.ResponseWriter, r *http.Request) {
 			dto.ResponseErrorStatusConflict(w, "token wrong type, expected refresh")
 		case errors.Is(err, authservice.ErrTokenRevoked):
 			dto.ResponseErrorStatusConflict(w, "token revoked")
+		case errors.Is(err, authservice.ErrTokenReused):
+			dto.ResponseErrorStatusConflict(w, "token reused, all session tokens revoked")
 		case errors.Is(err, authservice.ErrTokenParsing):
 			dto.ResponseErrorBadRequest(w, "token error")
 		case errors.Is(err, authservice.ErrTokenTTLExpired):
 			dto.ResponseErrorStatusConflict(w, "token ttl expired")
+		case errors.Is(err, authservice.ErrUserBlocked):
+			dto.ResponseErrorForbidden(w, "user is blocked")
 		default:
 			dto.ResponseErrorInternal(w, "internal server error")
 		}
@@ -239,18 +375,572 @@ func (a *AuthHandlers) Info(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := ctxWithTimeoutCause(r, a.cfg, "info timeout")
 	defer cancel()
 
-	tokenString := r.Header.Get("Authorization")
-	token := strings.TrimPrefix(tokenString, "Bearer")
-	token = strings.TrimSpace(token)
-	user, err := a.auth.Info(ctx, token)
+	user, err := a.auth.Info(ctx, bearerToken(r))
 
 	if err != nil {
 		if errors.Is(err, authservice.ErrInvalidCredentials) {
 			dto.ResponseErrorNotFound(w, "user not found")
 			return
 		}
+		if errors.Is(err, authservice.ErrEmailNotVerified) {
+			dto.ResponseErrorForbidden(w, "email not verified")
+			return
+		}
 		dto.ResponseErrorInternal(w, "internal server error")
 		return
 	}
 	dto.UserResponseOk(w, user)
 }
+
+// @Summary JWKS
+// @Description Provides public keys in JWK format to verify tokens offline.
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} dto.JWKS "public keys"
+// @Router /.well-known/jwks.json [get]
+func (a *AuthHandlers) JWKS(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		dto.ResponseErrorNowAllowed(w, "only GET method allowed")
+		return
+	}
+	// keys change rarely, so clients are allowed to cache them for a while
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	dto.ResponseOKJWKS(w, a.auth.JWKS(r.Context()))
+}
+
+// @Summary RotateSigningKey
+// @Description Promotes a new signing key. The previous key is accepted until the grace period ends. Admins only.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dto.RotateSigningKey true "RotateSigningKey request"
+// @Success 200 {object} dto.KeyRotationResponse "key rotated"
+// @Router /auth/keys/rotate [post]
+// @Security BearerAuth
+func (a *AuthHandlers) RotateSigningKey(w http.ResponseWriter, r *http.Request) {
+	reqData, err := handleBadRequest[*dto.RotateSigningKey](w, r, &dto.RotateSigningKey{})
+	if err != nil {
+		return
+	}
+	ctx, cancel := ctxWithTimeoutCause(r, a.cfg, "rotate signing key timeout")
+	defer cancel()
+
+	keyRotation, err := a.auth.RotateSigningKey(ctx, bearerToken(r), reqData)
+	if err != nil {
+		handleAuthorizationError(w, err)
+		return
+	}
+	dto.ResponseOKKeyRotation(w, keyRotation)
+}
+
+// @Summary GrantRole
+// @Description Assigns role to user. Permissions of the role are added to tokens issued afterwards. Admins only.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dto.UserRole true "GrantRole request"
+// @Success 200 {object} dto.Response "role granted"
+// @Router /auth/roles/grant [post]
+// @Security BearerAuth
+func (a *AuthHandlers) GrantRole(w http.ResponseWriter, r *http.Request) {
+	reqData, err := handleBadRequest[*dto.UserRole](w, r, &dto.UserRole{})
+	if err != nil {
+		return
+	}
+	ctx, cancel := ctxWithTimeoutCause(r, a.cfg, "grant role timeout")
+	defer cancel()
+
+	err = a.auth.GrantRole(ctx, bearerToken(r), reqData)
+	if err != nil {
+		switch {
+		case errors.Is(err, authservice.ErrUserNotFound):
+			dto.ResponseErrorNotFound(w, "user not found")
+		case errors.Is(err, authservice.ErrRoleNotFound):
+			dto.ResponseErrorNotFound(w, "role not found")
+		default:
+			handleAuthorizationError(w, err)
+		}
+		return
+	}
+	dto.ResponseOK(w)
+}
+
+// @Summary RevokeRole
+// @Description Removes role from user. Tokens issued before keep the role permissions until they expire. Admins only.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dto.UserRole true "RevokeRole request"
+// @Success 200 {object} dto.Response "role revoked"
+// @Router /auth/roles/revoke [post]
+// @Security BearerAuth
+func (a *AuthHandlers) RevokeRole(w http.ResponseWriter, r *http.Request) {
+	reqData, err := handleBadRequest[*dto.UserRole](w, r, &dto.UserRole{})
+	if err != nil {
+		return
+	}
+	ctx, cancel := ctxWithTimeoutCause(r, a.cfg, "revoke role timeout")
+	defer cancel()
+
+	err = a.auth.RevokeRole(ctx, bearerToken(r), reqData)
+	if err != nil {
+		if errors.Is(err, authservice.ErrRoleNotGranted) {
+			dto.ResponseErrorNotFound(w, "role not granted")
+			return
+		}
+		handleAuthorizationError(w, err)
+		return
+	}
+	dto.ResponseOK(w)
+}
+
+// @Summary ListSessions
+// @Description Lists sessions (devices) of the user. Sessions of other users are available to admins only.
+// @Tags Auth
+// @Produce json
+// @Param user_id query string false "User ID, token owner if empty"
+// @Success 200 {object} dto.SessionsResponse "sessions"
+// @Router /auth/sessions [get]
+// @Security BearerAuth
+func (a *AuthHandlers) ListSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		dto.ResponseErrorNowAllowed(w, "only GET method allowed")
+		return
+	}
+	userID := r.URL.Query().Get("user_id")
+	if userID != "" {
+		if err := validator.New().Var(userID, "uuid"); err != nil {
+			dto.ResponseErrorBadRequest(w, "user_id must be uuid")
+			return
+		}
+	}
+	ctx, cancel := ctxWithTimeoutCause(r, a.cfg, "list sessions timeout")
+	defer cancel()
+
+	sessions, err := a.auth.ListSessions(ctx, bearerToken(r), userID)
+	if err != nil {
+		handleAuthorizationError(w, err)
+		return
+	}
+	dto.ResponseOKSessions(w, sessions)
+}
+
+// @Summary RevokeSession
+// @Description Revokes all tokens of the session. Sessions of other users can be revoked by admins only.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dto.RevokeSession true "RevokeSession request"
+// @Success 200 {object} dto.Response "session revoked"
+// @Router /auth/sessions/revoke [post]
+// @Security BearerAuth
+func (a *AuthHandlers) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	reqData, err := handleBadRequest[*dto.RevokeSession](w, r, &dto.RevokeSession{})
+	if err != nil {
+		return
+	}
+	ctx, cancel := ctxWithTimeoutCause(r, a.cfg, "revoke session timeout")
+	defer cancel()
+
+	err = a.auth.RevokeSession(ctx, bearerToken(r), reqData)
+	if err != nil {
+		if errors.Is(err, authservice.ErrSessionNotFound) {
+			dto.ResponseErrorNotFound(w, "session not found")
+			return
+		}
+		handleAuthorizationError(w, err)
+		return
+	}
+	dto.ResponseOK(w)
+}
+
+// @Summary RevokeAllSessions
+// @Description Logs out everywhere: revokes tokens of all user sessions, including the current one.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dto.RevokeAllSessions true "RevokeAllSessions request"
+// @Success 200 {object} dto.RevokeSessionsResponse "sessions revoked"
+// @Router /auth/sessions/revoke-all [post]
+// @Security BearerAuth
+func (a *AuthHandlers) RevokeAllSessions(w http.ResponseWriter, r *http.Request) {
+	reqData, err := handleBadRequest[*dto.RevokeAllSessions](w, r, &dto.RevokeAllSessions{})
+	if err != nil {
+		return
+	}
+	ctx, cancel := ctxWithTimeoutCause(r, a.cfg, "revoke sessions timeout")
+	defer cancel()
+
+	revoked, err := a.auth.RevokeAllSessions(ctx, bearerToken(r), reqData)
+	if err != nil {
+		handleAuthorizationError(w, err)
+		return
+	}
+	dto.ResponseOKRevokeSessions(w, revoked)
+}
+
+// @Summary RequestPasswordReset
+// @Description Sends password reset token to user email. Responds with success for unknown emails too.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dto.RequestPasswordReset true "RequestPasswordReset request"
+// @Success 200 {object} dto.Response "password reset requested"
+// @Router /auth/password/reset [post]
+func (a *AuthHandlers) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	reqData, err := handleBadRequest[*dto.RequestPasswordReset](w, r, &dto.RequestPasswordReset{})
+	if err != nil {
+		return
+	}
+	ctx, cancel := ctxWithTimeoutCause(r, a.cfg, "password reset timeout")
+	defer cancel()
+
+	_, err = a.auth.RequestPasswordReset(ctx, reqData)
+	if err != nil {
+		dto.ResponseErrorInternal(w, "internal server error")
+		return
+	}
+	dto.ResponseOK(w)
+}
+
+// @Summary ConfirmPasswordReset
+// @Description Sets new password using password reset token and revokes all user sessions.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dto.ConfirmPasswordReset true "ConfirmPasswordReset request"
+// @Success 200 {object} dto.Response "password changed"
+// @Router /auth/password/reset/confirm [post]
+func (a *AuthHandlers) ConfirmPasswordReset(w http.ResponseWriter, r *http.Request) {
+	reqData, err := handleBadRequest[*dto.ConfirmPasswordReset](w, r, &dto.ConfirmPasswordReset{})
+	if err != nil {
+		return
+	}
+	ctx, cancel := ctxWithTimeoutCause(r, a.cfg, "password reset timeout")
+	defer cancel()
+
+	_, err = a.auth.ConfirmPasswordReset(ctx, reqData)
+	if err != nil {
+		switch {
+		case errors.Is(err, authservice.ErrResetTokenInvalid):
+			dto.ResponseErrorBadRequest(w, "password reset token is invalid or expired")
+		case errors.Is(err, authservice.ErrUserNotFound):
+			dto.ResponseErrorNotFound(w, "user not found")
+		default:
+			dto.ResponseErrorInternal(w, "internal server error")
+		}
+		return
+	}
+	dto.ResponseOK(w)
+}
+
+// @Summary VerifyEmail
+// @Description Verifies user email using token sent to the email on registration.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dto.VerifyEmail true "VerifyEmail request"
+// @Success 200 {object} dto.Response "email verified"
+// @Router /auth/email/verify [post]
+func (a *AuthHandlers) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	reqData, err := handleBadRequest[*dto.VerifyEmail](w, r, &dto.VerifyEmail{})
+	if err != nil {
+		return
+	}
+	ctx, cancel := ctxWithTimeoutCause(r, a.cfg, "email verification timeout")
+	defer cancel()
+
+	_, err = a.auth.VerifyEmail(ctx, reqData)
+	if err != nil {
+		switch {
+		case errors.Is(err, authservice.ErrVerificationTokenInvalid):
+			dto.ResponseErrorBadRequest(w, "email verification token is invalid or expired")
+		case errors.Is(err, authservice.ErrUserNotFound):
+			dto.ResponseErrorNotFound(w, "user not found")
+		default:
+			dto.ResponseErrorInternal(w, "internal server error")
+		}
+		return
+	}
+	dto.ResponseOK(w)
+}
+
+// @Summary ResendEmailVerification
+// @Description Sends a new email verification token to user email. Responds with success for unknown and already verified emails too.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dto.ResendEmailVerification true "ResendEmailVerification request"
+// @Success 200 {object} dto.Response "email verification requested"
+// @Router /auth/email/verify/resend [post]
+func (a *AuthHandlers) ResendEmailVerification(w http.ResponseWriter, r *http.Request) {
+	reqData, err := handleBadRequest[*dto.ResendEmailVerification](w, r, &dto.ResendEmailVerification{})
+	if err != nil {
+		return
+	}
+	ctx, cancel := ctxWithTimeoutCause(r, a.cfg, "email verification timeout")
+	defer cancel()
+
+	_, err = a.auth.ResendEmailVerification(ctx, reqData)
+	if err != nil {
+		dto.ResponseErrorInternal(w, "internal server error")
+		return
+	}
+	dto.ResponseOK(w)
+}
+
+// @Summary EnrollMFA
+// @Description Generates TOTP secret, MFA is enabled after the secret is confirmed with a code.
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} dto.MFAEnrollmentResponse "secret and otpauth uri"
+// @Router /auth/mfa/enroll [post]
+// @Security BearerAuth
+func (a *AuthHandlers) EnrollMFA(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := ctxWithTimeoutCause(r, a.cfg, "mfa enrollment timeout")
+	defer cancel()
+
+	enrollment, err := a.auth.EnrollMFA(ctx, bearerToken(r))
+	if err != nil {
+		if errors.Is(err, authservice.ErrMFAEnabled) {
+			dto.ResponseErrorStatusConflict(w, "mfa already enabled")
+			return
+		}
+		handleAuthorizationError(w, err)
+		return
+	}
+	dto.ResponseOKMFAEnrollment(w, enrollment)
+}
+
+// @Summary ConfirmMFA
+// @Description Enables MFA if code matches enrolled secret, returns one-time recovery codes.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dto.ConfirmMFA true "ConfirmMFA request"
+// @Success 200 {object} dto.RecoveryCodesResponse "mfa enabled"
+// @Router /auth/mfa/confirm [post]
+// @Security BearerAuth
+func (a *AuthHandlers) ConfirmMFA(w http.ResponseWriter, r *http.Request) {
+	reqData, err := handleBadRequest[*dto.ConfirmMFA](w, r, &dto.ConfirmMFA{})
+	if err != nil {
+		return
+	}
+	ctx, cancel := ctxWithTimeoutCause(r, a.cfg, "mfa confirmation timeout")
+	defer cancel()
+
+	recoveryCodes, err := a.auth.ConfirmMFA(ctx, bearerToken(r), reqData)
+	if err != nil {
+		switch {
+		case errors.Is(err, authservice.ErrMFAEnabled):
+			dto.ResponseErrorStatusConflict(w, "mfa already enabled")
+		case errors.Is(err, authservice.ErrMFANotEnrolled):
+			dto.ResponseErrorBadRequest(w, "mfa not enrolled")
+		case errors.Is(err, authservice.ErrMFAInvalid):
+			dto.ResponseErrorBadRequest(w, "invalid code")
+		default:
+			handleAuthorizationError(w, err)
+		}
+		return
+	}
+	dto.ResponseOKRecoveryCodes(w, recoveryCodes)
+}
+
+// @Summary VerifyMFA
+// @Description Completes login with mfa_token returned by Login and TOTP or recovery code.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dto.VerifyMFA true "VerifyMFA request"
+// @Success 201 {object} dto.Response "Login successful"
+// @Router /auth/mfa/verify [post]
+func (a *AuthHandlers) VerifyMFA(w http.ResponseWriter, r *http.Request) {
+	reqData, err := handleBadRequest[*dto.VerifyMFA](w, r, &dto.VerifyMFA{})
+	if err != nil {
+		return
+	}
+	reqData.Client = a.clientFromRequest(r)
+	ctx, cancel := ctxWithTimeoutCause(r, a.cfg, "mfa verification timeout")
+	defer cancel()
+
+	userWithTokens, err := a.auth.VerifyMFA(ctx, reqData)
+	if err != nil {
+		switch {
+		case errors.Is(err, authservice.ErrMFAInvalid):
+			dto.ResponseErrorBadRequest(w, "mfa challenge or code is invalid, login again")
+		case errors.Is(err, authservice.ErrUserNotFound):
+			dto.ResponseErrorNotFound(w, "user not found")
+		case errors.Is(err, authservice.ErrUserBlocked):
+			dto.ResponseErrorForbidden(w, "user is blocked")
+		default:
+			dto.ResponseErrorInternal(w, "internal server error")
+		}
+		return
+	}
+	dto.ResponseOKAccessRefresh(w, userWithTokens)
+}
+
+// @Summary UnlockLogin
+// @Description Removes login lockout after failed attempts of the user account and/or IP. Admins only.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dto.UnlockLogin true "UnlockLogin request"
+// @Success 200 {object} dto.Response "login unlocked"
+// @Router /auth/users/unlock [post]
+// @Security BearerAuth
+func (a *AuthHandlers) UnlockLogin(w http.ResponseWriter, r *http.Request) {
+	reqData, err := handleBadRequest[*dto.UnlockLogin](w, r, &dto.UnlockLogin{})
+	if err != nil {
+		return
+	}
+	ctx, cancel := ctxWithTimeoutCause(r, a.cfg, "unlock login timeout")
+	defer cancel()
+
+	err = a.auth.UnlockLogin(ctx, bearerToken(r), reqData)
+	if err != nil {
+		if errors.Is(err, authservice.ErrUserNotFound) {
+			dto.ResponseErrorNotFound(w, "user not found")
+			return
+		}
+		handleAuthorizationError(w, err)
+		return
+	}
+	dto.ResponseOK(w)
+}
+
+// @Summary BlockUser
+// @Description Blocks user login until the time or until unblocked and revokes user sessions. Admins only.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dto.BlockUser true "BlockUser request"
+// @Success 200 {object} dto.Response "user blocked"
+// @Router /auth/users/block [post]
+// @Security BearerAuth
+func (a *AuthHandlers) BlockUser(w http.ResponseWriter, r *http.Request) {
+	reqData, err := handleBadRequest[*dto.BlockUser](w, r, &dto.BlockUser{})
+	if err != nil {
+		return
+	}
+	ctx, cancel := ctxWithTimeoutCause(r, a.cfg, "block user timeout")
+	defer cancel()
+
+	err = a.auth.BlockUser(ctx, bearerToken(r), reqData)
+	if err != nil {
+		handleManageUserError(w, err)
+		return
+	}
+	dto.ResponseOK(w)
+}
+
+// @Summary UnblockUser
+// @Description Removes login block of the user. Admins only.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dto.UserID true "UnblockUser request"
+// @Success 200 {object} dto.Response "user unblocked"
+// @Router /auth/users/unblock [post]
+// @Security BearerAuth
+func (a *AuthHandlers) UnblockUser(w http.ResponseWriter, r *http.Request) {
+	reqData, err := handleBadRequest[*dto.UserID](w, r, &dto.UserID{})
+	if err != nil {
+		return
+	}
+	ctx, cancel := ctxWithTimeoutCause(r, a.cfg, "unblock user timeout")
+	defer cancel()
+
+	err = a.auth.UnblockUser(ctx, bearerToken(r), reqData)
+	if err != nil {
+		handleManageUserError(w, err)
+		return
+	}
+	dto.ResponseOK(w)
+}
+
+// @Summary DeleteUser
+// @Description Deletes user and revokes user sessions. Admins only.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dto.UserID true "DeleteUser request"
+// @Success 200 {object} dto.Response "user deleted"
+// @Router /auth/users/delete [post]
+// @Security BearerAuth
+func (a *AuthHandlers) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	reqData, err := handleBadRequest[*dto.UserID](w, r, &dto.UserID{})
+	if err != nil {
+		return
+	}
+	ctx, cancel := ctxWithTimeoutCause(r, a.cfg, "delete user timeout")
+	defer cancel()
+
+	err = a.auth.DeleteUser(ctx, bearerToken(r), reqData)
+	if err != nil {
+		handleManageUserError(w, err)
+		return
+	}
+	dto.ResponseOK(w)
+}
+
+// @Summary ChangeEmail
+// @Description Replaces email of the token owner. The new email is not verified, verification email is sent to it.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dto.ChangeEmail true "ChangeEmail request"
+// @Success 200 {object} dto.Response "email changed"
+// @Router /auth/email/change [post]
+// @Security BearerAuth
+func (a *AuthHandlers) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	reqData, err := handleBadRequest[*dto.ChangeEmail](w, r, &dto.ChangeEmail{})
+	if err != nil {
+		return
+	}
+	ctx, cancel := ctxWithTimeoutCause(r, a.cfg, "change email timeout")
+	defer cancel()
+
+	err = a.auth.ChangeEmail(ctx, bearerToken(r), reqData)
+	if err != nil {
+		switch {
+		case errors.Is(err, authservice.ErrInvalidCredentials):
+			dto.ResponseErrorBadRequest(w, "invalid credentials")
+		case errors.Is(err, storage.ErrUserExists):
+			dto.ResponseErrorStatusConflict(w, "user with this email already exists")
+		case errors.Is(err, authservice.ErrUserNotFound):
+			dto.ResponseErrorNotFound(w, "user not found")
+		default:
+			handleAuthorizationError(w, err)
+		}
+		return
+	}
+	dto.ResponseOK(w)
+}
+
+// handleManageUserError writes errors of user management.
+func handleManageUserError(w http.ResponseWriter, err error) {
+	if errors.Is(err, authservice.ErrUserNotFound) {
+		dto.ResponseErrorNotFound(w, "user not found")
+		return
+	}
+	handleAuthorizationError(w, err)
+}
+
+// handleAuthorizationError writes token validation and permission errors.
+func handleAuthorizationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, authservice.ErrPermissionDenied):
+		dto.ResponseErrorForbidden(w, "admin rights required")
+	case errors.Is(err, authservice.ErrMFARequired):
+		dto.ResponseErrorForbidden(w, "login with mfa required")
+	case errors.Is(err, authservice.ErrTokenRevoked):
+		dto.ResponseErrorStatusConflict(w, "token revoked")
+	case errors.Is(err, authservice.ErrTokenParsing):
+		dto.ResponseErrorBadRequest(w, "token error")
+	case errors.Is(err, authservice.ErrTokenWrongType):
+		dto.ResponseErrorBadRequest(w, "token wrong type, expected access")
+	default:
+		dto.ResponseErrorInternal(w, "internal server error")
+	}
+}
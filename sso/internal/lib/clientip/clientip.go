@@ -0,0 +1,90 @@
+// Package clientip resolves address of a client sending request. Forwarding
+// headers are set by clients as well as by proxies, so they are read only
+// when the request comes from a trusted proxy.
+package clientip
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// TrustedProxies is a list of networks of proxies allowed to forward client
+// address. Zero value trusts no proxies.
+type TrustedProxies struct {
+	networks []*net.IPNet
+}
+
+// NewTrustedProxies parses proxies given as CIDR or single IP address.
+func NewTrustedProxies(proxies []string) (TrustedProxies, error) {
+	var t TrustedProxies
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return TrustedProxies{}, fmt.Errorf("trusted proxy %q is not ip address", proxy)
+			}
+			bits := 8 * net.IPv4len
+			if ip.To4() == nil {
+				bits = 8 * net.IPv6len
+			}
+			proxy = fmt.Sprintf("%s/%d", proxy, bits)
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return TrustedProxies{}, fmt.Errorf("trusted proxy %q: %w", proxy, err)
+		}
+		t.networks = append(t.networks, network)
+	}
+	return t, nil
+}
+
+// Trusted reports whether ip belongs to a trusted proxy.
+func (t TrustedProxies) Trusted(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range t.networks {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns client address of request received from peer, forwardedFor
+// are X-Forwarded-For values. Each proxy appends address it received request
+// from, so the chain is walked from peer to the left while addresses are
+// trusted proxies. The first untrusted address is the client, addresses left
+// of it are set by the client and ignored.
+func (t TrustedProxies) ClientIP(peer string, forwardedFor ...string) string {
+	ip := Host(peer)
+	if !t.Trusted(ip) {
+		return ip
+	}
+	var chain []string
+	for _, value := range forwardedFor {
+		for _, addr := range strings.Split(value, ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				chain = append(chain, addr)
+			}
+		}
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		ip = Host(chain[i])
+		if !t.Trusted(ip) {
+			break
+		}
+	}
+	return ip
+}
+
+// Host strips port from address, address without port is returned as is.
+func Host(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
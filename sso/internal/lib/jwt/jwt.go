@@ -8,24 +8,38 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// NewToken creates new JWT token for given user and app.
+// NewToken creates new JWT token for given user and app signed with the signing key.
+// familyID links all tokens issued since login, tokenID is unique id of the token,
+// authMethods tell how user was authenticated on login.
 func NewToken(
 	user domain.User,
 	cfg *config.Config,
 	tokenType string,
+	signingKey *SigningKey,
+	familyID string,
+	tokenID string,
+	authMethods []string,
 ) (string, error) {
-	token := jwt.New(jwt.SigningMethodHS256)
+	token := jwt.New(signingKey.Method)
+	// kid lets verifiers pick the right key while keys are being rotated
+	token.Header["kid"] = signingKey.ID
 
 	claims := token.Claims.(jwt.MapClaims)
 	claims["token_type"] = tokenType
+	claims["jti"] = tokenID
+	claims["fid"] = familyID
 	claims["uid"] = user.ID
 	claims["email"] = user.Email
+	// roles and permissions let other services authorize requests without calling sso
+	claims["roles"] = user.Roles
+	claims["permissions"] = user.Permissions
+	claims["amr"] = authMethods
 	if tokenType == "access" {
 		claims["exp"] = time.Now().Add(cfg.AccessTokenTtl).Unix()
 	} else {
 		claims["exp"] = time.Now().Add(cfg.RefreshTokenTtl).Unix()
 	}
-	tokenString, err := token.SignedString([]byte(cfg.ServiceSecret))
+	tokenString, err := token.SignedString(signingKey.PrivateKey)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,114 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+)
+
+var (
+	ErrKeyNotFound       = errors.New("signing key not found")
+	ErrNoActiveKey       = errors.New("active signing key not found")
+	ErrKeyAlreadyRetired = errors.New("signing key already retired")
+)
+
+// Keyring holds the active key to sign tokens and retiring keys which are
+// still accepted to verify tokens until their grace period ends.
+type Keyring struct {
+	mu       sync.RWMutex
+	active   *SigningKey
+	retiring map[string]retiringKey
+}
+
+type retiringKey struct {
+	key      *SigningKey
+	retireAt time.Time
+}
+
+// NewKeyring returns keyring with the only active key.
+func NewKeyring(active *SigningKey) *Keyring {
+	return &Keyring{
+		active:   active,
+		retiring: make(map[string]retiringKey),
+	}
+}
+
+// Active returns key to sign new tokens.
+func (k *Keyring) Active() *SigningKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.active
+}
+
+// Get returns key to verify token by its "kid" header. Tokens without "kid"
+// were issued before rotation support and are verified with the active key.
+func (k *Keyring) Get(kid string) (*SigningKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if kid == "" || kid == k.active.ID {
+		return k.active, nil
+	}
+	retiring, ok := k.retiring[kid]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	if time.Now().After(retiring.retireAt) {
+		return nil, ErrKeyAlreadyRetired
+	}
+	return retiring.key, nil
+}
+
+// PublicKeys returns public parts of active and not yet retired keys.
+func (k *Keyring) PublicKeys() []domain.PublicKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	publicKeys := []domain.PublicKey{k.active.PublicKey()}
+	now := time.Now()
+	for _, retiring := range k.retiring {
+		if now.After(retiring.retireAt) {
+			continue
+		}
+		publicKeys = append(publicKeys, retiring.key.PublicKey())
+	}
+	return publicKeys
+}
+
+// Promote makes key active, the previous active key is accepted until retireAt.
+func (k *Keyring) Promote(key *SigningKey, retireAt time.Time) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.retiring[k.active.ID] = retiringKey{key: k.active, retireAt: retireAt}
+	k.active = key
+}
+
+// Load replaces keyring content with stored keys. Nothing is changed if there
+// are no stored keys, so the key from config stays active until the first rotation.
+func (k *Keyring) Load(keys []domain.SigningKey) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	var active *SigningKey
+	retiring := make(map[string]retiringKey, len(keys))
+	for _, storedKey := range keys {
+		key, err := NewSigningKey(storedKey.ID, storedKey.Algorithm, storedKey.PrivateKey)
+		if err != nil {
+			return fmt.Errorf("failed to load key %s: %w", storedKey.ID, err)
+		}
+		if storedKey.Active {
+			active = key
+			continue
+		}
+		retiring[key.ID] = retiringKey{key: key, retireAt: storedKey.ExpiresAt}
+	}
+	if active == nil {
+		return ErrNoActiveKey
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.active = active
+	k.retiring = retiring
+	return nil
+}
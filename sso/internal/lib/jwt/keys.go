@@ -0,0 +1,114 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+
+	rsaKeyBits = 2048
+)
+
+var (
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrInvalidPrivateKey    = errors.New("invalid private key")
+)
+
+// SigningKey is a private key used to sign tokens.
+type SigningKey struct {
+	ID         string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.Signer
+}
+
+// NewSigningKey parses PEM encoded private key and checks it matches the algorithm.
+func NewSigningKey(id string, algorithm string, privateKeyPEM string) (*SigningKey, error) {
+	block, _ := pem.Decode([]byte(privateKeyPEM))
+	if block == nil {
+		return nil, fmt.Errorf("%w: pem block not found", ErrInvalidPrivateKey)
+	}
+	var (
+		privateKey any
+		err        error
+	)
+	privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		// RSA keys generated by openssl genrsa might be in PKCS#1 format.
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidPrivateKey, err)
+		}
+	}
+
+	switch algorithm {
+	case AlgorithmRS256:
+		key, ok := privateKey.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: RS256 requires RSA key", ErrInvalidPrivateKey)
+		}
+		return &SigningKey{ID: id, Method: jwt.SigningMethodRS256, PrivateKey: key}, nil
+	case AlgorithmEdDSA:
+		key, ok := privateKey.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: EdDSA requires Ed25519 key", ErrInvalidPrivateKey)
+		}
+		return &SigningKey{ID: id, Method: jwt.SigningMethodEdDSA, PrivateKey: key}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, algorithm)
+	}
+}
+
+// GenerateSigningKey generates a new key pair with random key id.
+func GenerateSigningKey(algorithm string) (*SigningKey, error) {
+	switch algorithm {
+	case AlgorithmRS256:
+		key, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, err
+		}
+		return &SigningKey{ID: uuid.NewString(), Method: jwt.SigningMethodRS256, PrivateKey: key}, nil
+	case AlgorithmEdDSA:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return &SigningKey{ID: uuid.NewString(), Method: jwt.SigningMethodEdDSA, PrivateKey: key}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, algorithm)
+	}
+}
+
+// Export returns the key with PEM encoded private key to be stored.
+func (k *SigningKey) Export() (domain.SigningKey, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(k.PrivateKey)
+	if err != nil {
+		return domain.SigningKey{}, err
+	}
+	return domain.SigningKey{
+		ID:         k.ID,
+		Algorithm:  k.Method.Alg(),
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+	}, nil
+}
+
+// PublicKey returns public part of the signing key to verify tokens.
+func (k *SigningKey) PublicKey() domain.PublicKey {
+	return domain.PublicKey{
+		ID:        k.ID,
+		Algorithm: k.Method.Alg(),
+		Key:       k.PrivateKey.Public(),
+	}
+}
@@ -0,0 +1,89 @@
+// Package totp implements time-based one-time passwords (RFC 6238) compatible
+// with authenticator apps: HMAC-SHA1, 30 seconds period, 6 digits.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	period = 30 * time.Second
+	digits = 6
+	// secretSize is recommended by RFC 4226 for HMAC-SHA1.
+	secretSize = 20
+	// skew is a number of periods before and after current one accepted to
+	// tolerate clock drift between server and user device.
+	skew = 1
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a new random base32 encoded secret.
+func GenerateSecret() (string, error) {
+	b := make([]byte, secretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(b), nil
+}
+
+// URI returns otpauth URI of the secret, authenticator apps show it as QR code.
+// See https://github.com/google/google-authenticator/wiki/Key-Uri-Format
+func URI(issuer string, account string, secret string) string {
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(digits))
+	params.Set("period", fmt.Sprint(int(period.Seconds())))
+	label := url.PathEscape(issuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+// Code returns one-time password of the secret at time t.
+func Code(secret string, t time.Time) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", fmt.Errorf("totp: invalid secret: %w", err)
+	}
+	return code(key, uint64(t.Unix())/uint64(period.Seconds())), nil
+}
+
+// Validate reports whether passcode is valid for the secret at time t and
+// returns time step of the passcode. A code is valid during several steps, so
+// callers accept it once by rejecting steps not greater than the last accepted.
+func Validate(secret string, passcode string, t time.Time) (int64, bool) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(passcode) != digits {
+		return 0, false
+	}
+	counter := uint64(t.Unix()) / uint64(period.Seconds())
+	for i := -skew; i <= skew; i++ {
+		expected := code(key, counter+uint64(i))
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(passcode)) == 1 {
+			return int64(counter) + int64(i), true
+		}
+	}
+	return 0, false
+}
+
+// code implements HOTP (RFC 4226).
+func code(key []byte, counter uint64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", digits, value%1_000_000)
+}
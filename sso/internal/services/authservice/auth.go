@@ -5,9 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"sync"
 	"time"
 
-	registrationv1 "github.com/AlexBlackNn/authloyalty/commands/proto/registration.v1/registration.v1"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/config"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/dto"
@@ -15,6 +16,7 @@ import (
 	"github.com/AlexBlackNn/authloyalty/sso/internal/storage"
 	"github.com/AlexBlackNn/authloyalty/sso/pkg/broker"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -39,6 +41,7 @@ type userStorage interface {
 		ctx context.Context,
 		email string,
 		passHash []byte,
+		newEvent func(uuid string) (domain.OutboxMessage, error),
 	) (string, error)
 	GetUser(
 		ctx context.Context,
@@ -48,10 +51,93 @@ type userStorage interface {
 		ctx context.Context,
 		email string,
 	) (domain.User, error)
-	UpdateSendStatus(
+	GrantRole(
 		ctx context.Context,
 		uuid string,
-		status string,
+		role string,
+		events ...domain.OutboxMessage,
+	) error
+	RevokeRole(
+		ctx context.Context,
+		uuid string,
+		role string,
+		events ...domain.OutboxMessage,
+	) error
+	BlockUser(
+		ctx context.Context,
+		uuid string,
+		reason string,
+		until *time.Time,
+		events ...domain.OutboxMessage,
+	) error
+	UnblockUser(
+		ctx context.Context,
+		uuid string,
+		events ...domain.OutboxMessage,
+	) error
+	DeleteUser(
+		ctx context.Context,
+		uuid string,
+		events ...domain.OutboxMessage,
+	) error
+	UpdateEmail(
+		ctx context.Context,
+		uuid string,
+		email string,
+		events ...domain.OutboxMessage,
+	) error
+	UpdatePassword(
+		ctx context.Context,
+		uuid string,
+		passHash []byte,
+	) error
+	VerifyEmail(
+		ctx context.Context,
+		uuid string,
+		events ...domain.OutboxMessage,
+	) error
+	GetUndeliveredRegistrations(
+		ctx context.Context,
+		topic string,
+		staleAfter time.Duration,
+		maxAttempts int,
+		verifiedOnly bool,
+		limit int,
+	) ([]domain.UndeliveredRegistration, error)
+	CountExhaustedRegistrations(
+		ctx context.Context,
+		maxAttempts int,
+	) (int, error)
+	RetryRegistration(
+		ctx context.Context,
+		uuid string,
+		attempts int,
+		delay time.Duration,
+		event domain.OutboxMessage,
+	) (bool, error)
+	SaveMFASecret(
+		ctx context.Context,
+		uuid string,
+		secret string,
+	) error
+	GetMFASecret(
+		ctx context.Context,
+		uuid string,
+	) (string, error)
+	EnableMFA(
+		ctx context.Context,
+		uuid string,
+		recoveryCodeHashes []string,
+	) error
+	UseRecoveryCode(
+		ctx context.Context,
+		uuid string,
+		codeHash string,
+	) error
+	UseTOTPStep(
+		ctx context.Context,
+		uuid string,
+		step int64,
 	) error
 	HealthCheck(
 		ctx context.Context,
@@ -72,6 +158,114 @@ type tokenStorage interface {
 		ctx context.Context,
 		token string,
 	) (int64, error)
+	SaveTokenFamily(
+		ctx context.Context,
+		familyID string,
+		tokenID string,
+		ttl time.Duration,
+	) error
+	RotateTokenFamily(
+		ctx context.Context,
+		familyID string,
+		tokenID string,
+		nextTokenID string,
+		ttl time.Duration,
+	) error
+	RevokeTokenFamily(
+		ctx context.Context,
+		familyID string,
+		ttl time.Duration,
+	) error
+	CheckTokenFamilyRevoked(
+		ctx context.Context,
+		familyID string,
+	) (bool, error)
+	SaveSession(
+		ctx context.Context,
+		session domain.Session,
+		ttl time.Duration,
+	) error
+	RefreshSession(
+		ctx context.Context,
+		session domain.Session,
+		ttl time.Duration,
+	) error
+	GetSessions(
+		ctx context.Context,
+		userID string,
+	) ([]domain.Session, error)
+	DeleteSession(
+		ctx context.Context,
+		userID string,
+		sessionID string,
+	) error
+	SavePasswordResetToken(
+		ctx context.Context,
+		tokenHash string,
+		userID string,
+		ttl time.Duration,
+	) error
+	ConsumePasswordResetToken(
+		ctx context.Context,
+		tokenHash string,
+	) (string, error)
+	SaveEmailVerificationToken(
+		ctx context.Context,
+		tokenHash string,
+		userID string,
+		ttl time.Duration,
+	) error
+	ConsumeEmailVerificationToken(
+		ctx context.Context,
+		tokenHash string,
+	) (string, error)
+	LockEmailVerificationResend(
+		ctx context.Context,
+		userID string,
+		ttl time.Duration,
+	) (bool, error)
+	SaveMFAChallenge(
+		ctx context.Context,
+		tokenHash string,
+		userID string,
+		ttl time.Duration,
+	) error
+	ConsumeMFAChallenge(
+		ctx context.Context,
+		tokenHash string,
+	) (string, error)
+	LoginLockTTL(
+		ctx context.Context,
+		subject string,
+	) (time.Duration, error)
+	AddLoginFailure(
+		ctx context.Context,
+		subject string,
+		window time.Duration,
+	) (int64, error)
+	LockLogin(
+		ctx context.Context,
+		subject string,
+		ttl time.Duration,
+	) error
+	ResetLoginFailures(
+		ctx context.Context,
+		subject string,
+	) error
+}
+
+type keyStorage interface {
+	GetSigningKeys(
+		ctx context.Context,
+	) ([]domain.SigningKey, error)
+	RotateSigningKey(
+		ctx context.Context,
+		active domain.SigningKey,
+		retiring domain.SigningKey,
+	) error
+	DeleteExpiredSigningKeys(
+		ctx context.Context,
+	) error
 }
 
 type Auth struct {
@@ -79,8 +273,14 @@ type Auth struct {
 	userStorage   userStorage
 	tokenStorage  tokenStorage
 	objectStorage objectStorage
+	keyStorage    keyStorage
 	producer      getResponseChanSender
+	keyring       *jwtlib.Keyring
 	cfg           *config.Config
+	// keysReloadMu guards keysReloadedAt, the last load of keys caused by
+	// token signed by unknown key.
+	keysReloadMu   sync.Mutex
+	keysReloadedAt time.Time
 }
 
 type objectStorage interface {
@@ -97,53 +297,53 @@ func New(
 	tokenStorage tokenStorage,
 	producer getResponseChanSender,
 	objectStorage objectStorage,
+	keyStorage keyStorage,
+	keyring *jwtlib.Keyring,
 ) *Auth {
 	// Channel that is used by kafka to return sent message status.
 	brokerRespChan := producer.GetResponseChan()
-	// Getting status (async) from channel to determine if a message was sent successfully.
+	// Getting status (async) from channel to log notifications that were not
+	// delivered. Registration messages are published by the outbox relay, it
+	// waits for delivery itself.
 	go func() {
 		for brokerResponse := range brokerRespChan {
+			if errors.Is(brokerResponse.Err, broker.KafkaError) {
+				log.Error("broker error", "err", brokerResponse.Err)
+				continue
+			}
 			if brokerResponse.Err != nil {
-				if errors.Is(brokerResponse.Err, broker.KafkaError) {
-					log.Error("broker error", "err", brokerResponse.Err)
-					continue
-				}
 				log.Error(
-					"broker response error on message",
+					"failed to deliver notification",
 					"err", brokerResponse.Err,
+					"topic", brokerResponse.Topic,
 					"uuid", brokerResponse.UserUUID,
 				)
-				err := userStorage.UpdateSendStatus(
-					context.Background(), brokerResponse.UserUUID, "failed",
-				)
-				if err != nil {
-					log.Error(
-						"failed to update message status",
-						"err", err.Error(),
-						"uuid", brokerResponse.UserUUID,
-					)
-				}
-				continue
-			}
-			err := userStorage.UpdateSendStatus(
-				context.Background(),
-				brokerResponse.UserUUID,
-				"successful",
-			)
-			if err != nil {
-				log.Error("failed to update message status", "err", err.Error())
 			}
 		}
 	}()
 
-	return &Auth{
+	auth := &Auth{
 		log:           log,
 		userStorage:   userStorage,
 		tokenStorage:  tokenStorage,
 		objectStorage: objectStorage,
+		keyStorage:    keyStorage,
 		producer:      producer,
+		keyring:       keyring,
 		cfg:           cfg,
 	}
+
+	// Signing keys can be rotated by any sso instance, so all instances
+	// periodically load them from storage.
+	go func() {
+		ticker := time.NewTicker(cfg.JWT.ReloadInterval)
+		defer ticker.Stop()
+		for ; ; <-ticker.C {
+			auth.reloadSigningKeys(context.Background())
+		}
+	}()
+
+	return auth
 }
 
 const (
@@ -178,17 +378,50 @@ func (a *Auth) Login(
 		"user-id", md.Get("user-id"),
 		"x-trace-id", md.Get("x-trace-id"),
 	)
-	ctx, usrWithTokens, err := a.generateRefreshAccessToken(ctx, reqData.Email)
+	if err := a.checkLoginLock(ctx, reqData); err != nil {
+		a.log.Warn("login locked", "err", err.Error())
+		return nil, err
+	}
+	ctx, usrWithTokens, err := a.generateRefreshAccessToken(
+		ctx, reqData.Email, uuid.NewString(), []string{domain.AuthMethodPassword},
+	)
 	if err != nil {
+		if errors.Is(err, ErrInvalidCredentials) {
+			a.registerLoginFailure(ctx, reqData)
+		}
 		a.log.Error("Generation token failed:", "err", err.Error())
 		return nil, fmt.Errorf("generation token failed: %w", err)
 	}
 	if err = bcrypt.CompareHashAndPassword(
 		usrWithTokens.PassHash, []byte(reqData.Password),
 	); err != nil {
+		a.registerLoginFailure(ctx, reqData)
 		a.log.Warn("invalid credentials")
 		return nil, fmt.Errorf("invalid credentials: %w", ErrInvalidCredentials)
 	}
+	// only account attempts are reset, IP keeps counting across accounts
+	if err = a.tokenStorage.ResetLoginFailures(ctx, accountSubject(reqData.Email)); err != nil {
+		a.log.Error("failed to reset failed logins", "err", err.Error())
+	}
+	if usrWithTokens.Blocked {
+		a.log.Warn("user is blocked", "uuid", usrWithTokens.ID)
+		return nil, fmt.Errorf("login: %w", ErrUserBlocked)
+	}
+	if !a.cfg.EmailVerification.AllowUnverifiedLogin && !usrWithTokens.Verified() {
+		a.log.Warn("email not verified", "uuid", usrWithTokens.ID)
+		return nil, fmt.Errorf("login: %w", ErrEmailNotVerified)
+	}
+	if usrWithTokens.MFAEnabled {
+		// tokens are issued by VerifyMFA after the second factor is checked
+		mfaToken, err := a.issueMFAChallenge(ctx, usrWithTokens.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.UserWithTokens{User: usrWithTokens.User, MFAToken: mfaToken}, nil
+	}
+	if err = a.startSession(ctx, usrWithTokens, reqData.Client); err != nil {
+		return nil, err
+	}
 	return usrWithTokens, nil
 }
 
@@ -218,11 +451,77 @@ func (a *Auth) Refresh(
 		return nil, ErrTokenWrongType
 	}
 	log.Info("validate token successfully")
-	ctx, usrWithTokens, err := a.generateRefreshAccessToken(ctx, claims["email"].(string))
+	familyID, _ := claims["fid"].(string)
+	tokenID, _ := claims["jti"].(string)
+	if familyID == "" {
+		// token issued before token families were introduced, it is revoked
+		// and a new family is started
+		return a.refreshWithoutFamily(ctx, reqData, claims["email"].(string), ttl)
+	}
+	ctx, usrWithTokens, err := a.generateRefreshAccessToken(
+		ctx, claims["email"].(string), familyID, authMethods(claims),
+	)
+	if err != nil {
+		a.log.Error("failed to generate tokens", "err", err.Error())
+		return nil, err
+	}
+	if usrWithTokens.Blocked {
+		log.Warn("user is blocked", "uuid", usrWithTokens.ID)
+		return nil, ErrUserBlocked
+	}
+	err = a.tokenStorage.RotateTokenFamily(
+		ctx, familyID, tokenID, usrWithTokens.RefreshTokenID, a.cfg.RefreshTokenTtl,
+	)
+	if err != nil {
+		if errors.Is(err, storage.ErrTokenFamilyNotFound) {
+			return nil, ErrTokenRevoked
+		}
+		if !errors.Is(err, storage.ErrTokenReused) {
+			log.Error("failed to rotate token family", "err", err.Error())
+			return nil, err
+		}
+		// the token was already exchanged, so either the owner or an attacker
+		// holds a newer one. Revoke the whole family to end both sessions.
+		log.Warn("refresh token reuse detected, revoking token family", "family", familyID)
+		err = a.tokenStorage.RevokeTokenFamily(ctx, familyID, a.cfg.RefreshTokenTtl)
+		if err != nil {
+			log.Error("failed to revoke token family", "err", err.Error())
+			return nil, err
+		}
+		return nil, ErrTokenReused
+	}
+	err = a.tokenStorage.RefreshSession(ctx, domain.Session{
+		ID:          familyID,
+		UserID:      usrWithTokens.User.ID,
+		UserAgent:   reqData.UserAgent,
+		IP:          reqData.IP,
+		RefreshedAt: time.Now(),
+	}, a.cfg.RefreshTokenTtl)
+	if err != nil {
+		// tokens are valid even if session info is lost, so only log it
+		log.Error("failed to refresh session", "err", err.Error())
+	}
+	return usrWithTokens, nil
+}
+
+// refreshWithoutFamily blacklists refresh token without family and issues tokens of a new family.
+func (a *Auth) refreshWithoutFamily(
+	ctx context.Context,
+	reqData *dto.Refresh,
+	email string,
+	ttl time.Duration,
+) (*domain.UserWithTokens, error) {
+	ctx, usrWithTokens, err := a.generateRefreshAccessToken(
+		ctx, email, uuid.NewString(), []string{domain.AuthMethodPassword},
+	)
 	if err != nil {
 		a.log.Error("failed to generate tokens", "err", err.Error())
 		return nil, err
 	}
+	if usrWithTokens.Blocked {
+		a.log.Warn("user is blocked", "uuid", usrWithTokens.ID)
+		return nil, ErrUserBlocked
+	}
 	a.log.Info("saving refresh token to redis")
 	err = a.tokenStorage.SaveToken(ctx, reqData.Token, ttl)
 	if err != nil {
@@ -230,9 +529,41 @@ func (a *Auth) Refresh(
 		return nil, err
 	}
 	a.log.Info("token saved to redis successfully")
+	if err = a.startSession(ctx, usrWithTokens, reqData.Client); err != nil {
+		return nil, err
+	}
 	return usrWithTokens, nil
 }
 
+// startSession saves the token family and the session it belongs to.
+func (a *Auth) startSession(
+	ctx context.Context,
+	usrWithTokens *domain.UserWithTokens,
+	client dto.Client,
+) error {
+	err := a.tokenStorage.SaveTokenFamily(
+		ctx, usrWithTokens.FamilyID, usrWithTokens.RefreshTokenID, a.cfg.RefreshTokenTtl,
+	)
+	if err != nil {
+		a.log.Error("failed to save token family", "err", err.Error())
+		return err
+	}
+	now := time.Now()
+	err = a.tokenStorage.SaveSession(ctx, domain.Session{
+		ID:          usrWithTokens.FamilyID,
+		UserID:      usrWithTokens.User.ID,
+		UserAgent:   client.UserAgent,
+		IP:          client.IP,
+		CreatedAt:   now,
+		RefreshedAt: now,
+	}, a.cfg.RefreshTokenTtl)
+	if err != nil {
+		a.log.Error("failed to save session", "err", err.Error())
+		return err
+	}
+	return nil
+}
+
 // Register registers new users.
 func (a *Auth) Register(
 	ctx context.Context,
@@ -249,6 +580,7 @@ func (a *Auth) Register(
 		slog.String("user-id", "user-id"),
 	)
 	log.Info("registering user")
+	familyID := uuid.NewString()
 	passHash, err := bcrypt.GenerateFromPassword(
 		[]byte(reqData.Password), bcrypt.DefaultCost,
 	)
@@ -267,8 +599,15 @@ func (a *Auth) Register(
 		// https://github.com/minio/minio-go/blob/de1893f9cd38d67564fd9d04af6fcf0ea88f9035/api.go#L646
 		log.Error("failed to save avatar", "err", err.Error())
 	}
+	// Registration message is saved with the user and published by the outbox
+	// relay, so it's not lost if broker is not available. With held
+	// registration bonus the message is saved on email verification.
+	var registrationEvent func(uuid string) (domain.OutboxMessage, error)
+	if !a.cfg.EmailVerification.HoldRegistrationBonus {
+		registrationEvent = a.registrationEvent
+	}
 	// TODO: move to dto and need to add name
-	uuid, err := a.userStorage.SaveUser(ctx, reqData.Email, passHash)
+	uuid, err := a.userStorage.SaveUser(ctx, reqData.Email, passHash, registrationEvent)
 	if err != nil {
 		// send span to jaeger
 		span.SetStatus(codes.Error, err.Error())
@@ -285,49 +624,31 @@ func (a *Auth) Register(
 	}
 	span.AddEvent("user registered", trace.WithAttributes(attribute.String("user-id", uuid)))
 	log.Info("user registered")
-	registrationMsg := registrationv1.RegistrationMessage{
-		Uuid: uuid,
-		Type: RegistrationType,
-	}
-
-	err = a.producer.Send(ctx, &registrationMsg, a.cfg.Kafka.Topic, uuid)
+	err = a.sendEmailVerification(ctx, uuid, reqData.Email)
 	if err != nil {
-		// TODO: determine the err can be faced
-		// No return here with err!!!, we do continue working (so-called soft degradation)
-		// even kafka does not work, server is still able to process users.
-		span.SetStatus(codes.Error, err.Error())
-		span.SetAttributes(attribute.Bool("error", true))
-		span.RecordError(fmt.Errorf("sending message to broker failed %w", err))
-		log.Error("sending message to broker failed", "err", err.Error())
-		err = a.userStorage.UpdateSendStatus(
-			ctx, uuid, "failed",
-		)
-		if err != nil {
-			span.SetStatus(codes.Error, err.Error())
-			span.SetAttributes(attribute.Bool("error", true))
-			span.RecordError(
-				fmt.Errorf(
-					"failed to update message status with uuid %v: %w",
-					uuid, err,
-				),
-			)
-			log.Error(
-				"failed to update message status",
-				"err", err.Error(),
-				"uuid", uuid,
-			)
-		}
+		// No return here, registration works even if broker is not available
+		// (so-called soft degradation), verification email can be requested
+		// again with ResendEmailVerification.
+		span.RecordError(fmt.Errorf("sending email verification failed %w", err))
+		log.Error("sending email verification failed", "err", err.Error())
+	}
+	if !a.cfg.EmailVerification.AllowUnverifiedLogin {
+		// Tokens are issued on login after email is verified.
+		return ctx, &domain.UserWithTokens{
+			User: domain.User{ID: uuid, Email: reqData.Email},
+		}, nil
 	}
-	span.AddEvent(
-		"message to broker was sent successfully",
-		trace.WithAttributes(attribute.String("user-id", uuid)),
+	ctx, usrWithTokens, err := a.generateRefreshAccessToken(
+		ctx, reqData.Email, familyID, []string{domain.AuthMethodPassword},
 	)
-	ctx, usrWithTokens, err := a.generateRefreshAccessToken(ctx, reqData.Email)
 	if err != nil {
 		a.log.Error("failed to generate tokens", "err", err.Error())
 		return ctx, nil, err
 	}
 	usrWithTokens.ID = uuid
+	if err = a.startSession(ctx, usrWithTokens, reqData.Client); err != nil {
+		return ctx, nil, err
+	}
 	return ctx, usrWithTokens, nil
 }
 
@@ -407,11 +728,403 @@ func (a *Auth) Validate(
 	return true, nil
 }
 
+// JWKS returns public keys to verify tokens without calling sso.
+func (a *Auth) JWKS(ctx context.Context) []domain.PublicKey {
+	_, span := tracer.Start(ctx, "service layer: JWKS",
+		trace.WithAttributes(attribute.String("handler", "JWKS")))
+	defer span.End()
+	return a.keyring.PublicKeys()
+}
+
+// RotateSigningKey promotes a new signing key. The previous key is still
+// accepted to verify tokens until the grace period ends, then it is removed.
+func (a *Auth) RotateSigningKey(
+	ctx context.Context,
+	token string,
+	reqData *dto.RotateSigningKey,
+) (*domain.KeyRotation, error) {
+	const op = "SERVICE LAYER: auth_service.RotateSigningKey"
+
+	ctx, span := tracer.Start(ctx, "service layer: RotateSigningKey",
+		trace.WithAttributes(attribute.String("handler", "RotateSigningKey")))
+	defer span.End()
+
+	log := a.log.With(
+		slog.String("trace-id", "trace-id"),
+		slog.String("user-id", "user-id"),
+	)
+	ctx, err := a.authorize(ctx, token, domain.PermissionKeysRotate)
+	if err != nil {
+		log.Warn("signing key rotation is not allowed", "err", err.Error())
+		return nil, err
+	}
+
+	previous := a.keyring.Active()
+	algorithm := reqData.Algorithm
+	if algorithm == "" {
+		algorithm = previous.Method.Alg()
+	}
+	log.Info("generating new signing key", "algorithm", algorithm)
+	next, err := jwtlib.GenerateSigningKey(algorithm)
+	if err != nil {
+		log.Error("failed to generate signing key", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	active, err := next.Export()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	retiring, err := previous.Export()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	retireAt := time.Now().Add(a.cfg.JWT.RotationGracePeriod)
+	retiring.ExpiresAt = retireAt
+
+	err = a.keyStorage.RotateSigningKey(ctx, active, retiring)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+		log.Error("failed to save signing key", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	a.keyring.Promote(next, retireAt)
+	log.Info("signing key rotated", "active", next.ID, "retiring", previous.ID)
+	return &domain.KeyRotation{
+		ActiveKeyID:   next.ID,
+		RetiringKeyID: previous.ID,
+		RetireAt:      retireAt,
+	}, nil
+}
+
+// reloadSigningKeys loads keys rotated by other instances and removes expired ones.
+func (a *Auth) reloadSigningKeys(ctx context.Context) {
+	err := a.keyStorage.DeleteExpiredSigningKeys(ctx)
+	if err != nil {
+		a.log.Error("failed to delete expired signing keys", "err", err.Error())
+	}
+	keys, err := a.keyStorage.GetSigningKeys(ctx)
+	if err != nil {
+		a.log.Error("failed to get signing keys", "err", err.Error())
+		return
+	}
+	if err = a.keyring.Load(keys); err != nil {
+		a.log.Error("failed to load signing keys", "err", err.Error())
+	}
+}
+
+// reloadUnknownSigningKey loads keys when token is signed by unknown key.
+// Loads are limited by jwt.unknownKeyReloadInterval, so tokens with made up
+// "kid" don't flood storage.
+func (a *Auth) reloadUnknownSigningKey(ctx context.Context) {
+	a.keysReloadMu.Lock()
+	defer a.keysReloadMu.Unlock()
+	if time.Since(a.keysReloadedAt) < a.cfg.JWT.UnknownKeyReloadInterval {
+		return
+	}
+	a.keysReloadedAt = time.Now()
+	keys, err := a.keyStorage.GetSigningKeys(ctx)
+	if err != nil {
+		a.log.Error("failed to get signing keys", "err", err.Error())
+		return
+	}
+	if err = a.keyring.Load(keys); err != nil {
+		a.log.Error("failed to load signing keys", "err", err.Error())
+	}
+}
+
+// GrantRole assigns role to user. Already issued tokens keep previous
+// permissions until they expire.
+func (a *Auth) GrantRole(
+	ctx context.Context,
+	token string,
+	reqData *dto.UserRole,
+) error {
+	const op = "SERVICE LAYER: auth_service.GrantRole"
+
+	ctx, span := tracer.Start(ctx, "service layer: GrantRole",
+		trace.WithAttributes(attribute.String("handler", "GrantRole")))
+	defer span.End()
+
+	log := a.log.With(
+		slog.String("trace-id", "trace-id"),
+		slog.String("user-id", reqData.UserID),
+	)
+	ctx, err := a.authorize(ctx, token, domain.PermissionRolesManage)
+	if err != nil {
+		log.Warn("granting role is not allowed", "err", err.Error())
+		return err
+	}
+	log.Info("granting role", "role", reqData.Role)
+	event, err := a.userEvent(reqData.UserID, roleChangedEvent(reqData.Role, true))
+	if err != nil {
+		log.Error("failed to create user event", "err", err.Error())
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	err = a.userStorage.GrantRole(ctx, reqData.UserID, reqData.Role, event)
+	if err != nil {
+		log.Error("failed to grant role", "err", err.Error())
+		switch {
+		case errors.Is(err, storage.ErrUserNotFound):
+			return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		case errors.Is(err, storage.ErrRoleNotFound):
+			return fmt.Errorf("%s: %w", op, ErrRoleNotFound)
+		}
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("role granted", "role", reqData.Role)
+	return nil
+}
+
+// RevokeRole removes role from user. Already issued tokens keep previous
+// permissions until they expire.
+func (a *Auth) RevokeRole(
+	ctx context.Context,
+	token string,
+	reqData *dto.UserRole,
+) error {
+	const op = "SERVICE LAYER: auth_service.RevokeRole"
+
+	ctx, span := tracer.Start(ctx, "service layer: RevokeRole",
+		trace.WithAttributes(attribute.String("handler", "RevokeRole")))
+	defer span.End()
+
+	log := a.log.With(
+		slog.String("trace-id", "trace-id"),
+		slog.String("user-id", reqData.UserID),
+	)
+	ctx, err := a.authorize(ctx, token, domain.PermissionRolesManage)
+	if err != nil {
+		log.Warn("revoking role is not allowed", "err", err.Error())
+		return err
+	}
+	log.Info("revoking role", "role", reqData.Role)
+	event, err := a.userEvent(reqData.UserID, roleChangedEvent(reqData.Role, false))
+	if err != nil {
+		log.Error("failed to create user event", "err", err.Error())
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	err = a.userStorage.RevokeRole(ctx, reqData.UserID, reqData.Role, event)
+	if err != nil {
+		log.Error("failed to revoke role", "err", err.Error())
+		if errors.Is(err, storage.ErrRoleNotGranted) {
+			return fmt.Errorf("%s: %w", op, ErrRoleNotGranted)
+		}
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("role revoked", "role", reqData.Role)
+	return nil
+}
+
+// ListSessions returns sessions of the token owner. Sessions of other users
+// can be listed with sso:sessions:manage permission.
+func (a *Auth) ListSessions(
+	ctx context.Context,
+	token string,
+	userID string,
+) ([]domain.Session, error) {
+	const op = "SERVICE LAYER: auth_service.ListSessions"
+
+	ctx, span := tracer.Start(ctx, "service layer: ListSessions",
+		trace.WithAttributes(attribute.String("handler", "ListSessions")))
+	defer span.End()
+
+	ctx, userID, currentID, err := a.authorizeSessions(ctx, token, userID)
+	if err != nil {
+		a.log.Warn("listing sessions is not allowed", "err", err.Error())
+		return nil, err
+	}
+	sessions, err := a.tokenStorage.GetSessions(ctx, userID)
+	if err != nil {
+		a.log.Error("failed to get sessions", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentID
+	}
+	return sessions, nil
+}
+
+// RevokeSession revokes all tokens of the session.
+func (a *Auth) RevokeSession(
+	ctx context.Context,
+	token string,
+	reqData *dto.RevokeSession,
+) error {
+	const op = "SERVICE LAYER: auth_service.RevokeSession"
+
+	ctx, span := tracer.Start(ctx, "service layer: RevokeSession",
+		trace.WithAttributes(attribute.String("handler", "RevokeSession")))
+	defer span.End()
+
+	log := a.log.With(
+		slog.String("trace-id", "trace-id"),
+		slog.String("session-id", reqData.SessionID),
+	)
+	ctx, userID, _, err := a.authorizeSessions(ctx, token, reqData.UserID)
+	if err != nil {
+		log.Warn("revoking session is not allowed", "err", err.Error())
+		return err
+	}
+	err = a.tokenStorage.DeleteSession(ctx, userID, reqData.SessionID)
+	if err != nil {
+		if errors.Is(err, storage.ErrSessionNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrSessionNotFound)
+		}
+		log.Error("failed to delete session", "err", err.Error())
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	err = a.tokenStorage.RevokeTokenFamily(ctx, reqData.SessionID, a.cfg.RefreshTokenTtl)
+	if err != nil {
+		log.Error("failed to revoke token family", "err", err.Error())
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("session revoked")
+	return nil
+}
+
+// RevokeAllSessions revokes tokens of all user sessions, including the
+// session of the token used in request.
+func (a *Auth) RevokeAllSessions(
+	ctx context.Context,
+	token string,
+	reqData *dto.RevokeAllSessions,
+) (int, error) {
+	const op = "SERVICE LAYER: auth_service.RevokeAllSessions"
+
+	ctx, span := tracer.Start(ctx, "service layer: RevokeAllSessions",
+		trace.WithAttributes(attribute.String("handler", "RevokeAllSessions")))
+	defer span.End()
+
+	ctx, userID, _, err := a.authorizeSessions(ctx, token, reqData.UserID)
+	if err != nil {
+		a.log.Warn("revoking sessions is not allowed", "err", err.Error())
+		return 0, err
+	}
+	revoked, err := a.revokeSessions(ctx, userID)
+	if err != nil {
+		return revoked, fmt.Errorf("%s: %w", op, err)
+	}
+	return revoked, nil
+}
+
+// revokeSessions revokes token families of all user sessions and returns
+// number of revoked sessions.
+func (a *Auth) revokeSessions(ctx context.Context, userID string) (int, error) {
+	log := a.log.With(
+		slog.String("trace-id", "trace-id"),
+		slog.String("user-id", userID),
+	)
+	sessions, err := a.tokenStorage.GetSessions(ctx, userID)
+	if err != nil {
+		log.Error("failed to get sessions", "err", err.Error())
+		return 0, err
+	}
+	revoked := 0
+	for _, session := range sessions {
+		err = a.tokenStorage.RevokeTokenFamily(ctx, session.ID, a.cfg.RefreshTokenTtl)
+		if err != nil {
+			log.Error("failed to revoke token family", "err", err.Error())
+			return revoked, err
+		}
+		err = a.tokenStorage.DeleteSession(ctx, userID, session.ID)
+		if err != nil && !errors.Is(err, storage.ErrSessionNotFound) {
+			log.Error("failed to delete session", "err", err.Error())
+			return revoked, err
+		}
+		revoked++
+	}
+	log.Info("all sessions revoked", "revoked", revoked)
+	return revoked, nil
+}
+
+// authorizeSessions returns id of the user whose sessions are managed and id
+// of the session the token belongs to. Managing sessions of other users
+// requires sso:sessions:manage permission.
+func (a *Auth) authorizeSessions(
+	ctx context.Context,
+	token string,
+	userID string,
+) (context.Context, string, string, error) {
+	ctx, claims, err := a.validateToken(ctx, token)
+	if err != nil {
+		return ctx, "", "", err
+	}
+	if claims["token_type"] != "access" {
+		return ctx, "", "", ErrTokenWrongType
+	}
+	uid, ok := claims["uid"].(string)
+	if !ok {
+		return ctx, "", "", ErrInvalidCredentials
+	}
+	currentID, _ := claims["fid"].(string)
+	if userID == "" || userID == uid {
+		return ctx, uid, currentID, nil
+	}
+	ctx, err = a.authorize(ctx, token, domain.PermissionSessionsManage)
+	if err != nil {
+		return ctx, "", "", err
+	}
+	return ctx, userID, currentID, nil
+}
+
+// authorize checks access token owner is granted the permission. Permissions
+// are loaded from storage, so revoked roles take effect immediately in sso.
+func (a *Auth) authorize(
+	ctx context.Context,
+	token string,
+	permission string,
+) (context.Context, error) {
+	ctx, claims, err := a.validateToken(ctx, token)
+	if err != nil {
+		return ctx, err
+	}
+	if claims["token_type"] != "access" {
+		return ctx, ErrTokenWrongType
+	}
+	uuid, ok := claims["uid"].(string)
+	if !ok {
+		return ctx, ErrInvalidCredentials
+	}
+	user, err := a.userStorage.GetUser(ctx, uuid)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return ctx, ErrUserNotFound
+		}
+		return ctx, err
+	}
+	if !user.HasPermission(permission) {
+		return ctx, ErrPermissionDenied
+	}
+	// permissions checked here are granted to admins only
+	if a.cfg.MFA.RequiredForAdmins && !slices.Contains(authMethods(claims), domain.AuthMethodOTP) {
+		return ctx, ErrMFARequired
+	}
+	return ctx, nil
+}
+
 func (a *Auth) validateToken(ctx context.Context, token string) (context.Context, jwt.MapClaims, error) {
 	tokenParsed, err := jwt.Parse(token, func(token *jwt.Token) (any, error) {
-		return []byte(a.cfg.ServiceSecret), nil
+		kid, _ := token.Header["kid"].(string)
+		signingKey, err := a.keyring.Get(kid)
+		if errors.Is(err, jwtlib.ErrKeyNotFound) {
+			// key might be rotated by other instance after the last reload
+			a.reloadUnknownSigningKey(ctx)
+			signingKey, err = a.keyring.Get(kid)
+		}
+		if err != nil {
+			return nil, err
+		}
+		// prevents algorithm substitution, i.e. "none" or HS256 signed with public key
+		if token.Method.Alg() != signingKey.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
+		return signingKey.PrivateKey.Public(), nil
 	})
-	fmt.Println("111111111", tokenParsed, err)
 	if err != nil {
 		return ctx, jwt.MapClaims{}, ErrTokenParsing
 	}
@@ -437,12 +1150,26 @@ func (a *Auth) validateToken(ctx context.Context, token string) (context.Context
 	if value == TokenRevoked {
 		return ctx, jwt.MapClaims{}, ErrTokenRevoked
 	}
+	// check if token family was revoked after refresh token reuse
+	if familyID, ok := claims["fid"].(string); ok && familyID != "" {
+		revoked, err := a.tokenStorage.CheckTokenFamilyRevoked(ctx, familyID)
+		if err != nil {
+			return ctx, jwt.MapClaims{}, fmt.Errorf("validateToken: %w", err)
+		}
+		if revoked {
+			return ctx, jwt.MapClaims{}, ErrTokenRevoked
+		}
+	}
 	return ctx, claims, nil
 }
 
+// generateRefreshAccessToken issues tokens of the family. Caller is responsible
+// for saving refresh token id as the last one issued in the family.
 func (a *Auth) generateRefreshAccessToken(
 	ctx context.Context,
 	email string,
+	familyID string,
+	authMethods []string,
 ) (context.Context, *domain.UserWithTokens, error) {
 
 	user, err := a.userStorage.GetUserByEmail(ctx, email)
@@ -453,15 +1180,42 @@ func (a *Auth) generateRefreshAccessToken(
 		return ctx, nil, err
 	}
 
-	accessToken, err := jwtlib.NewToken(user, a.cfg, "access")
+	accessToken, err := jwtlib.NewToken(
+		user, a.cfg, "access", a.keyring.Active(), familyID, uuid.NewString(), authMethods,
+	)
 	if err != nil {
 		return ctx, nil, fmt.Errorf("accessToken generation failed: %w", err)
 	}
-	refreshToken, err := jwtlib.NewToken(user, a.cfg, "refresh")
+	refreshTokenID := uuid.NewString()
+	refreshToken, err := jwtlib.NewToken(
+		user, a.cfg, "refresh", a.keyring.Active(), familyID, refreshTokenID, authMethods,
+	)
 	if err != nil {
 		return ctx, nil, fmt.Errorf("refreshToken generation failed: %w", err)
 	}
-	return ctx, &domain.UserWithTokens{User: user, AccessToken: accessToken, RefreshToken: refreshToken}, nil
+	return ctx, &domain.UserWithTokens{
+		User:           user,
+		AccessToken:    accessToken,
+		RefreshToken:   refreshToken,
+		FamilyID:       familyID,
+		RefreshTokenID: refreshTokenID,
+	}, nil
+}
+
+// authMethods returns "amr" claim of the token, tokens issued before the claim
+// was introduced were issued on password login.
+func authMethods(claims jwt.MapClaims) []string {
+	amr, ok := claims["amr"].([]any)
+	if !ok {
+		return []string{domain.AuthMethodPassword}
+	}
+	methods := make([]string, 0, len(amr))
+	for _, method := range amr {
+		if m, ok := method.(string); ok {
+			methods = append(methods, m)
+		}
+	}
+	return methods
 }
 
 // Info provides info about new users.
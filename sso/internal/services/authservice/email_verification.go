@@ -0,0 +1,163 @@
+package authservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	notificationv1 "github.com/AlexBlackNn/authloyalty/commands/proto/notification.v1/notification.v1"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/dto"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/storage"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// VerifyEmail marks user email as verified using email verification token.
+// If registration bonus is held until verification, registration message is
+// saved to outbox here instead of registration, only when email becomes
+// verified. Email verified again after ChangeEmail saves the message with the
+// same event id, so loyalty applies registration bonus once.
+func (a *Auth) VerifyEmail(
+	ctx context.Context,
+	reqData *dto.VerifyEmail,
+) (context.Context, error) {
+	const op = "SERVICE LAYER: auth_service.VerifyEmail"
+
+	ctx, span := tracer.Start(ctx, "service layer: VerifyEmail",
+		trace.WithAttributes(attribute.String("handler", "VerifyEmail")))
+	defer span.End()
+
+	log := a.log.With(
+		slog.String("trace-id", "trace-id"),
+		slog.String("user-id", "user-id"),
+	)
+	log.Info("verifying email")
+
+	userID, err := a.tokenStorage.ConsumeEmailVerificationToken(
+		ctx, hashOneTimeToken(reqData.Token),
+	)
+	if err != nil {
+		if errors.Is(err, storage.ErrOneTimeTokenNotFound) {
+			log.Warn("invalid email verification token")
+			return ctx, fmt.Errorf("%s: %w", op, ErrVerificationTokenInvalid)
+		}
+		log.Error("failed to get email verification token", "err", err.Error())
+		return ctx, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var events []domain.OutboxMessage
+	if a.cfg.EmailVerification.HoldRegistrationBonus {
+		event, err := a.registrationEvent(userID)
+		if err != nil {
+			log.Error("failed to create registration event", "err", err.Error())
+			return ctx, fmt.Errorf("%s: %w", op, err)
+		}
+		events = append(events, event)
+	}
+	err = a.userStorage.VerifyEmail(ctx, userID, events...)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", "err", err.Error())
+			return ctx, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+		log.Error("failed to verify email", "err", err.Error())
+		return ctx, fmt.Errorf("%s: %w", op, err)
+	}
+	span.AddEvent(
+		"email verified",
+		trace.WithAttributes(attribute.String("user-id", userID)),
+	)
+	log.Info("email verified", "uuid", userID)
+	return ctx, nil
+}
+
+// ResendEmailVerification sends a new email verification token to the user.
+// Emails are sent to the same user not more often than once per
+// email_verification.resendInterval. Nothing is done for unknown and already
+// verified emails and for too frequent requests, but no error is returned to
+// not disclose registered users.
+func (a *Auth) ResendEmailVerification(
+	ctx context.Context,
+	reqData *dto.ResendEmailVerification,
+) (context.Context, error) {
+	const op = "SERVICE LAYER: auth_service.ResendEmailVerification"
+
+	ctx, span := tracer.Start(ctx, "service layer: ResendEmailVerification",
+		trace.WithAttributes(attribute.String("handler", "ResendEmailVerification")))
+	defer span.End()
+
+	log := a.log.With(
+		slog.String("trace-id", "trace-id"),
+		slog.String("user-id", "user-id"),
+	)
+	log.Info("resending email verification")
+
+	user, err := a.userStorage.GetUserByEmail(ctx, reqData.Email)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("email verification requested for unknown user")
+			return ctx, nil
+		}
+		log.Error("failed to get user", "err", err.Error())
+		return ctx, fmt.Errorf("%s: %w", op, err)
+	}
+	if user.Verified() {
+		log.Info("email is already verified", "uuid", user.ID)
+		return ctx, nil
+	}
+	locked, err := a.tokenStorage.LockEmailVerificationResend(
+		ctx, user.ID, a.cfg.EmailVerification.ResendInterval,
+	)
+	if err != nil {
+		log.Error("failed to lock email verification resend", "err", err.Error())
+		return ctx, fmt.Errorf("%s: %w", op, err)
+	}
+	if !locked {
+		log.Warn("email verification requested too often", "uuid", user.ID)
+		return ctx, nil
+	}
+	if err = a.sendEmailVerification(ctx, user.ID, user.Email); err != nil {
+		span.RecordError(fmt.Errorf("sending email verification failed %w", err))
+		log.Error("sending email verification failed", "err", err.Error())
+		return ctx, fmt.Errorf("%s: %w", op, err)
+	}
+	span.AddEvent(
+		"email verification resent",
+		trace.WithAttributes(attribute.String("user-id", user.ID)),
+	)
+	log.Info("email verification resent", "uuid", user.ID)
+	return ctx, nil
+}
+
+// sendEmailVerification issues single-use email verification token and
+// publishes it to broker, so that a mailer can deliver it to the user.
+func (a *Auth) sendEmailVerification(
+	ctx context.Context,
+	userID string,
+	email string,
+) error {
+	token, tokenHash, err := newOneTimeToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate email verification token: %w", err)
+	}
+	err = a.tokenStorage.SaveEmailVerificationToken(
+		ctx, tokenHash, userID, a.cfg.EmailVerification.TokenTtl,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save email verification token: %w", err)
+	}
+	verificationMsg := notificationv1.EmailVerificationMessage{
+		Uuid:      userID,
+		Email:     email,
+		Token:     token,
+		ExpiresAt: time.Now().Add(a.cfg.EmailVerification.TokenTtl).Unix(),
+	}
+	err = a.producer.Send(ctx, &verificationMsg, a.cfg.Kafka.EmailVerificationTopic, userID)
+	if err != nil {
+		return fmt.Errorf("sending message to broker failed: %w", err)
+	}
+	return nil
+}
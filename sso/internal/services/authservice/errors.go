@@ -3,10 +3,24 @@ package authservice
 import "errors"
 
 var (
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrUserNotFound       = errors.New("user not found")
-	ErrTokenRevoked       = errors.New("token has been revoked")
-	ErrTokenParsing       = errors.New("fail to parse token")
-	ErrTokenTTLExpired    = errors.New("token ttl expired")
-	ErrTokenWrongType     = errors.New("token wrong type")
+	ErrInvalidCredentials       = errors.New("invalid credentials")
+	ErrUserNotFound             = errors.New("user not found")
+	ErrTokenRevoked             = errors.New("token has been revoked")
+	ErrTokenParsing             = errors.New("fail to parse token")
+	ErrTokenTTLExpired          = errors.New("token ttl expired")
+	ErrTokenWrongType           = errors.New("token wrong type")
+	ErrTokenReused              = errors.New("refresh token reused")
+	ErrPermissionDenied         = errors.New("permission denied")
+	ErrRoleNotFound             = errors.New("role not found")
+	ErrRoleNotGranted           = errors.New("role not granted")
+	ErrSessionNotFound          = errors.New("session not found")
+	ErrResetTokenInvalid        = errors.New("password reset token is invalid or expired")
+	ErrEmailNotVerified         = errors.New("email not verified")
+	ErrVerificationTokenInvalid = errors.New("email verification token is invalid or expired")
+	ErrMFARequired              = errors.New("mfa required")
+	ErrMFAEnabled               = errors.New("mfa already enabled")
+	ErrMFANotEnrolled           = errors.New("mfa not enrolled")
+	ErrMFAInvalid               = errors.New("mfa challenge or code is invalid")
+	ErrLoginLocked              = errors.New("login temporarily locked")
+	ErrUserBlocked              = errors.New("user is blocked")
 )
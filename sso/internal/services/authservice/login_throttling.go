@@ -0,0 +1,164 @@
+package authservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/sso/internal/config"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/dto"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/storage"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LoginLockedError is returned while login is locked after failed attempts.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrLoginLocked, e.RetryAfter)
+}
+
+func (e *LoginLockedError) Unwrap() error {
+	return ErrLoginLocked
+}
+
+// loginSubject is an account or IP failed login attempts are counted for.
+type loginSubject struct {
+	key             string
+	freeAttempts    int64
+	lockoutAttempts int64
+}
+
+func (a *Auth) loginSubjects(email string, ip string) []loginSubject {
+	subjects := []loginSubject{{
+		key:             accountSubject(email),
+		freeAttempts:    a.cfg.LoginThrottling.FreeAttempts,
+		lockoutAttempts: a.cfg.LoginThrottling.LockoutAttempts,
+	}}
+	if ip != "" {
+		subjects = append(subjects, loginSubject{
+			key:             ipSubject(ip),
+			freeAttempts:    a.cfg.LoginThrottling.IPFreeAttempts,
+			lockoutAttempts: a.cfg.LoginThrottling.IPLockoutAttempts,
+		})
+	}
+	return subjects
+}
+
+// Unknown emails are counted too, so lockout doesn't disclose registered users.
+func accountSubject(email string) string {
+	return "account:" + strings.ToLower(email)
+}
+
+func ipSubject(ip string) string {
+	return "ip:" + ip
+}
+
+// checkLoginLock returns LoginLockedError if account or IP is locked. Login is
+// allowed if storage is not available (soft degradation).
+func (a *Auth) checkLoginLock(ctx context.Context, reqData *dto.Login) error {
+	var retryAfter time.Duration
+	for _, subject := range a.loginSubjects(reqData.Email, reqData.IP) {
+		ttl, err := a.tokenStorage.LoginLockTTL(ctx, subject.key)
+		if err != nil {
+			a.log.Error("failed to check login lock", "err", err.Error())
+			continue
+		}
+		retryAfter = max(retryAfter, ttl)
+	}
+	if retryAfter > 0 {
+		return &LoginLockedError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// registerLoginFailure counts failed attempt and locks login with exponential
+// backoff once free attempts are exhausted.
+func (a *Auth) registerLoginFailure(ctx context.Context, reqData *dto.Login) {
+	for _, subject := range a.loginSubjects(reqData.Email, reqData.IP) {
+		failures, err := a.tokenStorage.AddLoginFailure(
+			ctx, subject.key, a.cfg.LoginThrottling.Window,
+		)
+		if err != nil {
+			a.log.Error("failed to count failed login", "err", err.Error())
+			continue
+		}
+		delay := loginDelay(a.cfg.LoginThrottling, failures, subject.freeAttempts, subject.lockoutAttempts)
+		if delay == 0 {
+			continue
+		}
+		a.log.Warn("login locked", "subject", subject.key, "failures", failures, "delay", delay)
+		if err = a.tokenStorage.LockLogin(ctx, subject.key, delay); err != nil {
+			a.log.Error("failed to lock login", "err", err.Error())
+		}
+	}
+}
+
+// loginDelay returns how long login is locked after the number of failures.
+func loginDelay(cfg config.LoginThrottlingConfig, failures, freeAttempts, lockoutAttempts int64) time.Duration {
+	if failures >= lockoutAttempts {
+		return cfg.LockoutDuration
+	}
+	if failures <= freeAttempts {
+		return 0
+	}
+	delay := cfg.BaseDelay
+	for i := freeAttempts + 1; i < failures && delay < cfg.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, cfg.MaxDelay)
+}
+
+// UnlockLogin removes failed login attempts and lockout of the user account
+// and/or IP.
+func (a *Auth) UnlockLogin(
+	ctx context.Context,
+	token string,
+	reqData *dto.UnlockLogin,
+) error {
+	const op = "SERVICE LAYER: auth_service.UnlockLogin"
+
+	ctx, span := tracer.Start(ctx, "service layer: UnlockLogin",
+		trace.WithAttributes(attribute.String("handler", "UnlockLogin")))
+	defer span.End()
+
+	ctx, err := a.authorize(ctx, token, domain.PermissionUsersUnlock)
+	if err != nil {
+		a.log.Warn("unlocking login is not allowed", "err", err.Error())
+		return err
+	}
+	log := a.log.With(
+		slog.String("trace-id", "trace-id"),
+		slog.String("user-id", reqData.UserID),
+	)
+	if reqData.UserID != "" {
+		user, err := a.userStorage.GetUser(ctx, reqData.UserID)
+		if err != nil {
+			if errors.Is(err, storage.ErrUserNotFound) {
+				return ErrUserNotFound
+			}
+			log.Error("failed to get user", "err", err.Error())
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if err = a.tokenStorage.ResetLoginFailures(ctx, accountSubject(user.Email)); err != nil {
+			log.Error("failed to unlock account", "err", err.Error())
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		log.Info("account login unlocked")
+	}
+	if reqData.IP != "" {
+		if err = a.tokenStorage.ResetLoginFailures(ctx, ipSubject(reqData.IP)); err != nil {
+			log.Error("failed to unlock ip", "err", err.Error())
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		log.Info("ip login unlocked", "ip", reqData.IP)
+	}
+	return nil
+}
@@ -0,0 +1,294 @@
+package authservice
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/dto"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/lib/totp"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/storage"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recoveryCodeSize is a number of random bytes in a recovery code.
+const recoveryCodeSize = 10
+
+// EnrollMFA generates TOTP secret for the access token owner. MFA is enabled
+// only after the secret is confirmed with a code from authenticator app.
+func (a *Auth) EnrollMFA(
+	ctx context.Context,
+	token string,
+) (*domain.MFAEnrollment, error) {
+	const op = "SERVICE LAYER: auth_service.EnrollMFA"
+
+	ctx, span := tracer.Start(ctx, "service layer: EnrollMFA",
+		trace.WithAttributes(attribute.String("handler", "EnrollMFA")))
+	defer span.End()
+
+	ctx, user, err := a.accessTokenOwner(ctx, token)
+	if err != nil {
+		a.log.Warn("mfa enrollment is not allowed", "err", err.Error())
+		return nil, err
+	}
+	log := a.log.With(
+		slog.String("trace-id", "trace-id"),
+		slog.String("user-id", user.ID),
+	)
+	if user.MFAEnabled {
+		log.Warn("mfa already enabled")
+		return nil, ErrMFAEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		log.Error("failed to generate totp secret", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	err = a.userStorage.SaveMFASecret(ctx, user.ID, secret)
+	if err != nil {
+		if errors.Is(err, storage.ErrMFAEnabled) {
+			return nil, ErrMFAEnabled
+		}
+		log.Error("failed to save totp secret", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("mfa enrollment started")
+	return &domain.MFAEnrollment{
+		Secret: secret,
+		URI:    totp.URI(a.cfg.MFA.Issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmMFA enables MFA if code matches the enrolled secret and returns
+// one-time recovery codes. Recovery codes are shown only once.
+func (a *Auth) ConfirmMFA(
+	ctx context.Context,
+	token string,
+	reqData *dto.ConfirmMFA,
+) ([]string, error) {
+	const op = "SERVICE LAYER: auth_service.ConfirmMFA"
+
+	ctx, span := tracer.Start(ctx, "service layer: ConfirmMFA",
+		trace.WithAttributes(attribute.String("handler", "ConfirmMFA")))
+	defer span.End()
+
+	ctx, user, err := a.accessTokenOwner(ctx, token)
+	if err != nil {
+		a.log.Warn("mfa confirmation is not allowed", "err", err.Error())
+		return nil, err
+	}
+	log := a.log.With(
+		slog.String("trace-id", "trace-id"),
+		slog.String("user-id", user.ID),
+	)
+	if user.MFAEnabled {
+		log.Warn("mfa already enabled")
+		return nil, ErrMFAEnabled
+	}
+	secret, err := a.userStorage.GetMFASecret(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, storage.ErrMFANotEnrolled) {
+			return nil, ErrMFANotEnrolled
+		}
+		log.Error("failed to get totp secret", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err = a.useTOTP(ctx, user.ID, secret, reqData.Code); err != nil {
+		if errors.Is(err, ErrMFAInvalid) {
+			log.Warn("invalid totp code")
+			return nil, err
+		}
+		log.Error("failed to use totp code", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	codes := make([]string, a.cfg.MFA.RecoveryCodes)
+	hashes := make([]string, a.cfg.MFA.RecoveryCodes)
+	for i := range codes {
+		codes[i], err = newRecoveryCode()
+		if err != nil {
+			log.Error("failed to generate recovery code", "err", err.Error())
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	err = a.userStorage.EnableMFA(ctx, user.ID, hashes)
+	if err != nil {
+		if errors.Is(err, storage.ErrMFAEnabled) {
+			return nil, ErrMFAEnabled
+		}
+		log.Error("failed to enable mfa", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	span.AddEvent("mfa enabled", trace.WithAttributes(attribute.String("user-id", user.ID)))
+	log.Info("mfa enabled")
+	return codes, nil
+}
+
+// VerifyMFA completes login started with Login using TOTP or recovery code.
+// The challenge token is single-use, so a failed attempt requires new login.
+func (a *Auth) VerifyMFA(
+	ctx context.Context,
+	reqData *dto.VerifyMFA,
+) (*domain.UserWithTokens, error) {
+	const op = "SERVICE LAYER: auth_service.VerifyMFA"
+
+	ctx, span := tracer.Start(ctx, "service layer: VerifyMFA",
+		trace.WithAttributes(attribute.String("handler", "VerifyMFA")))
+	defer span.End()
+
+	log := a.log.With(
+		slog.String("trace-id", "trace-id"),
+		slog.String("user-id", "user-id"),
+	)
+	log.Info("verifying mfa")
+
+	userID, err := a.tokenStorage.ConsumeMFAChallenge(ctx, hashOneTimeToken(reqData.MFAToken))
+	if err != nil {
+		if errors.Is(err, storage.ErrOneTimeTokenNotFound) {
+			log.Warn("invalid mfa challenge")
+			return nil, ErrMFAInvalid
+		}
+		log.Error("failed to get mfa challenge", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	user, err := a.userStorage.GetUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		log.Error("failed to get user", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if user.Blocked {
+		log.Warn("user is blocked", "uuid", userID)
+		return nil, ErrUserBlocked
+	}
+
+	switch {
+	case reqData.Code != "":
+		secret, err := a.userStorage.GetMFASecret(ctx, userID)
+		if err != nil {
+			log.Error("failed to get totp secret", "err", err.Error())
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if err = a.useTOTP(ctx, userID, secret, reqData.Code); err != nil {
+			if errors.Is(err, ErrMFAInvalid) {
+				log.Warn("invalid totp code", "uuid", userID)
+				return nil, err
+			}
+			log.Error("failed to use totp code", "err", err.Error())
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	case reqData.RecoveryCode != "":
+		err = a.userStorage.UseRecoveryCode(ctx, userID, hashRecoveryCode(reqData.RecoveryCode))
+		if err != nil {
+			if errors.Is(err, storage.ErrRecoveryCodeNotFound) {
+				log.Warn("invalid recovery code", "uuid", userID)
+				return nil, ErrMFAInvalid
+			}
+			log.Error("failed to use recovery code", "err", err.Error())
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		log.Info("recovery code used", "uuid", userID)
+	default:
+		return nil, ErrMFAInvalid
+	}
+
+	ctx, usrWithTokens, err := a.generateRefreshAccessToken(
+		ctx, user.Email, uuid.NewString(),
+		[]string{domain.AuthMethodPassword, domain.AuthMethodOTP},
+	)
+	if err != nil {
+		log.Error("failed to generate tokens", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err = a.startSession(ctx, usrWithTokens, reqData.Client); err != nil {
+		return nil, err
+	}
+	span.AddEvent("mfa verified", trace.WithAttributes(attribute.String("user-id", userID)))
+	return usrWithTokens, nil
+}
+
+// useTOTP accepts TOTP code of the user once. Invalid code and code of a time
+// step not later than the last accepted one return ErrMFAInvalid, so an
+// intercepted code can't be replayed while it's still valid.
+func (a *Auth) useTOTP(ctx context.Context, userID string, secret string, code string) error {
+	step, ok := totp.Validate(secret, code, time.Now())
+	if !ok {
+		return ErrMFAInvalid
+	}
+	if err := a.userStorage.UseTOTPStep(ctx, userID, step); err != nil {
+		if errors.Is(err, storage.ErrTOTPStepUsed) {
+			return ErrMFAInvalid
+		}
+		return err
+	}
+	return nil
+}
+
+// issueMFAChallenge returns single-use token to complete login with VerifyMFA.
+func (a *Auth) issueMFAChallenge(ctx context.Context, userID string) (string, error) {
+	token, tokenHash, err := newOneTimeToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate mfa challenge: %w", err)
+	}
+	err = a.tokenStorage.SaveMFAChallenge(ctx, tokenHash, userID, a.cfg.MFA.ChallengeTokenTtl)
+	if err != nil {
+		a.log.Error("failed to save mfa challenge", "err", err.Error())
+		return "", fmt.Errorf("failed to save mfa challenge: %w", err)
+	}
+	return token, nil
+}
+
+// accessTokenOwner returns user the access token is issued to.
+func (a *Auth) accessTokenOwner(
+	ctx context.Context,
+	token string,
+) (context.Context, domain.User, error) {
+	ctx, claims, err := a.validateToken(ctx, token)
+	if err != nil {
+		return ctx, domain.User{}, err
+	}
+	if claims["token_type"] != "access" {
+		return ctx, domain.User{}, ErrTokenWrongType
+	}
+	uid, ok := claims["uid"].(string)
+	if !ok {
+		return ctx, domain.User{}, ErrInvalidCredentials
+	}
+	user, err := a.userStorage.GetUser(ctx, uid)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return ctx, domain.User{}, ErrUserNotFound
+		}
+		return ctx, domain.User{}, err
+	}
+	return ctx, user, nil
+}
+
+// newRecoveryCode returns random code formatted as xxxx-xxxx-xxxx-xxxx.
+func newRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(base32.StdEncoding.EncodeToString(b))
+	return code[0:4] + "-" + code[4:8] + "-" + code[8:12] + "-" + code[12:16], nil
+}
+
+// hashRecoveryCode hashes recovery code ignoring case and separators users
+// might type differently.
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return hashOneTimeToken(normalized)
+}
@@ -0,0 +1,26 @@
+package authservice
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// oneTimeTokenSize is a number of random bytes in tokens sent to user email.
+const oneTimeTokenSize = 32
+
+// newOneTimeToken returns token sent to the user and its hash kept in storage.
+func newOneTimeToken() (string, string, error) {
+	b := make([]byte, oneTimeTokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, hashOneTimeToken(token), nil
+}
+
+func hashOneTimeToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
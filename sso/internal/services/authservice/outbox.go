@@ -0,0 +1,44 @@
+package authservice
+
+import (
+	"fmt"
+
+	registrationv1 "github.com/AlexBlackNn/authloyalty/commands/proto/registration.v1/registration.v1"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// RegistrationSchemaVersion is version of registration message fields
+// populated by sso.
+const RegistrationSchemaVersion = 2
+
+// newOutboxMessage serializes message to be saved to outbox and published
+// by the outbox relay.
+func newOutboxMessage(topic string, key string, msg proto.Message) (domain.OutboxMessage, error) {
+	payload, err := proto.Marshal(msg)
+	if err != nil {
+		return domain.OutboxMessage{}, fmt.Errorf("failed to marshal outbox message: %w", err)
+	}
+	return domain.OutboxMessage{
+		Topic:   topic,
+		Key:     key,
+		Type:    string(msg.ProtoReflect().Descriptor().FullName()),
+		Payload: payload,
+	}, nil
+}
+
+// registrationEvent returns registration message for loyalty service, it
+// creates loyalty account with registration bonus. Event id depends on user
+// only, so the bonus is granted once however many times the event is re-sent.
+func (a *Auth) registrationEvent(uuid string) (domain.OutboxMessage, error) {
+	return newOutboxMessage(a.cfg.Kafka.Topic, uuid, &registrationv1.RegistrationMessage{
+		Uuid:          uuid,
+		Type:          RegistrationType,
+		EventId:       RegistrationType + "/" + uuid,
+		OccurredAt:    timestamppb.Now(),
+		Bonus:         a.cfg.RegistrationBonus.Amount,
+		Campaign:      a.cfg.RegistrationBonus.Campaign,
+		SchemaVersion: RegistrationSchemaVersion,
+	})
+}
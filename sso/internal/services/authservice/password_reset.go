@@ -0,0 +1,137 @@
+package authservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	notificationv1 "github.com/AlexBlackNn/authloyalty/commands/proto/notification.v1/notification.v1"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/dto"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/storage"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// RequestPasswordReset issues single-use password reset token and publishes it
+// to broker, so that a mailer can deliver it to the user. Nothing is done for
+// unknown emails, but no error is returned to not disclose registered users.
+func (a *Auth) RequestPasswordReset(
+	ctx context.Context,
+	reqData *dto.RequestPasswordReset,
+) (context.Context, error) {
+	const op = "SERVICE LAYER: auth_service.RequestPasswordReset"
+
+	ctx, span := tracer.Start(ctx, "service layer: RequestPasswordReset",
+		trace.WithAttributes(attribute.String("handler", "RequestPasswordReset")))
+	defer span.End()
+
+	log := a.log.With(
+		slog.String("trace-id", "trace-id"),
+		slog.String("user-id", "user-id"),
+	)
+	log.Info("requesting password reset")
+
+	user, err := a.userStorage.GetUserByEmail(ctx, reqData.Email)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("password reset requested for unknown user")
+			return ctx, nil
+		}
+		log.Error("failed to get user", "err", err.Error())
+		return ctx, fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, tokenHash, err := newOneTimeToken()
+	if err != nil {
+		log.Error("failed to generate password reset token", "err", err.Error())
+		return ctx, fmt.Errorf("%s: %w", op, err)
+	}
+	err = a.tokenStorage.SavePasswordResetToken(
+		ctx, tokenHash, user.ID, a.cfg.PasswordResetTokenTtl,
+	)
+	if err != nil {
+		log.Error("failed to save password reset token", "err", err.Error())
+		return ctx, fmt.Errorf("%s: %w", op, err)
+	}
+
+	resetMsg := notificationv1.PasswordResetMessage{
+		Uuid:      user.ID,
+		Email:     user.Email,
+		Token:     token,
+		ExpiresAt: time.Now().Add(a.cfg.PasswordResetTokenTtl).Unix(),
+	}
+	err = a.producer.Send(ctx, &resetMsg, a.cfg.Kafka.PasswordResetTopic, user.ID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(attribute.Bool("error", true))
+		span.RecordError(fmt.Errorf("sending message to broker failed %w", err))
+		log.Error("sending message to broker failed", "err", err.Error())
+		return ctx, fmt.Errorf("%s: %w", op, err)
+	}
+	span.AddEvent(
+		"password reset requested",
+		trace.WithAttributes(attribute.String("user-id", user.ID)),
+	)
+	log.Info("password reset requested", "uuid", user.ID)
+	return ctx, nil
+}
+
+// ConfirmPasswordReset sets new user password using password reset token and
+// revokes all user sessions.
+func (a *Auth) ConfirmPasswordReset(
+	ctx context.Context,
+	reqData *dto.ConfirmPasswordReset,
+) (context.Context, error) {
+	const op = "SERVICE LAYER: auth_service.ConfirmPasswordReset"
+
+	ctx, span := tracer.Start(ctx, "service layer: ConfirmPasswordReset",
+		trace.WithAttributes(attribute.String("handler", "ConfirmPasswordReset")))
+	defer span.End()
+
+	log := a.log.With(
+		slog.String("trace-id", "trace-id"),
+		slog.String("user-id", "user-id"),
+	)
+	log.Info("confirming password reset")
+
+	userID, err := a.tokenStorage.ConsumePasswordResetToken(ctx, hashOneTimeToken(reqData.Token))
+	if err != nil {
+		if errors.Is(err, storage.ErrOneTimeTokenNotFound) {
+			log.Warn("invalid password reset token")
+			return ctx, fmt.Errorf("%s: %w", op, ErrResetTokenInvalid)
+		}
+		log.Error("failed to get password reset token", "err", err.Error())
+		return ctx, fmt.Errorf("%s: %w", op, err)
+	}
+
+	passHash, err := bcrypt.GenerateFromPassword(
+		[]byte(reqData.Password), bcrypt.DefaultCost,
+	)
+	if err != nil {
+		log.Error("failed to generate password hash", "err", err.Error())
+		return ctx, fmt.Errorf("%s: %w", op, err)
+	}
+	err = a.userStorage.UpdatePassword(ctx, userID, passHash)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", "err", err.Error())
+			return ctx, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+		log.Error("failed to update password", "err", err.Error())
+		return ctx, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err = a.revokeSessions(ctx, userID); err != nil {
+		return ctx, fmt.Errorf("%s: %w", op, err)
+	}
+	span.AddEvent(
+		"password reset",
+		trace.WithAttributes(attribute.String("user-id", userID)),
+	)
+	log.Info("password reset", "uuid", userID)
+	return ctx, nil
+}
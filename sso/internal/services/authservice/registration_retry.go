@@ -0,0 +1,127 @@
+package authservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/sso/internal/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	registrationRetryScanned = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "sso_registration_retry_scanned_users_total",
+		Help: "Number of users found with undelivered registration message.",
+	})
+	registrationRetryScheduled = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "sso_registration_retry_scheduled_total",
+		Help: "Number of registration messages saved to outbox again.",
+	})
+	registrationRetryErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "sso_registration_retry_errors_total",
+		Help: "Number of failed registration message retries.",
+	})
+	registrationRetryExhausted = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "sso_registration_retry_exhausted_users",
+		Help: "Number of users given up after max registration message retries.",
+	})
+	registrationRetryLastRun = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "sso_registration_retry_last_run_timestamp_seconds",
+		Help: "Unix time of the last registration retry scan.",
+	})
+)
+
+// RunRegistrationRetry periodically re-sends registration message to users
+// stuck in failed or inProgress message status until ctx is done.
+func (a *Auth) RunRegistrationRetry(ctx context.Context) {
+	ticker := time.NewTicker(a.cfg.RegistrationRetry.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := a.RetryUndeliveredRegistrations(ctx); err != nil {
+			a.log.Error("registration retry failed", "err", err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RetryUndeliveredRegistrations saves registration message to outbox again for
+// a batch of users with undelivered message and returns number of retried
+// users. Users are retried with backoff at most MaxAttempts times.
+func (a *Auth) RetryUndeliveredRegistrations(ctx context.Context) (int, error) {
+	const op = "SERVICE LAYER: auth_service.RetryUndeliveredRegistrations"
+
+	ctx, span := tracer.Start(ctx, "service layer: RetryUndeliveredRegistrations",
+		trace.WithAttributes(attribute.String("handler", "RetryUndeliveredRegistrations")))
+	defer span.End()
+
+	cfg := a.cfg.RegistrationRetry
+	defer registrationRetryLastRun.SetToCurrentTime()
+
+	exhausted, err := a.userStorage.CountExhaustedRegistrations(ctx, cfg.MaxAttempts)
+	if err != nil {
+		a.log.Error("failed to count exhausted registrations", "err", err.Error())
+	} else {
+		registrationRetryExhausted.Set(float64(exhausted))
+	}
+
+	users, err := a.userStorage.GetUndeliveredRegistrations(
+		ctx,
+		a.cfg.Kafka.Topic,
+		cfg.StaleAfter,
+		cfg.MaxAttempts,
+		// registration bonus of unverified users is held until verification
+		a.cfg.EmailVerification.HoldRegistrationBonus,
+		cfg.BatchSize,
+	)
+	if err != nil {
+		registrationRetryErrors.Inc()
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	registrationRetryScanned.Add(float64(len(users)))
+
+	var retried int
+	for _, user := range users {
+		event, err := a.registrationEvent(user.UserID)
+		if err != nil {
+			registrationRetryErrors.Inc()
+			return retried, fmt.Errorf("%s: %w", op, err)
+		}
+		delay := registrationRetryDelay(cfg, user.Attempts)
+		ok, err := a.userStorage.RetryRegistration(ctx, user.UserID, user.Attempts, delay, event)
+		if err != nil {
+			registrationRetryErrors.Inc()
+			a.log.Error("failed to retry registration", "err", err.Error(), "uuid", user.UserID)
+			continue
+		}
+		if !ok {
+			// retried by another sso instance
+			continue
+		}
+		registrationRetryScheduled.Inc()
+		retried++
+		a.log.Info(
+			"registration message retried",
+			"uuid", user.UserID,
+			"attempt", user.Attempts+1,
+			"next-retry-in", delay,
+		)
+	}
+	return retried, nil
+}
+
+// registrationRetryDelay doubles delay after each re-send up to MaxRetryDelay.
+func registrationRetryDelay(cfg config.RegistrationRetryConfig, attempts int) time.Duration {
+	delay := cfg.RetryDelay
+	for i := 0; i < attempts && delay < cfg.MaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, cfg.MaxRetryDelay)
+}
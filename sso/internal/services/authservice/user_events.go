@@ -0,0 +1,67 @@
+package authservice
+
+import (
+	"time"
+
+	eventsv1 "github.com/AlexBlackNn/authloyalty/commands/proto/events.v1/events.v1"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// UserEventSchemaVersion is version of user event envelope populated by sso.
+const UserEventSchemaVersion = 1
+
+// userEvent returns user lifecycle event to be saved to outbox in the same
+// transaction as the change it describes, payload of the event must be set.
+func (a *Auth) userEvent(userID string, event *eventsv1.UserEvent) (domain.OutboxMessage, error) {
+	event.EventId = uuid.NewString()
+	event.SchemaVersion = UserEventSchemaVersion
+	event.UserId = userID
+	event.OccurredAt = timestamppb.Now()
+	return newOutboxMessage(a.cfg.Kafka.UserEventsTopic, userID, event)
+}
+
+// roleChangedEvent returns event published when role is granted or revoked.
+func roleChangedEvent(role string, granted bool) *eventsv1.UserEvent {
+	return &eventsv1.UserEvent{
+		Payload: &eventsv1.UserEvent_RoleChanged{
+			RoleChanged: &eventsv1.RoleChanged{Role: role, Granted: granted},
+		},
+	}
+}
+
+// userBlockedEvent returns event published when user is blocked, until is nil
+// if user is blocked until unblocked.
+func userBlockedEvent(reason string, until *time.Time) *eventsv1.UserEvent {
+	blocked := &eventsv1.UserBlocked{Reason: reason}
+	if until != nil {
+		blocked.Until = timestamppb.New(*until)
+	}
+	return &eventsv1.UserEvent{
+		Payload: &eventsv1.UserEvent_UserBlocked{UserBlocked: blocked},
+	}
+}
+
+// userUnblockedEvent returns event published when user is unblocked.
+func userUnblockedEvent() *eventsv1.UserEvent {
+	return &eventsv1.UserEvent{
+		Payload: &eventsv1.UserEvent_UserUnblocked{UserUnblocked: &eventsv1.UserUnblocked{}},
+	}
+}
+
+// userDeletedEvent returns event published when user is deleted.
+func userDeletedEvent() *eventsv1.UserEvent {
+	return &eventsv1.UserEvent{
+		Payload: &eventsv1.UserEvent_UserDeleted{UserDeleted: &eventsv1.UserDeleted{}},
+	}
+}
+
+// emailChangedEvent returns event published when user changes email.
+func emailChangedEvent(verified bool) *eventsv1.UserEvent {
+	return &eventsv1.UserEvent{
+		Payload: &eventsv1.UserEvent_EmailChanged{
+			EmailChanged: &eventsv1.EmailChanged{Verified: verified},
+		},
+	}
+}
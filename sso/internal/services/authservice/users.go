@@ -0,0 +1,177 @@
+package authservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	eventsv1 "github.com/AlexBlackNn/authloyalty/commands/proto/events.v1/events.v1"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/dto"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/storage"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BlockUser blocks user login and revokes user sessions. Already issued
+// access tokens are valid until they expire.
+func (a *Auth) BlockUser(
+	ctx context.Context,
+	token string,
+	reqData *dto.BlockUser,
+) error {
+	const op = "SERVICE LAYER: auth_service.BlockUser"
+
+	ctx, span := tracer.Start(ctx, "service layer: BlockUser",
+		trace.WithAttributes(attribute.String("handler", "BlockUser")))
+	defer span.End()
+
+	err := a.manageUser(ctx, token, reqData.UserID, userBlockedEvent(reqData.Reason, reqData.Until),
+		func(ctx context.Context, event domain.OutboxMessage) error {
+			return a.userStorage.BlockUser(ctx, reqData.UserID, reqData.Reason, reqData.Until, event)
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if _, err = a.revokeSessions(ctx, reqData.UserID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	a.log.Info("user blocked", "user-id", reqData.UserID)
+	return nil
+}
+
+// UnblockUser removes login block of the user.
+func (a *Auth) UnblockUser(
+	ctx context.Context,
+	token string,
+	reqData *dto.UserID,
+) error {
+	const op = "SERVICE LAYER: auth_service.UnblockUser"
+
+	ctx, span := tracer.Start(ctx, "service layer: UnblockUser",
+		trace.WithAttributes(attribute.String("handler", "UnblockUser")))
+	defer span.End()
+
+	err := a.manageUser(ctx, token, reqData.UserID, userUnblockedEvent(),
+		func(ctx context.Context, event domain.OutboxMessage) error {
+			return a.userStorage.UnblockUser(ctx, reqData.UserID, event)
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	a.log.Info("user unblocked", "user-id", reqData.UserID)
+	return nil
+}
+
+// DeleteUser deletes user and revokes user sessions. Already issued access
+// tokens are valid until they expire.
+func (a *Auth) DeleteUser(
+	ctx context.Context,
+	token string,
+	reqData *dto.UserID,
+) error {
+	const op = "SERVICE LAYER: auth_service.DeleteUser"
+
+	ctx, span := tracer.Start(ctx, "service layer: DeleteUser",
+		trace.WithAttributes(attribute.String("handler", "DeleteUser")))
+	defer span.End()
+
+	err := a.manageUser(ctx, token, reqData.UserID, userDeletedEvent(),
+		func(ctx context.Context, event domain.OutboxMessage) error {
+			return a.userStorage.DeleteUser(ctx, reqData.UserID, event)
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if _, err = a.revokeSessions(ctx, reqData.UserID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	a.log.Info("user deleted", "user-id", reqData.UserID)
+	return nil
+}
+
+// manageUser authorizes token owner to manage users and saves user change
+// with event describing it.
+func (a *Auth) manageUser(
+	ctx context.Context,
+	token string,
+	userID string,
+	payload *eventsv1.UserEvent,
+	save func(ctx context.Context, event domain.OutboxMessage) error,
+) error {
+	log := a.log.With(
+		slog.String("trace-id", "trace-id"),
+		slog.String("user-id", userID),
+	)
+	ctx, err := a.authorize(ctx, token, domain.PermissionUsersManage)
+	if err != nil {
+		log.Warn("managing user is not allowed", "err", err.Error())
+		return err
+	}
+	event, err := a.userEvent(userID, payload)
+	if err != nil {
+		log.Error("failed to create user event", "err", err.Error())
+		return err
+	}
+	if err = save(ctx, event); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return ErrUserNotFound
+		}
+		log.Error("failed to save user", "err", err.Error())
+		return err
+	}
+	return nil
+}
+
+// ChangeEmail replaces email of the access token owner. The new email is not
+// verified, verification email is sent to it.
+func (a *Auth) ChangeEmail(
+	ctx context.Context,
+	token string,
+	reqData *dto.ChangeEmail,
+) error {
+	const op = "SERVICE LAYER: auth_service.ChangeEmail"
+
+	ctx, span := tracer.Start(ctx, "service layer: ChangeEmail",
+		trace.WithAttributes(attribute.String("handler", "ChangeEmail")))
+	defer span.End()
+
+	ctx, user, err := a.accessTokenOwner(ctx, token)
+	if err != nil {
+		a.log.Warn("changing email is not allowed", "err", err.Error())
+		return err
+	}
+	log := a.log.With(
+		slog.String("trace-id", "trace-id"),
+		slog.String("user-id", user.ID),
+	)
+	if err = bcrypt.CompareHashAndPassword(user.PassHash, []byte(reqData.Password)); err != nil {
+		log.Warn("invalid credentials")
+		return fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+	}
+	event, err := a.userEvent(user.ID, emailChangedEvent(false))
+	if err != nil {
+		log.Error("failed to create user event", "err", err.Error())
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err = a.userStorage.UpdateEmail(ctx, user.ID, reqData.Email, event); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+		log.Error("failed to change email", "err", err.Error())
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("email changed")
+	if err = a.sendEmailVerification(ctx, user.ID, reqData.Email); err != nil {
+		// email is changed anyway, verification email can be requested again
+		// with ResendEmailVerification
+		span.RecordError(fmt.Errorf("sending email verification failed %w", err))
+		log.Error("sending email verification failed", "err", err.Error())
+	}
+	return nil
+}
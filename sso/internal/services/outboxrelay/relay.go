@@ -0,0 +1,182 @@
+package outboxrelay
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/sso/internal/config"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+type outboxStorage interface {
+	ClaimOutboxMessages(
+		ctx context.Context,
+		limit int,
+		lease time.Duration,
+	) ([]domain.OutboxMessage, error)
+	MarkOutboxMessageSent(
+		ctx context.Context,
+		id int64,
+	) error
+	RetryOutboxMessage(
+		ctx context.Context,
+		id int64,
+		reason string,
+		delay time.Duration,
+	) error
+	UpdateSendStatus(
+		ctx context.Context,
+		uuid string,
+		status string,
+	) error
+}
+
+type syncSender interface {
+	SendSync(
+		ctx context.Context,
+		msg proto.Message,
+		topic string,
+		key string,
+	) error
+}
+
+var tracer = otel.Tracer("sso service")
+
+// Relay publishes events saved to outbox to kafka. An event is marked sent
+// only after kafka confirms delivery, so events are delivered at least once
+// even if sso is restarted.
+type Relay struct {
+	log      *slog.Logger
+	cfg      *config.Config
+	storage  outboxStorage
+	producer syncSender
+}
+
+func New(
+	cfg *config.Config,
+	log *slog.Logger,
+	storage outboxStorage,
+	producer syncSender,
+) *Relay {
+	return &Relay{
+		log:      log,
+		cfg:      cfg,
+		storage:  storage,
+		producer: producer,
+	}
+}
+
+// Run publishes pending events until ctx is done.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.Outbox.PollInterval)
+	defer ticker.Stop()
+	for {
+		// full batch means more events are pending, they are published without waiting
+		if r.PublishPending(ctx) == r.cfg.Outbox.BatchSize && ctx.Err() == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishPending publishes a batch of pending events and returns its size.
+func (r *Relay) PublishPending(ctx context.Context) int {
+	ctx, span := tracer.Start(ctx, "service layer: PublishPending",
+		trace.WithAttributes(attribute.String("handler", "PublishPending")))
+	defer span.End()
+
+	// messages are published one by one, each waits for delivery up to
+	// DeliveryTimeout, so the lease must cover the whole batch
+	lease := time.Duration(r.cfg.Outbox.BatchSize) * r.cfg.Outbox.DeliveryTimeout
+	leaseEnd := time.Now().Add(lease)
+	messages, err := r.storage.ClaimOutboxMessages(ctx, r.cfg.Outbox.BatchSize, lease)
+	if err != nil {
+		r.log.Error("failed to get outbox messages", "err", err.Error())
+		return 0
+	}
+	for i, msg := range messages {
+		if time.Until(leaseEnd) < r.cfg.Outbox.DeliveryTimeout {
+			// lease may expire before delivery is confirmed and another relay
+			// would publish the rest too, they are published after the lease
+			r.log.Warn("outbox lease is running out", "unpublished", len(messages)-i)
+			break
+		}
+		r.publish(ctx, msg)
+	}
+	return len(messages)
+}
+
+func (r *Relay) publish(ctx context.Context, msg domain.OutboxMessage) {
+	log := r.log.With(
+		slog.Int64("outbox-id", msg.ID),
+		slog.String("topic", msg.Topic),
+		slog.String("key", msg.Key),
+	)
+	err := r.send(ctx, msg)
+	if err != nil {
+		delay := retryDelay(r.cfg.Outbox, msg.Attempts)
+		log.Error(
+			"failed to publish outbox message",
+			"err", err.Error(),
+			"attempts", msg.Attempts,
+			"retry-in", delay,
+		)
+		if err = r.storage.RetryOutboxMessage(ctx, msg.ID, err.Error(), delay); err != nil {
+			log.Error("failed to schedule outbox message retry", "err", err.Error())
+		}
+		r.updateSendStatus(ctx, msg, "failed")
+		return
+	}
+	if err = r.storage.MarkOutboxMessageSent(ctx, msg.ID); err != nil {
+		// message is published again after delivery timeout
+		log.Error("failed to mark outbox message sent", "err", err.Error())
+		return
+	}
+	r.updateSendStatus(ctx, msg, "successful")
+	log.Info("outbox message published")
+}
+
+func (r *Relay) send(ctx context.Context, msg domain.OutboxMessage) error {
+	msgType, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(msg.Type))
+	if err != nil {
+		return fmt.Errorf("unknown message type %s: %w", msg.Type, err)
+	}
+	protoMsg := msgType.New().Interface()
+	if err = proto.Unmarshal(msg.Payload, protoMsg); err != nil {
+		return fmt.Errorf("failed to unmarshal message: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.Outbox.DeliveryTimeout)
+	defer cancel()
+	return r.producer.SendSync(ctx, protoMsg, msg.Topic, msg.Key)
+}
+
+// updateSendStatus keeps users.message_status of registration messages.
+func (r *Relay) updateSendStatus(ctx context.Context, msg domain.OutboxMessage, status string) {
+	if msg.Topic != r.cfg.Kafka.Topic {
+		return
+	}
+	if err := r.storage.UpdateSendStatus(ctx, msg.Key, status); err != nil {
+		r.log.Error("failed to update message status", "err", err.Error(), "uuid", msg.Key)
+	}
+}
+
+// retryDelay doubles delay after each failed attempt up to MaxRetryDelay.
+func retryDelay(cfg config.OutboxConfig, attempts int) time.Duration {
+	delay := cfg.RetryDelay
+	for i := 1; i < attempts && delay < cfg.MaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, cfg.MaxRetryDelay)
+}
@@ -0,0 +1,149 @@
+package patroni
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AlexBlackNn/authloyalty/sso/internal/storage"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SaveMFASecret saves encrypted TOTP secret waiting for confirmation. Secret of
+// user with already enabled MFA can't be replaced.
+func (s *Storage) SaveMFASecret(ctx context.Context, uuid string, secret string) error {
+	ctx, span := tracer.Start(ctx, "data layer Patroni: SaveMFASecret",
+		trace.WithAttributes(attribute.String("handler", "SaveMFASecret")))
+	defer span.End()
+
+	encrypted, err := s.cipher.Encrypt([]byte(secret))
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.SaveMFASecret: couldn't encrypt secret %w", err)
+	}
+	query := `UPDATE users SET mfa_secret=$2, modified=CURRENT_TIMESTAMP
+		WHERE uuid = $1 AND mfa_enabled_at IS NULL;`
+	result, err := s.dbWrite.ExecContext(ctx, query, uuid, encrypted)
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.SaveMFASecret: couldn't save secret %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.SaveMFASecret: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("DATA LAYER: storage.postgres.SaveMFASecret: %w", storage.ErrMFAEnabled)
+	}
+	return nil
+}
+
+// GetMFASecret returns decrypted TOTP secret of the user.
+func (s *Storage) GetMFASecret(ctx context.Context, uuid string) (string, error) {
+	ctx, span := tracer.Start(ctx, "data layer Patroni: GetMFASecret",
+		trace.WithAttributes(attribute.String("handler", "GetMFASecret")))
+	defer span.End()
+
+	// secret is read from master, it might be saved just before confirmation
+	query := "SELECT mfa_secret FROM users WHERE uuid = $1;"
+	var encrypted []byte
+	err := s.dbWrite.QueryRowContext(ctx, query, uuid).Scan(&encrypted)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("DATA LAYER: storage.postgres.GetMFASecret: %w", storage.ErrUserNotFound)
+		}
+		return "", fmt.Errorf("DATA LAYER: storage.postgres.GetMFASecret: %w", err)
+	}
+	if encrypted == nil {
+		return "", fmt.Errorf("DATA LAYER: storage.postgres.GetMFASecret: %w", storage.ErrMFANotEnrolled)
+	}
+	secret, err := s.cipher.Decrypt(encrypted)
+	if err != nil {
+		return "", fmt.Errorf("DATA LAYER: storage.postgres.GetMFASecret: couldn't decrypt secret %w", err)
+	}
+	return string(secret), nil
+}
+
+// EnableMFA enables MFA with previously saved secret and replaces recovery codes.
+func (s *Storage) EnableMFA(ctx context.Context, uuid string, recoveryCodeHashes []string) error {
+	ctx, span := tracer.Start(ctx, "data layer Patroni: EnableMFA",
+		trace.WithAttributes(attribute.String("handler", "EnableMFA")))
+	defer span.End()
+
+	tx, err := s.dbWrite.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.EnableMFA: failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE users SET mfa_enabled_at=CURRENT_TIMESTAMP, modified=CURRENT_TIMESTAMP
+		WHERE uuid = $1 AND mfa_secret IS NOT NULL AND mfa_enabled_at IS NULL;`
+	result, err := tx.ExecContext(ctx, query, uuid)
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.EnableMFA: couldn't enable mfa %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.EnableMFA: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("DATA LAYER: storage.postgres.EnableMFA: %w", storage.ErrMFAEnabled)
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM mfa_recovery_codes WHERE user_uuid = $1;", uuid)
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.EnableMFA: couldn't delete recovery codes %w", err)
+	}
+	query = "INSERT INTO mfa_recovery_codes(user_uuid, code_hash) VALUES($1, $2);"
+	for _, codeHash := range recoveryCodeHashes {
+		if _, err = tx.ExecContext(ctx, query, uuid, codeHash); err != nil {
+			return fmt.Errorf("DATA LAYER: storage.postgres.EnableMFA: couldn't save recovery code %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// UseRecoveryCode marks recovery code as used, each code can be used only once.
+func (s *Storage) UseRecoveryCode(ctx context.Context, uuid string, codeHash string) error {
+	ctx, span := tracer.Start(ctx, "data layer Patroni: UseRecoveryCode",
+		trace.WithAttributes(attribute.String("handler", "UseRecoveryCode")))
+	defer span.End()
+
+	query := `UPDATE mfa_recovery_codes SET used_at=CURRENT_TIMESTAMP
+		WHERE user_uuid = $1 AND code_hash = $2 AND used_at IS NULL;`
+	result, err := s.dbWrite.ExecContext(ctx, query, uuid, codeHash)
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.UseRecoveryCode: couldn't use recovery code %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.UseRecoveryCode: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("DATA LAYER: storage.postgres.UseRecoveryCode: %w", storage.ErrRecoveryCodeNotFound)
+	}
+	return nil
+}
+
+// UseTOTPStep saves time step of accepted TOTP code. The step must be greater
+// than the last accepted one, so each code is accepted once.
+func (s *Storage) UseTOTPStep(ctx context.Context, uuid string, step int64) error {
+	ctx, span := tracer.Start(ctx, "data layer Patroni: UseTOTPStep",
+		trace.WithAttributes(attribute.String("handler", "UseTOTPStep")))
+	defer span.End()
+
+	query := `UPDATE users SET mfa_last_step=$2
+		WHERE uuid = $1 AND (mfa_last_step IS NULL OR mfa_last_step < $2);`
+	result, err := s.dbWrite.ExecContext(ctx, query, uuid, step)
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.UseTOTPStep: couldn't save totp step %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.UseTOTPStep: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("DATA LAYER: storage.postgres.UseTOTPStep: %w", storage.ErrTOTPStepUsed)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package patroni
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// saveOutboxMessages saves events within transaction of the data change.
+func saveOutboxMessages(ctx context.Context, tx *sql.Tx, messages ...domain.OutboxMessage) error {
+	query := `INSERT INTO outbox(topic, message_key, message_type, payload)
+		VALUES($1, $2, $3, $4);`
+	for _, msg := range messages {
+		_, err := tx.ExecContext(ctx, query, msg.Topic, msg.Key, msg.Type, msg.Payload)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClaimOutboxMessages returns pending events and postpones their next attempt
+// by lease, so other sso instances skip them while they are being published.
+// If delivery is not confirmed within lease, events are published again.
+// Events with the same key are published in order: an event is claimed only
+// if no earlier event of its key is unsent, so an event waiting for retry holds
+// back the later ones (e.g. UserUnblocked is not published before UserBlocked).
+func (s *Storage) ClaimOutboxMessages(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+) ([]domain.OutboxMessage, error) {
+	ctx, span := tracer.Start(ctx, "data layer Patroni: ClaimOutboxMessages",
+		trace.WithAttributes(attribute.String("handler", "ClaimOutboxMessages")))
+	defer span.End()
+
+	query := `UPDATE outbox SET attempts = attempts + 1,
+			next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM outbox o
+			WHERE sent_at IS NULL AND next_attempt_at <= CURRENT_TIMESTAMP
+				AND NOT EXISTS (
+					SELECT 1 FROM outbox earlier
+					WHERE earlier.topic = o.topic AND earlier.message_key = o.message_key
+						AND earlier.sent_at IS NULL AND earlier.id < o.id
+				)
+			ORDER BY id LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, topic, message_key, message_type, payload, attempts;`
+	rows, err := s.dbWrite.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.ClaimOutboxMessages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []domain.OutboxMessage
+	for rows.Next() {
+		var msg domain.OutboxMessage
+		err = rows.Scan(&msg.ID, &msg.Topic, &msg.Key, &msg.Type, &msg.Payload, &msg.Attempts)
+		if err != nil {
+			return nil, fmt.Errorf("DATA LAYER: storage.postgres.ClaimOutboxMessages: %w", err)
+		}
+		messages = append(messages, msg)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.ClaimOutboxMessages: %w", err)
+	}
+	return messages, nil
+}
+
+// MarkOutboxMessageSent marks event delivered, it is never published again.
+func (s *Storage) MarkOutboxMessageSent(ctx context.Context, id int64) error {
+	ctx, span := tracer.Start(ctx, "data layer Patroni: MarkOutboxMessageSent",
+		trace.WithAttributes(attribute.String("handler", "MarkOutboxMessageSent")))
+	defer span.End()
+
+	query := "UPDATE outbox SET sent_at=CURRENT_TIMESTAMP, last_error=NULL WHERE id = $1;"
+	if _, err := s.dbWrite.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.MarkOutboxMessageSent: %w", err)
+	}
+	return nil
+}
+
+// RetryOutboxMessage saves the delivery error and schedules next attempt.
+func (s *Storage) RetryOutboxMessage(
+	ctx context.Context,
+	id int64,
+	reason string,
+	delay time.Duration,
+) error {
+	ctx, span := tracer.Start(ctx, "data layer Patroni: RetryOutboxMessage",
+		trace.WithAttributes(attribute.String("handler", "RetryOutboxMessage")))
+	defer span.End()
+
+	query := `UPDATE outbox SET last_error=$2,
+		next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $3)
+		WHERE id = $1 AND sent_at IS NULL;`
+	if _, err := s.dbWrite.ExecContext(ctx, query, id, reason, delay.Seconds()); err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.RetryOutboxMessage: %w", err)
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/AlexBlackNn/authloyalty/sso/internal/config"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/lib/encryption"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/storage"
 	"github.com/XSAM/otelsql"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -23,11 +24,17 @@ const UniqueViolation = "23505"
 type Storage struct {
 	dbRead  *sql.DB
 	dbWrite *sql.DB
+	// cipher encrypts secrets stored in db.
+	cipher *encryption.Cipher
 }
 
 var tracer = otel.Tracer("sso service")
 
 func New(cfg *config.Config) (*Storage, error) {
+	secretCipher, err := encryption.New(cfg.MFA.EncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.New: %w", err)
+	}
 	dbWrite, err := otelsql.Open("pgx", cfg.StoragePatroni.Master)
 	if err != nil {
 		return nil, fmt.Errorf(
@@ -56,7 +63,12 @@ func New(cfg *config.Config) (*Storage, error) {
 			"DATA LAYER: storage.postgres.New: couldn't connect to database for Write: %w", err,
 		)
 	}
-	return &Storage{dbRead: dbRead, dbWrite: dbWrite}, nil
+	s := &Storage{dbRead: dbRead, dbWrite: dbWrite, cipher: secretCipher}
+	// keys saved before encryption must be encrypted before they are read
+	if err = s.encryptSigningKeys(context.Background()); err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.New: %w", err)
+	}
+	return s, nil
 }
 
 func (s *Storage) Stop() error {
@@ -70,17 +82,35 @@ func (s *Storage) Stop() error {
 	return errors.Join(err1, err2)
 }
 
-// SaveUser saves user to db.
-func (s *Storage) SaveUser(ctx context.Context, email string, passHash []byte) (string, error) {
+// SaveUser saves user to db. Event returned by newEvent for the saved user is
+// written to outbox in the same transaction, newEvent might be nil.
+func (s *Storage) SaveUser(
+	ctx context.Context,
+	email string,
+	passHash []byte,
+	newEvent func(uuid string) (domain.OutboxMessage, error),
+) (string, error) {
 	ctx, span := tracer.Start(
 		ctx, "data layer Patroni: SaveUser",
 		trace.WithAttributes(attribute.String("handler", "SaveUser")),
 	)
 	defer span.End()
 
+	tx, err := s.dbWrite.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf(
+			"DATA LAYER: storage.postgres.SaveUser: failed to begin transaction: %w", err,
+		)
+	}
+	defer tx.Rollback()
+
 	var uuid string
-	query := "INSERT INTO users(email, pass_hash) VALUES($1, $2) RETURNING uuid"
-	err := s.dbWrite.QueryRowContext(ctx, query, email, passHash).Scan(&uuid)
+	// every user gets default role in the same statement
+	query := `WITH new_user AS (
+			INSERT INTO users(email, pass_hash) VALUES($1, $2) RETURNING uuid
+		)
+		INSERT INTO user_roles(user_uuid, role) SELECT uuid, $3 FROM new_user RETURNING user_uuid;`
+	err = tx.QueryRowContext(ctx, query, email, passHash, domain.RoleUser).Scan(&uuid)
 	// https://www.postgresql.org/docs/11/protocol-error-fields.html
 	var pgerr *pgconn.PgError
 	if errors.As(err, &pgerr) {
@@ -94,6 +124,18 @@ func (s *Storage) SaveUser(ctx context.Context, email string, passHash []byte) (
 			err,
 		)
 	}
+	if newEvent != nil {
+		event, err := newEvent(uuid)
+		if err != nil {
+			return "", fmt.Errorf("DATA LAYER: storage.postgres.SaveUser: couldn't create event %w", err)
+		}
+		if err = saveOutboxMessages(ctx, tx, event); err != nil {
+			return "", fmt.Errorf("DATA LAYER: storage.postgres.SaveUser: couldn't save event %w", err)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return "", fmt.Errorf("DATA LAYER: storage.postgres.SaveUser: %w", err)
+	}
 	return uuid, nil
 }
 
@@ -102,11 +144,16 @@ func (s *Storage) GetUser(ctx context.Context, uuid string) (domain.User, error)
 		trace.WithAttributes(attribute.String("handler", "GetUser")))
 	defer span.End()
 
-	query := "SELECT uuid, email, pass_hash, is_admin FROM users WHERE (uuid = $1);"
+	query := `SELECT uuid, email, pass_hash, verified_at, mfa_enabled_at IS NOT NULL,
+			blocked_at IS NOT NULL AND (blocked_until IS NULL OR blocked_until > CURRENT_TIMESTAMP)
+		FROM users WHERE (uuid = $1);`
 	row := s.dbRead.QueryRowContext(ctx, query, uuid)
 
 	var user domain.User
-	err := row.Scan(&user.ID, &user.Email, &user.PassHash, &user.IsAdmin)
+	var verifiedAt sql.NullTime
+	err := row.Scan(
+		&user.ID, &user.Email, &user.PassHash, &verifiedAt, &user.MFAEnabled, &user.Blocked,
+	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return domain.User{}, fmt.Errorf(
@@ -119,6 +166,12 @@ func (s *Storage) GetUser(ctx context.Context, uuid string) (domain.User, error)
 			err,
 		)
 	}
+	if verifiedAt.Valid {
+		user.VerifiedAt = &verifiedAt.Time
+	}
+	if err = s.loadRoles(ctx, &user); err != nil {
+		return domain.User{}, err
+	}
 	return user, nil
 }
 
@@ -127,11 +180,16 @@ func (s *Storage) GetUserByEmail(ctx context.Context, email string) (domain.User
 		trace.WithAttributes(attribute.String("handler", "GetUser")))
 	defer span.End()
 
-	query := "SELECT uuid, email, pass_hash, is_admin FROM users WHERE (email = $1);"
+	query := `SELECT uuid, email, pass_hash, verified_at, mfa_enabled_at IS NOT NULL,
+			blocked_at IS NOT NULL AND (blocked_until IS NULL OR blocked_until > CURRENT_TIMESTAMP)
+		FROM users WHERE (email = $1);`
 	row := s.dbRead.QueryRowContext(ctx, query, email)
 
 	var user domain.User
-	err := row.Scan(&user.ID, &user.Email, &user.PassHash, &user.IsAdmin)
+	var verifiedAt sql.NullTime
+	err := row.Scan(
+		&user.ID, &user.Email, &user.PassHash, &verifiedAt, &user.MFAEnabled, &user.Blocked,
+	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return domain.User{}, fmt.Errorf(
@@ -144,6 +202,12 @@ func (s *Storage) GetUserByEmail(ctx context.Context, email string) (domain.User
 			err,
 		)
 	}
+	if verifiedAt.Valid {
+		user.VerifiedAt = &verifiedAt.Time
+	}
+	if err = s.loadRoles(ctx, &user); err != nil {
+		return domain.User{}, err
+	}
 	return user, nil
 }
 
@@ -164,6 +228,76 @@ func (s *Storage) UpdateSendStatus(ctx context.Context, uuid string, status stri
 	return nil
 }
 
+// UpdatePassword replaces password hash of the user.
+func (s *Storage) UpdatePassword(ctx context.Context, uuid string, passHash []byte) error {
+	ctx, span := tracer.Start(ctx, "data layer Patroni: UpdatePassword",
+		trace.WithAttributes(attribute.String("handler", "UpdatePassword")))
+	defer span.End()
+
+	query := "UPDATE users SET pass_hash=$2, modified=CURRENT_TIMESTAMP WHERE uuid = $1;"
+	result, err := s.dbWrite.ExecContext(ctx, query, uuid, passHash)
+	if err != nil {
+		return fmt.Errorf(
+			"DATA LAYER: storage.postgres.UpdatePassword: couldn't update password  %w",
+			err,
+		)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.UpdatePassword: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("DATA LAYER: storage.postgres.UpdatePassword: %w", storage.ErrUserNotFound)
+	}
+	return nil
+}
+
+// VerifyEmail marks user email as verified and saves events to outbox in the
+// same transaction. Verification of already verified email changes nothing,
+// events are not saved again and verification time is kept.
+func (s *Storage) VerifyEmail(
+	ctx context.Context,
+	uuid string,
+	events ...domain.OutboxMessage,
+) error {
+	ctx, span := tracer.Start(ctx, "data layer Patroni: VerifyEmail",
+		trace.WithAttributes(attribute.String("handler", "VerifyEmail")))
+	defer span.End()
+
+	tx, err := s.dbWrite.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf(
+			"DATA LAYER: storage.postgres.VerifyEmail: failed to begin transaction: %w", err,
+		)
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE users SET verified_at=CURRENT_TIMESTAMP, modified=CURRENT_TIMESTAMP
+		WHERE uuid = $1 AND verified_at IS NULL RETURNING uuid;`
+	err = tx.QueryRowContext(ctx, query, uuid).Scan(&uuid)
+	if errors.Is(err, sql.ErrNoRows) {
+		var exists bool
+		query = "SELECT EXISTS (SELECT 1 FROM users WHERE uuid = $1);"
+		if err = tx.QueryRowContext(ctx, query, uuid).Scan(&exists); err != nil {
+			return fmt.Errorf("DATA LAYER: storage.postgres.VerifyEmail: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("DATA LAYER: storage.postgres.VerifyEmail: %w", storage.ErrUserNotFound)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf(
+			"DATA LAYER: storage.postgres.VerifyEmail: couldn't verify email  %w",
+			err,
+		)
+	}
+	if err = saveOutboxMessages(ctx, tx, events...); err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.VerifyEmail: couldn't save events %w", err)
+	}
+	return tx.Commit()
+}
+
 func (s *Storage) HealthCheck(ctx context.Context) error {
 	ctx, span := tracer.Start(ctx, "data layer Patroni: HealthCheck",
 		trace.WithAttributes(attribute.String("handler", "HealthCheck")))
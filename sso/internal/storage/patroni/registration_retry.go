@@ -0,0 +1,122 @@
+package patroni
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GetUndeliveredRegistrations returns users in failed message status or stuck
+// in inProgress status longer than staleAfter. Users with registration message
+// still pending in outbox, re-sent maxAttempts times or waiting for the next
+// attempt are skipped.
+func (s *Storage) GetUndeliveredRegistrations(
+	ctx context.Context,
+	topic string,
+	staleAfter time.Duration,
+	maxAttempts int,
+	verifiedOnly bool,
+	limit int,
+) ([]domain.UndeliveredRegistration, error) {
+	ctx, span := tracer.Start(ctx, "data layer Patroni: GetUndeliveredRegistrations",
+		trace.WithAttributes(attribute.String("handler", "GetUndeliveredRegistrations")))
+	defer span.End()
+
+	query := `SELECT u.uuid, u.message_attempts FROM users u
+		WHERE (u.message_status = 'failed' OR (u.message_status = 'inProgress'
+			AND u.created <= CURRENT_TIMESTAMP - make_interval(secs => $2)))
+		AND u.message_attempts < $3
+		AND (u.message_retry_at IS NULL OR u.message_retry_at <= CURRENT_TIMESTAMP)
+		AND (NOT $4 OR u.verified_at IS NOT NULL)
+		AND NOT EXISTS (
+			SELECT 1 FROM outbox o
+			WHERE o.topic = $1 AND o.message_key = u.uuid::text AND o.sent_at IS NULL
+		)
+		ORDER BY u.created LIMIT $5;`
+	rows, err := s.dbWrite.QueryContext(
+		ctx, query, topic, staleAfter.Seconds(), maxAttempts, verifiedOnly, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.GetUndeliveredRegistrations: %w", err)
+	}
+	defer rows.Close()
+
+	var users []domain.UndeliveredRegistration
+	for rows.Next() {
+		var user domain.UndeliveredRegistration
+		if err = rows.Scan(&user.UserID, &user.Attempts); err != nil {
+			return nil, fmt.Errorf("DATA LAYER: storage.postgres.GetUndeliveredRegistrations: %w", err)
+		}
+		users = append(users, user)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.GetUndeliveredRegistrations: %w", err)
+	}
+	return users, nil
+}
+
+// CountExhaustedRegistrations returns number of users with undelivered
+// registration message that were re-sent maxAttempts times.
+func (s *Storage) CountExhaustedRegistrations(ctx context.Context, maxAttempts int) (int, error) {
+	ctx, span := tracer.Start(ctx, "data layer Patroni: CountExhaustedRegistrations",
+		trace.WithAttributes(attribute.String("handler", "CountExhaustedRegistrations")))
+	defer span.End()
+
+	query := `SELECT count(*) FROM users
+		WHERE message_status <> 'successful' AND message_attempts >= $1;`
+	var count int
+	if err := s.dbRead.QueryRowContext(ctx, query, maxAttempts).Scan(&count); err != nil {
+		return 0, fmt.Errorf("DATA LAYER: storage.postgres.CountExhaustedRegistrations: %w", err)
+	}
+	return count, nil
+}
+
+// RetryRegistration saves registration message to outbox again and postpones
+// the next attempt by delay. It returns false if the user was already retried
+// by another sso instance, attempts is the number of re-sends the caller saw.
+func (s *Storage) RetryRegistration(
+	ctx context.Context,
+	uuid string,
+	attempts int,
+	delay time.Duration,
+	event domain.OutboxMessage,
+) (bool, error) {
+	ctx, span := tracer.Start(ctx, "data layer Patroni: RetryRegistration",
+		trace.WithAttributes(attribute.String("handler", "RetryRegistration")))
+	defer span.End()
+
+	tx, err := s.dbWrite.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf(
+			"DATA LAYER: storage.postgres.RetryRegistration: failed to begin transaction: %w", err,
+		)
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE users SET message_status = 'inProgress',
+			message_attempts = message_attempts + 1,
+			message_retry_at = CURRENT_TIMESTAMP + make_interval(secs => $3)
+		WHERE uuid = $1 AND message_attempts = $2;`
+	result, err := tx.ExecContext(ctx, query, uuid, attempts, delay.Seconds())
+	if err != nil {
+		return false, fmt.Errorf("DATA LAYER: storage.postgres.RetryRegistration: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("DATA LAYER: storage.postgres.RetryRegistration: %w", err)
+	}
+	if affected == 0 {
+		return false, nil
+	}
+	if err = saveOutboxMessages(ctx, tx, event); err != nil {
+		return false, fmt.Errorf("DATA LAYER: storage.postgres.RetryRegistration: couldn't save event %w", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return false, fmt.Errorf("DATA LAYER: storage.postgres.RetryRegistration: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,133 @@
+package patroni
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/storage"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// https://www.postgresql.org/docs/11/errcodes-appendix.html
+const ForeignKeyViolation = "23503"
+
+// GrantRole assigns role to user. Granting already assigned role is not an
+// error, events are saved to outbox in the same transaction only if the role
+// is assigned.
+func (s *Storage) GrantRole(
+	ctx context.Context,
+	uuid string,
+	role string,
+	events ...domain.OutboxMessage,
+) error {
+	ctx, span := tracer.Start(ctx, "data layer Patroni: GrantRole",
+		trace.WithAttributes(attribute.String("handler", "GrantRole")))
+	defer span.End()
+
+	tx, err := s.dbWrite.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf(
+			"DATA LAYER: storage.postgres.GrantRole: failed to begin transaction: %w", err,
+		)
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO user_roles(user_uuid, role) VALUES($1, $2) ON CONFLICT DO NOTHING;"
+	result, err := tx.ExecContext(ctx, query, uuid, role)
+	var pgerr *pgconn.PgError
+	if errors.As(err, &pgerr) && pgerr.Code == ForeignKeyViolation {
+		if pgerr.ConstraintName == "user_roles_role_fkey" {
+			return fmt.Errorf("DATA LAYER: storage.postgres.GrantRole: %w", storage.ErrRoleNotFound)
+		}
+		return fmt.Errorf("DATA LAYER: storage.postgres.GrantRole: %w", storage.ErrUserNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.GrantRole: couldn't grant role %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.GrantRole: %w", err)
+	}
+	if affected == 0 {
+		return nil
+	}
+	if err = saveOutboxMessages(ctx, tx, events...); err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.GrantRole: couldn't save events %w", err)
+	}
+	return tx.Commit()
+}
+
+// RevokeRole removes role from user, events are saved to outbox in the same
+// transaction.
+func (s *Storage) RevokeRole(
+	ctx context.Context,
+	uuid string,
+	role string,
+	events ...domain.OutboxMessage,
+) error {
+	ctx, span := tracer.Start(ctx, "data layer Patroni: RevokeRole",
+		trace.WithAttributes(attribute.String("handler", "RevokeRole")))
+	defer span.End()
+
+	tx, err := s.dbWrite.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf(
+			"DATA LAYER: storage.postgres.RevokeRole: failed to begin transaction: %w", err,
+		)
+	}
+	defer tx.Rollback()
+
+	query := "DELETE FROM user_roles WHERE user_uuid = $1 AND role = $2;"
+	result, err := tx.ExecContext(ctx, query, uuid, role)
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.RevokeRole: couldn't revoke role %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.RevokeRole: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("DATA LAYER: storage.postgres.RevokeRole: %w", storage.ErrRoleNotGranted)
+	}
+	if err = saveOutboxMessages(ctx, tx, events...); err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.RevokeRole: couldn't save events %w", err)
+	}
+	return tx.Commit()
+}
+
+// loadRoles fills user roles and permissions granted by them.
+func (s *Storage) loadRoles(ctx context.Context, user *domain.User) error {
+	query := `SELECT ur.role, rp.permission FROM user_roles ur
+		LEFT JOIN role_permissions rp ON rp.role = ur.role
+		WHERE ur.user_uuid = $1 ORDER BY ur.role, rp.permission;`
+	rows, err := s.dbRead.QueryContext(ctx, query, user.ID)
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.loadRoles: %w", err)
+	}
+	defer rows.Close()
+
+	user.Roles, user.Permissions = nil, nil
+	for rows.Next() {
+		var role string
+		var permission sql.NullString
+		if err = rows.Scan(&role, &permission); err != nil {
+			return fmt.Errorf("DATA LAYER: storage.postgres.loadRoles: %w", err)
+		}
+		if !user.HasRole(role) {
+			user.Roles = append(user.Roles, role)
+		}
+		if permission.Valid && !user.HasPermission(permission.String) {
+			user.Permissions = append(user.Permissions, permission.String)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.loadRoles: %w", err)
+	}
+	user.IsAdmin = user.HasRole(domain.RoleAdmin)
+	return nil
+}
@@ -0,0 +1,151 @@
+package patroni
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GetSigningKeys returns active and retiring signing keys with decrypted
+// private keys.
+func (s *Storage) GetSigningKeys(ctx context.Context) ([]domain.SigningKey, error) {
+	ctx, span := tracer.Start(ctx, "data layer Patroni: GetSigningKeys",
+		trace.WithAttributes(attribute.String("handler", "GetSigningKeys")))
+	defer span.End()
+
+	query := "SELECT kid, algorithm, private_key_encrypted, is_active, expires_at FROM signing_keys;"
+	rows, err := s.dbRead.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.GetSigningKeys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []domain.SigningKey
+	for rows.Next() {
+		var key domain.SigningKey
+		var expiresAt sql.NullTime
+		var encrypted []byte
+		err = rows.Scan(&key.ID, &key.Algorithm, &encrypted, &key.Active, &expiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("DATA LAYER: storage.postgres.GetSigningKeys: %w", err)
+		}
+		privateKey, err := s.cipher.Decrypt(encrypted)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"DATA LAYER: storage.postgres.GetSigningKeys: couldn't decrypt key %s %w", key.ID, err,
+			)
+		}
+		key.PrivateKey = string(privateKey)
+		key.ExpiresAt = expiresAt.Time
+		keys = append(keys, key)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("DATA LAYER: storage.postgres.GetSigningKeys: %w", err)
+	}
+	return keys, nil
+}
+
+// RotateSigningKey saves new active key and schedules removal of the previous one.
+func (s *Storage) RotateSigningKey(
+	ctx context.Context,
+	active domain.SigningKey,
+	retiring domain.SigningKey,
+) error {
+	ctx, span := tracer.Start(ctx, "data layer Patroni: RotateSigningKey",
+		trace.WithAttributes(attribute.String("handler", "RotateSigningKey")))
+	defer span.End()
+
+	tx, err := s.dbWrite.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.RotateSigningKey: failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	retiringEncrypted, err := s.cipher.Encrypt([]byte(retiring.PrivateKey))
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.RotateSigningKey: couldn't encrypt key %w", err)
+	}
+	activeEncrypted, err := s.cipher.Encrypt([]byte(active.PrivateKey))
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.RotateSigningKey: couldn't encrypt key %w", err)
+	}
+
+	// the previous key might come from config and be absent in db
+	query := `INSERT INTO signing_keys(kid, algorithm, private_key_encrypted, is_active, expires_at)
+		VALUES($1, $2, $3, FALSE, $4)
+		ON CONFLICT (kid) DO UPDATE SET is_active = FALSE, expires_at = EXCLUDED.expires_at;`
+	_, err = tx.ExecContext(
+		ctx, query, retiring.ID, retiring.Algorithm, retiringEncrypted, retiring.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.RotateSigningKey: couldn't retire key %w", err)
+	}
+	query = "INSERT INTO signing_keys(kid, algorithm, private_key_encrypted, is_active) VALUES($1, $2, $3, TRUE);"
+	_, err = tx.ExecContext(ctx, query, active.ID, active.Algorithm, activeEncrypted)
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.RotateSigningKey: couldn't save key %w", err)
+	}
+	return tx.Commit()
+}
+
+// DeleteExpiredSigningKeys removes retiring keys whose grace period has ended.
+func (s *Storage) DeleteExpiredSigningKeys(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "data layer Patroni: DeleteExpiredSigningKeys",
+		trace.WithAttributes(attribute.String("handler", "DeleteExpiredSigningKeys")))
+	defer span.End()
+
+	query := "DELETE FROM signing_keys WHERE NOT is_active AND expires_at < CURRENT_TIMESTAMP;"
+	_, err := s.dbWrite.ExecContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf(
+			"DATA LAYER: storage.postgres.DeleteExpiredSigningKeys: couldn't delete keys %w",
+			err,
+		)
+	}
+	return nil
+}
+
+// encryptSigningKeys encrypts private keys saved in plain text before they
+// were encrypted, plain text is removed.
+func (s *Storage) encryptSigningKeys(ctx context.Context) error {
+	tx, err := s.dbWrite.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.encryptSigningKeys: failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := "SELECT kid, private_key FROM signing_keys WHERE private_key IS NOT NULL FOR UPDATE;"
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.encryptSigningKeys: %w", err)
+	}
+	plain := make(map[string]string)
+	for rows.Next() {
+		var kid, privateKey string
+		if err = rows.Scan(&kid, &privateKey); err != nil {
+			rows.Close()
+			return fmt.Errorf("DATA LAYER: storage.postgres.encryptSigningKeys: %w", err)
+		}
+		plain[kid] = privateKey
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.encryptSigningKeys: %w", err)
+	}
+
+	query = "UPDATE signing_keys SET private_key_encrypted = $2, private_key = NULL WHERE kid = $1;"
+	for kid, privateKey := range plain {
+		encrypted, err := s.cipher.Encrypt([]byte(privateKey))
+		if err != nil {
+			return fmt.Errorf("DATA LAYER: storage.postgres.encryptSigningKeys: couldn't encrypt key %w", err)
+		}
+		if _, err = tx.ExecContext(ctx, query, kid, encrypted); err != nil {
+			return fmt.Errorf("DATA LAYER: storage.postgres.encryptSigningKeys: couldn't save key %w", err)
+		}
+	}
+	return tx.Commit()
+}
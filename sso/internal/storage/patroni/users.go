@@ -0,0 +1,131 @@
+package patroni
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/storage"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// BlockUser blocks user login until the time, until nil blocks user until
+// unblocked. Events are saved to outbox in the same transaction.
+func (s *Storage) BlockUser(
+	ctx context.Context,
+	uuid string,
+	reason string,
+	until *time.Time,
+	events ...domain.OutboxMessage,
+) error {
+	ctx, span := tracer.Start(ctx, "data layer Patroni: BlockUser",
+		trace.WithAttributes(attribute.String("handler", "BlockUser")))
+	defer span.End()
+
+	query := `UPDATE users SET blocked_at=CURRENT_TIMESTAMP, blocked_until=$2::timestamptz,
+		block_reason=$3, modified=CURRENT_TIMESTAMP WHERE uuid = $1;`
+	err := s.updateUser(ctx, query, []any{uuid, until, reason}, events)
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.BlockUser: %w", err)
+	}
+	return nil
+}
+
+// UnblockUser removes login block of the user, events are saved to outbox in
+// the same transaction.
+func (s *Storage) UnblockUser(
+	ctx context.Context,
+	uuid string,
+	events ...domain.OutboxMessage,
+) error {
+	ctx, span := tracer.Start(ctx, "data layer Patroni: UnblockUser",
+		trace.WithAttributes(attribute.String("handler", "UnblockUser")))
+	defer span.End()
+
+	query := `UPDATE users SET blocked_at=NULL, blocked_until=NULL, block_reason=NULL,
+		modified=CURRENT_TIMESTAMP WHERE uuid = $1;`
+	err := s.updateUser(ctx, query, []any{uuid}, events)
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.UnblockUser: %w", err)
+	}
+	return nil
+}
+
+// DeleteUser deletes user with roles and recovery codes, events are saved to
+// outbox in the same transaction.
+func (s *Storage) DeleteUser(
+	ctx context.Context,
+	uuid string,
+	events ...domain.OutboxMessage,
+) error {
+	ctx, span := tracer.Start(ctx, "data layer Patroni: DeleteUser",
+		trace.WithAttributes(attribute.String("handler", "DeleteUser")))
+	defer span.End()
+
+	query := "DELETE FROM users WHERE uuid = $1;"
+	err := s.updateUser(ctx, query, []any{uuid}, events)
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.DeleteUser: %w", err)
+	}
+	return nil
+}
+
+// UpdateEmail replaces user email, the new email is not verified. Events are
+// saved to outbox in the same transaction.
+func (s *Storage) UpdateEmail(
+	ctx context.Context,
+	uuid string,
+	email string,
+	events ...domain.OutboxMessage,
+) error {
+	ctx, span := tracer.Start(ctx, "data layer Patroni: UpdateEmail",
+		trace.WithAttributes(attribute.String("handler", "UpdateEmail")))
+	defer span.End()
+
+	query := `UPDATE users SET email=$2, verified_at=NULL, modified=CURRENT_TIMESTAMP
+		WHERE uuid = $1;`
+	err := s.updateUser(ctx, query, []any{uuid, email}, events)
+	var pgerr *pgconn.PgError
+	if errors.As(err, &pgerr) && pgerr.Code == UniqueViolation {
+		return fmt.Errorf("DATA LAYER: storage.postgres.UpdateEmail: %w", storage.ErrUserExists)
+	}
+	if err != nil {
+		return fmt.Errorf("DATA LAYER: storage.postgres.UpdateEmail: %w", err)
+	}
+	return nil
+}
+
+// updateUser executes query changing a single user and saves events to outbox
+// in the same transaction. ErrUserNotFound is returned if no user is changed.
+func (s *Storage) updateUser(
+	ctx context.Context,
+	query string,
+	args []any,
+	events []domain.OutboxMessage,
+) error {
+	tx, err := s.dbWrite.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return storage.ErrUserNotFound
+	}
+	if err = saveOutboxMessages(ctx, tx, events...); err != nil {
+		return fmt.Errorf("couldn't save events %w", err)
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,88 @@
+package redissentinel
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Failed login attempts are counted per subject (account or IP), subject is
+// locked out of login while lock key exists.
+const (
+	loginFailuresPrefix = "login_failures:"
+	loginLockPrefix     = "login_lock:"
+)
+
+// LoginLockTTL returns how long login is locked for the subject, zero if it is
+// not locked.
+func (s *Cache) LoginLockTTL(ctx context.Context, subject string) (time.Duration, error) {
+	const op = "DATA LAYER: storage.redis.LoginLockTTL"
+
+	ctx, span := tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("handler", "LoginLockTTL")))
+	defer span.End()
+
+	ttl, err := s.client.PTTL(ctx, loginLockPrefix+subject).Result()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	// negative values mean the key does not exist or has no ttl
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// AddLoginFailure increments failed login attempts of the subject and returns
+// their number. Attempts are forgotten after window passes since the last one.
+func (s *Cache) AddLoginFailure(
+	ctx context.Context,
+	subject string,
+	window time.Duration,
+) (int64, error) {
+	const op = "DATA LAYER: storage.redis.AddLoginFailure"
+
+	ctx, span := tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("handler", "AddLoginFailure")))
+	defer span.End()
+
+	pipe := s.client.TxPipeline()
+	incr := pipe.Incr(ctx, loginFailuresPrefix+subject)
+	pipe.PExpire(ctx, loginFailuresPrefix+subject, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return incr.Val(), nil
+}
+
+// LockLogin locks login of the subject for ttl.
+func (s *Cache) LockLogin(ctx context.Context, subject string, ttl time.Duration) error {
+	const op = "DATA LAYER: storage.redis.LockLogin"
+
+	ctx, span := tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("handler", "LockLogin")))
+	defer span.End()
+
+	if err := s.client.Set(ctx, loginLockPrefix+subject, 1, ttl).Err(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// ResetLoginFailures removes failed login attempts and lock of the subject.
+func (s *Cache) ResetLoginFailures(ctx context.Context, subject string) error {
+	const op = "DATA LAYER: storage.redis.ResetLoginFailures"
+
+	ctx, span := tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("handler", "ResetLoginFailures")))
+	defer span.End()
+
+	err := s.client.Del(ctx, loginFailuresPrefix+subject, loginLockPrefix+subject).Err()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
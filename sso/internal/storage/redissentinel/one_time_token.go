@@ -0,0 +1,176 @@
+package redissentinel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/sso/internal/storage"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// One-time tokens are sent to user email, only their hashes are stored.
+const (
+	passwordResetPrefix     = "password_reset:"
+	emailVerificationPrefix = "email_verification:"
+	mfaChallengePrefix      = "mfa_challenge:"
+	// resend key exists while verification email can't be requested again
+	emailVerificationResendPrefix = "email_verification_resend:"
+)
+
+// SavePasswordResetToken saves hash of password reset token issued to the user.
+func (s *Cache) SavePasswordResetToken(
+	ctx context.Context,
+	tokenHash string,
+	userID string,
+	ttl time.Duration,
+) error {
+	const op = "DATA LAYER: storage.redis.SavePasswordResetToken"
+
+	ctx, span := tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("handler", "SavePasswordResetToken")))
+	defer span.End()
+
+	if err := s.saveOneTimeToken(ctx, passwordResetPrefix+tokenHash, userID, ttl); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// ConsumePasswordResetToken returns id of the user the token was issued to and
+// removes the token, so it can be used only once.
+func (s *Cache) ConsumePasswordResetToken(
+	ctx context.Context,
+	tokenHash string,
+) (string, error) {
+	const op = "DATA LAYER: storage.redis.ConsumePasswordResetToken"
+
+	ctx, span := tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("handler", "ConsumePasswordResetToken")))
+	defer span.End()
+
+	userID, err := s.consumeOneTimeToken(ctx, passwordResetPrefix+tokenHash)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	return userID, nil
+}
+
+// SaveEmailVerificationToken saves hash of email verification token issued to the user.
+func (s *Cache) SaveEmailVerificationToken(
+	ctx context.Context,
+	tokenHash string,
+	userID string,
+	ttl time.Duration,
+) error {
+	const op = "DATA LAYER: storage.redis.SaveEmailVerificationToken"
+
+	ctx, span := tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("handler", "SaveEmailVerificationToken")))
+	defer span.End()
+
+	if err := s.saveOneTimeToken(ctx, emailVerificationPrefix+tokenHash, userID, ttl); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// ConsumeEmailVerificationToken returns id of the user the token was issued to
+// and removes the token, so it can be used only once.
+func (s *Cache) ConsumeEmailVerificationToken(
+	ctx context.Context,
+	tokenHash string,
+) (string, error) {
+	const op = "DATA LAYER: storage.redis.ConsumeEmailVerificationToken"
+
+	ctx, span := tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("handler", "ConsumeEmailVerificationToken")))
+	defer span.End()
+
+	userID, err := s.consumeOneTimeToken(ctx, emailVerificationPrefix+tokenHash)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	return userID, nil
+}
+
+// LockEmailVerificationResend locks resending verification email to the user
+// for ttl. False is returned if it's already locked.
+func (s *Cache) LockEmailVerificationResend(
+	ctx context.Context,
+	userID string,
+	ttl time.Duration,
+) (bool, error) {
+	const op = "DATA LAYER: storage.redis.LockEmailVerificationResend"
+
+	ctx, span := tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("handler", "LockEmailVerificationResend")))
+	defer span.End()
+
+	locked, err := s.client.SetNX(ctx, emailVerificationResendPrefix+userID, 1, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return locked, nil
+}
+
+// SaveMFAChallenge saves hash of MFA challenge token issued on login.
+func (s *Cache) SaveMFAChallenge(
+	ctx context.Context,
+	tokenHash string,
+	userID string,
+	ttl time.Duration,
+) error {
+	const op = "DATA LAYER: storage.redis.SaveMFAChallenge"
+
+	ctx, span := tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("handler", "SaveMFAChallenge")))
+	defer span.End()
+
+	if err := s.saveOneTimeToken(ctx, mfaChallengePrefix+tokenHash, userID, ttl); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// ConsumeMFAChallenge returns id of the user the challenge was issued to and
+// removes the challenge, so only one attempt to complete login can be made.
+func (s *Cache) ConsumeMFAChallenge(
+	ctx context.Context,
+	tokenHash string,
+) (string, error) {
+	const op = "DATA LAYER: storage.redis.ConsumeMFAChallenge"
+
+	ctx, span := tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("handler", "ConsumeMFAChallenge")))
+	defer span.End()
+
+	userID, err := s.consumeOneTimeToken(ctx, mfaChallengePrefix+tokenHash)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	return userID, nil
+}
+
+func (s *Cache) saveOneTimeToken(
+	ctx context.Context,
+	key string,
+	userID string,
+	ttl time.Duration,
+) error {
+	return s.client.Set(ctx, key, userID, ttl).Err()
+}
+
+func (s *Cache) consumeOneTimeToken(ctx context.Context, key string) (string, error) {
+	userID, err := s.client.GetDel(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", storage.ErrOneTimeTokenNotFound
+		}
+		return "", err
+	}
+	return userID, nil
+}
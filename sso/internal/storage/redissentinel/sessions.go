@@ -0,0 +1,156 @@
+package redissentinel
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/storage"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Session is stored as a hash, ids of user sessions are stored in a set.
+// Both expire together with the last refresh token of the session.
+const (
+	sessionPrefix      = "session:"
+	userSessionsPrefix = "user_sessions:"
+)
+
+// SaveSession saves new session and adds it to user sessions.
+func (s *Cache) SaveSession(
+	ctx context.Context,
+	session domain.Session,
+	ttl time.Duration,
+) error {
+	const op = "DATA LAYER: storage.redis.SaveSession"
+
+	ctx, span := tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("handler", "SaveSession")))
+	defer span.End()
+
+	key := sessionPrefix + session.ID
+	userKey := userSessionsPrefix + session.UserID
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, key, map[string]any{
+		"user_id":      session.UserID,
+		"user_agent":   session.UserAgent,
+		"ip":           session.IP,
+		"created_at":   session.CreatedAt.Unix(),
+		"refreshed_at": session.RefreshedAt.Unix(),
+	})
+	pipe.Expire(ctx, key, ttl)
+	pipe.SAdd(ctx, userKey, session.ID)
+	pipe.Expire(ctx, userKey, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// RefreshSession updates last refresh time and address of the session.
+func (s *Cache) RefreshSession(
+	ctx context.Context,
+	session domain.Session,
+	ttl time.Duration,
+) error {
+	const op = "DATA LAYER: storage.redis.RefreshSession"
+
+	ctx, span := tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("handler", "RefreshSession")))
+	defer span.End()
+
+	key := sessionPrefix + session.ID
+	exists, err := s.client.Exists(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if exists == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrSessionNotFound)
+	}
+	userKey := userSessionsPrefix + session.UserID
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, key, map[string]any{
+		"user_agent":   session.UserAgent,
+		"ip":           session.IP,
+		"refreshed_at": session.RefreshedAt.Unix(),
+	})
+	pipe.Expire(ctx, key, ttl)
+	pipe.Expire(ctx, userKey, ttl)
+	if _, err = pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// GetSessions returns active sessions of the user.
+func (s *Cache) GetSessions(
+	ctx context.Context,
+	userID string,
+) ([]domain.Session, error) {
+	const op = "DATA LAYER: storage.redis.GetSessions"
+
+	ctx, span := tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("handler", "GetSessions")))
+	defer span.End()
+
+	userKey := userSessionsPrefix + userID
+	ids, err := s.client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	sessions := make([]domain.Session, 0, len(ids))
+	for _, id := range ids {
+		fields, err := s.client.HGetAll(ctx, sessionPrefix+id).Result()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if len(fields) == 0 {
+			// session expired, but user set is kept alive by other sessions
+			s.client.SRem(ctx, userKey, id)
+			continue
+		}
+		sessions = append(sessions, sessionFromHash(id, fields))
+	}
+	return sessions, nil
+}
+
+// DeleteSession removes session of the user.
+func (s *Cache) DeleteSession(
+	ctx context.Context,
+	userID string,
+	sessionID string,
+) error {
+	const op = "DATA LAYER: storage.redis.DeleteSession"
+
+	ctx, span := tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("handler", "DeleteSession")))
+	defer span.End()
+
+	removed, err := s.client.SRem(ctx, userSessionsPrefix+userID, sessionID).Result()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if removed == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrSessionNotFound)
+	}
+	if err = s.client.Del(ctx, sessionPrefix+sessionID).Err(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func sessionFromHash(id string, fields map[string]string) domain.Session {
+	createdAt, _ := strconv.ParseInt(fields["created_at"], 10, 64)
+	refreshedAt, _ := strconv.ParseInt(fields["refreshed_at"], 10, 64)
+	return domain.Session{
+		ID:          id,
+		UserID:      fields["user_id"],
+		UserAgent:   fields["user_agent"],
+		IP:          fields["ip"],
+		CreatedAt:   time.Unix(createdAt, 0),
+		RefreshedAt: time.Unix(refreshedAt, 0),
+	}
+}
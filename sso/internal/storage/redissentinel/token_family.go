@@ -0,0 +1,128 @@
+package redissentinel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/sso/internal/storage"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Token family key stores jti of the last refresh token issued in the family
+// or revokedFamily marker after reuse was detected.
+const (
+	tokenFamilyPrefix = "token_family:"
+	revokedFamily     = "revoked"
+)
+
+// rotateFamilyScript replaces current jti only if presented token is the last
+// issued one, so two refreshes with the same token can't both succeed.
+var rotateFamilyScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if not current or current == ARGV[3] then
+	return -1
+end
+if current ~= ARGV[1] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[4])
+return 1
+`)
+
+// SaveTokenFamily starts new token family with the refresh token id.
+func (s *Cache) SaveTokenFamily(
+	ctx context.Context,
+	familyID string,
+	tokenID string,
+	ttl time.Duration,
+) error {
+	const op = "DATA LAYER: storage.redis.SaveTokenFamily"
+
+	ctx, span := tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("handler", "SaveTokenFamily")))
+	defer span.End()
+
+	err := s.client.Set(ctx, tokenFamilyPrefix+familyID, tokenID, ttl).Err()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// RotateTokenFamily replaces last refresh token id of the family. Returns
+// storage.ErrTokenReused if the token is not the last issued one and
+// storage.ErrTokenFamilyNotFound if the family expired or was revoked.
+func (s *Cache) RotateTokenFamily(
+	ctx context.Context,
+	familyID string,
+	tokenID string,
+	nextTokenID string,
+	ttl time.Duration,
+) error {
+	const op = "DATA LAYER: storage.redis.RotateTokenFamily"
+
+	ctx, span := tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("handler", "RotateTokenFamily")))
+	defer span.End()
+
+	result, err := rotateFamilyScript.Run(
+		ctx,
+		s.client,
+		[]string{tokenFamilyPrefix + familyID},
+		tokenID, nextTokenID, revokedFamily, ttl.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	switch result {
+	case -1:
+		return fmt.Errorf("%s: %w", op, storage.ErrTokenFamilyNotFound)
+	case 0:
+		return fmt.Errorf("%s: %w", op, storage.ErrTokenReused)
+	}
+	return nil
+}
+
+// RevokeTokenFamily marks family as revoked, so every token of the family is rejected.
+func (s *Cache) RevokeTokenFamily(
+	ctx context.Context,
+	familyID string,
+	ttl time.Duration,
+) error {
+	const op = "DATA LAYER: storage.redis.RevokeTokenFamily"
+
+	ctx, span := tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("handler", "RevokeTokenFamily")))
+	defer span.End()
+
+	err := s.client.Set(ctx, tokenFamilyPrefix+familyID, revokedFamily, ttl).Err()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// CheckTokenFamilyRevoked checks if family was revoked.
+func (s *Cache) CheckTokenFamilyRevoked(
+	ctx context.Context,
+	familyID string,
+) (bool, error) {
+	const op = "DATA LAYER: storage.redis.CheckTokenFamilyRevoked"
+
+	ctx, span := tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("handler", "CheckTokenFamilyRevoked")))
+	defer span.End()
+
+	val, err := s.client.Get(ctx, tokenFamilyPrefix+familyID).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return false, nil
+		}
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return val == revokedFamily, nil
+}
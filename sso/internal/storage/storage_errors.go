@@ -8,4 +8,17 @@ var (
 	ErrAppNotFound    = errors.New("app not found")
 	ErrWrongParamType = errors.New("wrong param type")
 	ErrConnection     = errors.New("no connection")
+	ErrRoleNotFound   = errors.New("role not found")
+	ErrRoleNotGranted = errors.New("role not granted")
+	// ErrTokenReused means refresh token was already exchanged for a new one.
+	ErrTokenReused          = errors.New("token reused")
+	ErrTokenFamilyNotFound  = errors.New("token family not found")
+	ErrSessionNotFound      = errors.New("session not found")
+	ErrOneTimeTokenNotFound = errors.New("one-time token not found")
+	ErrMFANotEnrolled       = errors.New("mfa not enrolled")
+	ErrMFAEnabled           = errors.New("mfa already enabled")
+	ErrRecoveryCodeNotFound = errors.New("recovery code not found")
+	// ErrTOTPStepUsed means a TOTP code of this or a later time step was
+	// already accepted.
+	ErrTOTPStepUsed = errors.New("totp code already used")
 )
@@ -28,7 +28,9 @@ type Broker struct {
 
 type Response struct {
 	UserUUID string
-	Err      error
+	// Topic lets to tell registration messages from notifications.
+	Topic string
+	Err   error
 }
 
 var FlushBrokerTimeMs = 100
@@ -69,15 +71,21 @@ func New(cfg *config.Config) (*Broker, error) {
 				// permanent failure after retries have been exhausted.
 				// Application level retries won't help since the client
 				// is already configured to do that.
+				var topic string
+				if e.TopicPartition.Topic != nil {
+					topic = *e.TopicPartition.Topic
+				}
 				if e.TopicPartition.Error != nil {
 					kafkaResponseChan <- &Response{
 						UserUUID: string(e.Key),
+						Topic:    topic,
 						Err:      e.TopicPartition.Error,
 					}
 					continue
 				}
 				kafkaResponseChan <- &Response{
 					UserUUID: string(e.Key),
+					Topic:    topic,
 					Err:      nil,
 				}
 			case kafka.Error:
@@ -123,8 +131,37 @@ func (b *Broker) GetResponseChan() chan *Response {
 	return b.ResponseChan
 }
 
-// Send sends serialized message to kafka using schema registry
+// Send sends serialized message to kafka using schema registry. Delivery
+// status is returned by the channel from GetResponseChan.
 func (b *Broker) Send(ctx context.Context, msg proto.Message, topic string, key string) error {
+	return b.produce(ctx, msg, topic, key, nil)
+}
+
+// SendSync sends serialized message to kafka using schema registry and waits
+// until the message is delivered or ctx is done.
+func (b *Broker) SendSync(ctx context.Context, msg proto.Message, topic string, key string) error {
+	deliveryChan := make(chan kafka.Event, 1)
+	if err := b.produce(ctx, msg, topic, key, deliveryChan); err != nil {
+		return err
+	}
+	select {
+	case e := <-deliveryChan:
+		if m, ok := e.(*kafka.Message); ok && m.TopicPartition.Error != nil {
+			return m.TopicPartition.Error
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *Broker) produce(
+	ctx context.Context,
+	msg proto.Message,
+	topic string,
+	key string,
+	deliveryChan chan kafka.Event,
+) error {
 	ctx, span := tracer.Start(
 		ctx, "transfer layer Kafka: Serialize message",
 		trace.WithAttributes(attribute.String("transfer transfer", "Send")),
@@ -150,7 +187,7 @@ func (b *Broker) Send(ctx context.Context, msg proto.Message, topic string, key
 		TopicPartition: kafka.TopicPartition{Topic: &topic},
 		Value:          payload,
 		Headers:        headers,
-	}, nil); err != nil {
+	}, deliveryChan); err != nil {
 		return err
 	}
 	return nil
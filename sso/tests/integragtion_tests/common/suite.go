@@ -11,6 +11,7 @@ import (
 
 	ssov1 "github.com/AlexBlackNn/authloyalty/commands/proto/sso/gen"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/config"
+	jwtlib "github.com/AlexBlackNn/authloyalty/sso/internal/lib/jwt"
 	"github.com/AlexBlackNn/authloyalty/sso/pkg/tracing"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/otel"
@@ -24,9 +25,10 @@ const (
 )
 
 type Suite struct {
-	*testing.T                  // потребуется для вызова методов *testing.T внутри Suite
-	Cfg        *config.Config   // Конфигурация приложения
-	AuthClient ssov1.AuthClient // Клиент для взаимодействия с grpc_transport - сервером
+	*testing.T                    // потребуется для вызова методов *testing.T внутри Suite
+	Cfg        *config.Config     // Конфигурация приложения
+	AuthClient ssov1.AuthClient   // Клиент для взаимодействия с grpc_transport - сервером
+	SigningKey *jwtlib.SigningKey // Ключ, которым сервер подписывает токены
 }
 
 var tracer = otel.Tracer("testing client")
@@ -78,10 +80,16 @@ func New(t *testing.T) (context.Context, *Suite) {
 		t.Fatalf("grpc_transport server connection failed: %v", err)
 	}
 
+	signingKey, err := jwtlib.NewSigningKey(cfg.JWT.KeyID, cfg.JWT.Algorithm, cfg.JWT.PrivateKey)
+	if err != nil {
+		t.Fatalf("signing key parsing failed: %v", err)
+	}
+
 	return ctx, &Suite{
 		T:          t,
 		Cfg:        cfg,
 		AuthClient: ssov1.NewAuthClient(cc),
+		SigningKey: signingKey,
 	}
 }
 
@@ -25,7 +25,7 @@ func TestLoginHappyPath(t *testing.T) {
 	token := respLogin.GetAccessToken()
 
 	tokenParsed, err := jwt.Parse(token, func(token *jwt.Token) (any, error) {
-		return []byte(testCommon.Cfg.ServiceSecret), nil
+		return testCommon.SigningKey.PrivateKey.Public(), nil
 	})
 	require.NoError(t, err)
 
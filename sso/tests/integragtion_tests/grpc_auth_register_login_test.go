@@ -31,7 +31,7 @@ func TestRegisterLoginHappyPath(t *testing.T) {
 
 	token := respLogin.GetAccessToken()
 	tokenParsed, err := jwt.Parse(token, func(token *jwt.Token) (any, error) {
-		return []byte(testCommon.Cfg.ServiceSecret), nil
+		return testCommon.SigningKey.PrivateKey.Public(), nil
 	})
 	require.NoError(t, err)
 
@@ -0,0 +1,63 @@
+package integragtion_tests
+
+import (
+	"testing"
+
+	ssov1 "github.com/AlexBlackNn/authloyalty/commands/proto/sso/gen"
+	"github.com/AlexBlackNn/authloyalty/sso/tests/integragtion_tests/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	adminID = "22f23689-9b67-4ef9-a693-5ef2d18ee111"
+	userID  = "7c2ab9ec-bddf-43ff-96a5-ff1e0785c909"
+)
+
+func TestGrantRevokeRoleHappyPath(t *testing.T) {
+	ctx, testCommon := common.New(t)
+	respLogin, err := testCommon.AuthClient.Login(ctx, &ssov1.LoginRequest{
+		Email:    "admin@test.com",
+		Password: "test",
+	})
+	require.NoError(t, err)
+
+	_, err = testCommon.AuthClient.GrantRole(ctx, &ssov1.GrantRoleRequest{
+		Token:  respLogin.GetAccessToken(),
+		UserId: userID,
+		Role:   "admin",
+	})
+	require.NoError(t, err)
+	respIsAdmin, err := testCommon.AuthClient.IsAdmin(ctx, &ssov1.IsAdminRequest{UserId: userID})
+	require.NoError(t, err)
+	assert.Equal(t, true, respIsAdmin.GetIsAdmin())
+
+	_, err = testCommon.AuthClient.RevokeRole(ctx, &ssov1.RevokeRoleRequest{
+		Token:  respLogin.GetAccessToken(),
+		UserId: userID,
+		Role:   "admin",
+	})
+	require.NoError(t, err)
+	respIsAdmin, err = testCommon.AuthClient.IsAdmin(ctx, &ssov1.IsAdminRequest{UserId: userID})
+	require.NoError(t, err)
+	assert.Equal(t, false, respIsAdmin.GetIsAdmin())
+}
+
+func TestGrantRoleForbiddenForUser(t *testing.T) {
+	ctx, testCommon := common.New(t)
+	respLogin, err := testCommon.AuthClient.Login(ctx, &ssov1.LoginRequest{
+		Email:    "user@test.com",
+		Password: "test",
+	})
+	require.NoError(t, err)
+
+	_, err = testCommon.AuthClient.GrantRole(ctx, &ssov1.GrantRoleRequest{
+		Token:  respLogin.GetAccessToken(),
+		UserId: userID,
+		Role:   "admin",
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
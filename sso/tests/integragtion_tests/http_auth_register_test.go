@@ -12,8 +12,10 @@ import (
 	"github.com/AlexBlackNn/authloyalty/sso/cmd/sso/router"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/config"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/dto"
+	jwtlib "github.com/AlexBlackNn/authloyalty/sso/internal/lib/jwt"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/logger"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/services/authservice"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/storage/objectstorage"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/storage/patroni"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/storage/redissentinel"
 	"github.com/AlexBlackNn/authloyalty/sso/pkg/broker"
@@ -45,12 +47,21 @@ func (ms *AuthSuite) SetupSuite() {
 	producer, err := broker.New(cfg)
 	ms.Suite.NoError(err)
 
+	objStorage, err := objectstorage.New(cfg)
+	ms.Suite.NoError(err)
+
+	signingKey, err := jwtlib.NewSigningKey(cfg.JWT.KeyID, cfg.JWT.Algorithm, cfg.JWT.PrivateKey)
+	ms.Suite.NoError(err)
+
 	authService := authservice.New(
 		cfg,
 		log,
 		userStorage,
 		tokenStorage,
 		producer,
+		objStorage,
+		userStorage,
+		jwtlib.NewKeyring(signingKey),
 	)
 
 	// http server
@@ -66,6 +77,7 @@ func (ms *AuthSuite) BeforeTest(suiteName, testName string) {
 		ms.application.Log,
 		ms.application.HandlersV1,
 		ms.application.HealthChecker,
+		ms.application.GatewayV2,
 	))
 }
 
@@ -127,8 +139,14 @@ func (ms *AuthSuite) TestHttpServerRegisterHappyPath() {
 		ms.NoError(err)
 		ms.Equal(test.want.response.Status, response.Status)
 
+		signingKey, err := jwtlib.NewSigningKey(
+			ms.application.Cfg.JWT.KeyID,
+			ms.application.Cfg.JWT.Algorithm,
+			ms.application.Cfg.JWT.PrivateKey,
+		)
+		ms.NoError(err)
 		tokenParsed, err := jwt.Parse(response.AccessToken, func(token *jwt.Token) (any, error) {
-			return []byte(ms.application.Cfg.ServiceSecret), nil
+			return signingKey.PrivateKey.Public(), nil
 		})
 		ms.NoError(err)
 
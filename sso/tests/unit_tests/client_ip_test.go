@@ -0,0 +1,182 @@
+package unit_tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/sso/app/serverhttp"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/config"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/lib/clientip"
+	jwtlib "github.com/AlexBlackNn/authloyalty/sso/internal/lib/jwt"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/logger"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/services/authservice"
+	"github.com/AlexBlackNn/authloyalty/sso/pkg/broker"
+	"github.com/AlexBlackNn/authloyalty/sso/tests/unit_tests/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestClientIP(t *testing.T) {
+	proxies, err := clientip.NewTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		peer         string
+		forwardedFor []string
+		ip           string
+	}{
+		{
+			name: "no proxy",
+			peer: "203.0.113.7:5555",
+			ip:   "203.0.113.7",
+		},
+		{
+			name:         "header of untrusted peer is ignored",
+			peer:         "203.0.113.7:5555",
+			forwardedFor: []string{"198.51.100.1"},
+			ip:           "203.0.113.7",
+		},
+		{
+			name:         "trusted proxy",
+			peer:         "10.1.2.3:5555",
+			forwardedFor: []string{"198.51.100.1"},
+			ip:           "198.51.100.1",
+		},
+		{
+			name:         "address set by client before trusted proxies is ignored",
+			peer:         "192.168.1.1:5555",
+			forwardedFor: []string{"1.1.1.1, 198.51.100.1", "10.0.0.5"},
+			ip:           "198.51.100.1",
+		},
+		{
+			name: "trusted proxy without header",
+			peer: "10.1.2.3:5555",
+			ip:   "10.1.2.3",
+		},
+		{
+			name: "peer without port",
+			peer: "203.0.113.7",
+			ip:   "203.0.113.7",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.ip, proxies.ClientIP(tt.peer, tt.forwardedFor...))
+		})
+	}
+
+	// zero value trusts no proxies
+	require.Equal(t, "10.1.2.3", clientip.TrustedProxies{}.ClientIP("10.1.2.3:5555", "198.51.100.1"))
+
+	_, err = clientip.NewTrustedProxies([]string{"not-an-ip"})
+	require.Error(t, err)
+}
+
+func TestSessionClientIP(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	passHash, err := bcrypt.GenerateFromPassword([]byte("test"), bcrypt.DefaultCost)
+	require.NoError(t, err)
+	userStorageMock := mocks.NewMockuserStorage(ctrl)
+	userStorageMock.EXPECT().
+		GetUserByEmail(gomock.Any(), gomock.Any()).
+		Return(domain.User{ID: "79d3ac44-5857-4185-ba92-1a224fbacb51", Email: "test@test.com", PassHash: passHash}, nil).
+		AnyTimes()
+
+	brokerMock := mocks.NewMockgetResponseChanSender(ctrl)
+	brokerMock.EXPECT().
+		GetResponseChan().
+		Return(make(chan *broker.Response)).
+		AnyTimes()
+
+	keyStorageMock := mocks.NewMockkeyStorage(ctrl)
+	keyStorageMock.EXPECT().
+		GetSigningKeys(gomock.Any()).
+		Return(nil, nil).
+		AnyTimes()
+	keyStorageMock.EXPECT().
+		DeleteExpiredSigningKeys(gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
+	// login throttling and session are keyed by client ip
+	var sessionIP string
+	tokenStorageMock := mocks.NewMocktokenStorage(ctrl)
+	tokenStorageMock.EXPECT().
+		LoginLockTTL(gomock.Any(), gomock.Any()).
+		Return(time.Duration(0), nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		ResetLoginFailures(gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		SaveTokenFamily(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		SaveSession(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, session domain.Session, _ time.Duration) error {
+			sessionIP = session.IP
+			return nil
+		}).
+		AnyTimes()
+
+	signingKey, err := jwtlib.NewSigningKey(cfg.JWT.KeyID, cfg.JWT.Algorithm, cfg.JWT.PrivateKey)
+	require.NoError(t, err)
+	authService := authservice.New(
+		cfg,
+		log,
+		userStorageMock,
+		tokenStorageMock,
+		brokerMock,
+		mocks.NewMockobjectStorage(ctrl),
+		keyStorageMock,
+		jwtlib.NewKeyring(signingKey),
+	)
+
+	login := func(t *testing.T, url string, trustedProxies []string) string {
+		cfg.TrustedProxies = trustedProxies
+		application, err := serverhttp.New(cfg, log, authService)
+		require.NoError(t, err)
+		srv := httptest.NewServer(application.Srv.Handler)
+		defer srv.Close()
+
+		req, err := http.NewRequest(
+			http.MethodPost,
+			srv.URL+url,
+			strings.NewReader(`{"email":"test@test.com","password":"test"}`),
+		)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", "198.51.100.1")
+		req.Header.Set("X-Real-IP", "198.51.100.2")
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		// v1 api responds with 201, v2 api with 200
+		require.Less(t, res.StatusCode, 300)
+		return sessionIP
+	}
+
+	for _, url := range []string{"/auth/login", "/api/v2/auth/login"} {
+		t.Run(url, func(t *testing.T) {
+			// test server listens on loopback, headers of untrusted peer are ignored
+			require.Equal(t, "127.0.0.1", login(t, url, nil))
+		})
+	}
+	t.Run("trusted proxy", func(t *testing.T) {
+		require.Equal(t, "198.51.100.2", login(t, "/auth/login", []string{"127.0.0.1"}))
+		require.Equal(t, "198.51.100.1", login(t, "/api/v2/auth/login", []string{"127.0.0.1"}))
+	})
+}
@@ -0,0 +1,230 @@
+package unit_tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	notificationv1 "github.com/AlexBlackNn/authloyalty/commands/proto/notification.v1/notification.v1"
+	registrationv1 "github.com/AlexBlackNn/authloyalty/commands/proto/registration.v1/registration.v1"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/config"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/dto"
+	jwtlib "github.com/AlexBlackNn/authloyalty/sso/internal/lib/jwt"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/logger"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/services/authservice"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/storage"
+	"github.com/AlexBlackNn/authloyalty/sso/pkg/broker"
+	"github.com/AlexBlackNn/authloyalty/sso/tests/unit_tests/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestEmailVerificationRequired(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	cfg.EmailVerification.AllowUnverifiedLogin = false
+	cfg.EmailVerification.HoldRegistrationBonus = true
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	passHash, err := bcrypt.GenerateFromPassword([]byte("test"), bcrypt.DefaultCost)
+	require.NoError(t, err)
+	user := domain.User{
+		ID:       "79d3ac44-5857-4185-ba92-1a224fbacb51",
+		Email:    "test@test.com",
+		PassHash: passHash,
+	}
+
+	userStorageMock := mocks.NewMockuserStorage(ctrl)
+	userStorageMock.EXPECT().
+		SaveUser(gomock.Any(), user.Email, gomock.Any(), gomock.Nil()).
+		Return(user.ID, nil)
+	userStorageMock.EXPECT().
+		GetUserByEmail(gomock.Any(), user.Email).
+		Return(user, nil)
+	userStorageMock.EXPECT().
+		VerifyEmail(gomock.Any(), user.ID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, events ...domain.OutboxMessage) error {
+			// registration message is saved only after email is verified
+			require.Len(t, events, 1)
+			require.Equal(t, cfg.Kafka.Topic, events[0].Topic)
+			require.Equal(t, user.ID, events[0].Key)
+			var msg registrationv1.RegistrationMessage
+			require.NoError(t, proto.Unmarshal(events[0].Payload, &msg))
+			require.Equal(t, user.ID, msg.GetUuid())
+			return nil
+		})
+
+	objectStorageMock := mocks.NewMockobjectStorage(ctrl)
+	objectStorageMock.EXPECT().
+		UploadData(gomock.Any(), gomock.Any()).
+		Return("", nil)
+
+	var verificationToken string
+	brokerMock := mocks.NewMockgetResponseChanSender(ctrl)
+	brokerMock.EXPECT().
+		GetResponseChan().
+		Return(make(chan *broker.Response)).
+		AnyTimes()
+	brokerMock.EXPECT().
+		Send(gomock.Any(), gomock.Any(), cfg.Kafka.EmailVerificationTopic, user.ID).
+		DoAndReturn(func(_ context.Context, msg proto.Message, _, _ string) error {
+			verificationToken = msg.(*notificationv1.EmailVerificationMessage).GetToken()
+			return nil
+		})
+
+	keyStorageMock := mocks.NewMockkeyStorage(ctrl)
+	keyStorageMock.EXPECT().
+		GetSigningKeys(gomock.Any()).
+		Return(nil, nil).
+		AnyTimes()
+	keyStorageMock.EXPECT().
+		DeleteExpiredSigningKeys(gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
+	var savedHash string
+	tokenStorageMock := mocks.NewMocktokenStorage(ctrl)
+	tokenStorageMock.EXPECT().
+		LoginLockTTL(gomock.Any(), gomock.Any()).
+		Return(time.Duration(0), nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		ResetLoginFailures(gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		SaveEmailVerificationToken(gomock.Any(), gomock.Any(), user.ID, cfg.EmailVerification.TokenTtl).
+		DoAndReturn(func(_ context.Context, tokenHash, _ string, _ any) error {
+			savedHash = tokenHash
+			return nil
+		})
+	gomock.InOrder(
+		tokenStorageMock.EXPECT().
+			ConsumeEmailVerificationToken(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, tokenHash string) (string, error) {
+				require.Equal(t, savedHash, tokenHash)
+				return user.ID, nil
+			}),
+		tokenStorageMock.EXPECT().
+			ConsumeEmailVerificationToken(gomock.Any(), gomock.Any()).
+			Return("", storage.ErrOneTimeTokenNotFound),
+	)
+
+	signingKey, err := jwtlib.NewSigningKey(cfg.JWT.KeyID, cfg.JWT.Algorithm, cfg.JWT.PrivateKey)
+	require.NoError(t, err)
+	authService := authservice.New(
+		cfg,
+		log,
+		userStorageMock,
+		tokenStorageMock,
+		brokerMock,
+		objectStorageMock,
+		keyStorageMock,
+		jwtlib.NewKeyring(signingKey),
+	)
+
+	ctx := context.Background()
+	_, registered, err := authService.Register(
+		ctx, &dto.Register{Email: user.Email, Password: "test"},
+	)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, registered.ID)
+	require.Empty(t, registered.AccessToken)
+	require.Empty(t, registered.RefreshToken)
+
+	_, err = authService.Login(ctx, &dto.Login{Email: user.Email, Password: "test"})
+	require.ErrorIs(t, err, authservice.ErrEmailNotVerified)
+
+	_, err = authService.VerifyEmail(ctx, &dto.VerifyEmail{Token: verificationToken})
+	require.NoError(t, err)
+
+	// verification token is single-use
+	_, err = authService.VerifyEmail(ctx, &dto.VerifyEmail{Token: verificationToken})
+	require.ErrorIs(t, err, authservice.ErrVerificationTokenInvalid)
+}
+
+func TestResendEmailVerification(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	verifiedAt := time.Now()
+	user := domain.User{ID: "79d3ac44-5857-4185-ba92-1a224fbacb51", Email: "test@test.com"}
+	verified := domain.User{
+		ID:         "5b0b7b2a-1d5e-4c4f-9d8a-2f7c1e3b4a6d",
+		Email:      "verified@test.com",
+		VerifiedAt: &verifiedAt,
+	}
+
+	userStorageMock := mocks.NewMockuserStorage(ctrl)
+	userStorageMock.EXPECT().
+		GetUserByEmail(gomock.Any(), user.Email).
+		Return(user, nil).
+		Times(2)
+	userStorageMock.EXPECT().
+		GetUserByEmail(gomock.Any(), verified.Email).
+		Return(verified, nil)
+	userStorageMock.EXPECT().
+		GetUserByEmail(gomock.Any(), "unknown@test.com").
+		Return(domain.User{}, storage.ErrUserNotFound)
+
+	brokerMock := mocks.NewMockgetResponseChanSender(ctrl)
+	brokerMock.EXPECT().
+		GetResponseChan().
+		Return(make(chan *broker.Response)).
+		AnyTimes()
+	// the only email is sent to unverified user
+	brokerMock.EXPECT().
+		Send(gomock.Any(), gomock.Any(), cfg.Kafka.EmailVerificationTopic, user.ID).
+		Return(nil).
+		Times(1)
+
+	keyStorageMock := mocks.NewMockkeyStorage(ctrl)
+	keyStorageMock.EXPECT().
+		GetSigningKeys(gomock.Any()).
+		Return(nil, nil).
+		AnyTimes()
+	keyStorageMock.EXPECT().
+		DeleteExpiredSigningKeys(gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
+	tokenStorageMock := mocks.NewMocktokenStorage(ctrl)
+	gomock.InOrder(
+		tokenStorageMock.EXPECT().
+			LockEmailVerificationResend(gomock.Any(), user.ID, cfg.EmailVerification.ResendInterval).
+			Return(true, nil),
+		// the second request within resend interval
+		tokenStorageMock.EXPECT().
+			LockEmailVerificationResend(gomock.Any(), user.ID, cfg.EmailVerification.ResendInterval).
+			Return(false, nil),
+	)
+	tokenStorageMock.EXPECT().
+		SaveEmailVerificationToken(gomock.Any(), gomock.Any(), user.ID, cfg.EmailVerification.TokenTtl).
+		Return(nil).
+		Times(1)
+
+	signingKey, err := jwtlib.NewSigningKey(cfg.JWT.KeyID, cfg.JWT.Algorithm, cfg.JWT.PrivateKey)
+	require.NoError(t, err)
+	authService := authservice.New(
+		cfg,
+		log,
+		userStorageMock,
+		tokenStorageMock,
+		brokerMock,
+		mocks.NewMockobjectStorage(ctrl),
+		keyStorageMock,
+		jwtlib.NewKeyring(signingKey),
+	)
+
+	ctx := context.Background()
+	for _, email := range []string{user.Email, user.Email, verified.Email, "unknown@test.com"} {
+		_, err = authService.ResendEmailVerification(ctx, &dto.ResendEmailVerification{Email: email})
+		require.NoError(t, err)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/AlexBlackNn/authloyalty/sso/internal/config"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/dto"
+	jwtlib "github.com/AlexBlackNn/authloyalty/sso/internal/lib/jwt"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/logger"
 	"github.com/AlexBlackNn/authloyalty/sso/internal/services/authservice"
 	"github.com/AlexBlackNn/authloyalty/sso/pkg/broker"
@@ -42,7 +43,7 @@ func (ms *AuthSuite) SetupSuite() {
 
 	userStorageMock := mocks.NewMockuserStorage(ctrl)
 	userStorageMock.EXPECT().
-		SaveUser(gomock.Any(), gomock.Any(), gomock.Any()).
+		SaveUser(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return("79d3ac44-5857-4185-ba92-1a224fbacb51", nil).
 		AnyTimes()
 
@@ -60,10 +61,9 @@ func (ms *AuthSuite) SetupSuite() {
 		GetUserByEmail(gomock.Any(), gomock.Any()).
 		Return(user, nil).
 		AnyTimes()
-
 	userStorageMock.EXPECT().
-		UpdateSendStatus(gomock.Any(), gomock.Any(), gomock.Any()).
-		Return(nil).
+		GetUser(gomock.Any(), gomock.Any()).
+		Return(user, nil).
 		AnyTimes()
 
 	brokerMock := mocks.NewMockgetResponseChanSender(ctrl)
@@ -78,13 +78,76 @@ func (ms *AuthSuite) SetupSuite() {
 		Return(make(chan *broker.Response)).
 		AnyTimes()
 
+	objectStorageMock := mocks.NewMockobjectStorage(ctrl)
+	objectStorageMock.EXPECT().
+		UploadData(gomock.Any(), gomock.Any()).
+		Return("", nil).
+		AnyTimes()
+
+	signingKey, err := jwtlib.NewSigningKey(cfg.JWT.KeyID, cfg.JWT.Algorithm, cfg.JWT.PrivateKey)
+	ms.Suite.NoError(err)
+
+	keyStorageMock := mocks.NewMockkeyStorage(ctrl)
+	keyStorageMock.EXPECT().
+		GetSigningKeys(gomock.Any()).
+		Return(nil, nil).
+		AnyTimes()
+	keyStorageMock.EXPECT().
+		DeleteExpiredSigningKeys(gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
 	tokenStorageMock := mocks.NewMocktokenStorage(ctrl)
+	tokenStorageMock.EXPECT().
+		CheckTokenExists(gomock.Any(), gomock.Any()).
+		Return(int64(0), nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		SaveTokenFamily(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		CheckTokenFamilyRevoked(gomock.Any(), gomock.Any()).
+		Return(false, nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		SaveSession(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		SaveEmailVerificationToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		LoginLockTTL(gomock.Any(), gomock.Any()).
+		Return(time.Duration(0), nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		AddLoginFailure(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(int64(1), nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		LockLogin(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		ResetLoginFailures(gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		GetSessions(gomock.Any(), gomock.Any()).
+		Return([]domain.Session{{ID: "b5f2b7d1-5d2c-4bbf-9a4f-7d0f0bb3c8a1", UserAgent: "Go-http-client/1.1"}}, nil).
+		AnyTimes()
+
 	authService := authservice.New(
 		cfg,
 		log,
 		userStorageMock,
 		tokenStorageMock,
 		brokerMock,
+		objectStorageMock,
+		keyStorageMock,
+		jwtlib.NewKeyring(signingKey),
 	)
 
 	// http server
@@ -100,6 +163,7 @@ func (ms *AuthSuite) BeforeTest(suiteName, testName string) {
 		ms.application.Log,
 		ms.application.HandlersV1,
 		ms.application.HealthChecker,
+		ms.application.GatewayV2,
 	))
 }
 
@@ -161,8 +225,14 @@ func (ms *AuthSuite) TestHttpServerRegisterHappyPath() {
 		ms.NoError(err)
 		ms.Equal(test.want.response.Status, response.Status)
 
+		signingKey, err := jwtlib.NewSigningKey(
+			ms.application.Cfg.JWT.KeyID,
+			ms.application.Cfg.JWT.Algorithm,
+			ms.application.Cfg.JWT.PrivateKey,
+		)
+		ms.NoError(err)
 		tokenParsed, err := jwt.Parse(response.AccessToken, func(token *jwt.Token) (any, error) {
-			return []byte(ms.application.Cfg.ServiceSecret), nil
+			return signingKey.PrivateKey.Public(), nil
 		})
 		ms.NoError(err)
 
@@ -0,0 +1,47 @@
+package unit_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+func (ms *AuthSuite) TestHttpServerGatewayLogin() {
+	// stop server when tests finished
+	defer ms.srv.Close()
+
+	type loginResponse struct {
+		AccessToken  string `json:"accessToken"`
+		RefreshToken string `json:"refreshToken"`
+	}
+
+	ms.Run("login through grpc gateway", func() {
+		res, err := ms.client.Post(
+			ms.srv.URL+"/api/v2/auth/login",
+			"application/json",
+			bytes.NewBufferString(`{"email":"test@test.com","password":"test"}`),
+		)
+		ms.NoError(err)
+		defer res.Body.Close()
+		ms.Equal(http.StatusOK, res.StatusCode)
+
+		body, err := io.ReadAll(res.Body)
+		ms.NoError(err)
+		var response loginResponse
+		ms.NoError(json.Unmarshal(body, &response))
+		ms.NotEmpty(response.AccessToken)
+		ms.NotEmpty(response.RefreshToken)
+	})
+
+	ms.Run("wrong password is mapped to bad request", func() {
+		res, err := ms.client.Post(
+			ms.srv.URL+"/api/v2/auth/login",
+			"application/json",
+			bytes.NewBufferString(`{"email":"test@test.com","password":"wrong"}`),
+		)
+		ms.NoError(err)
+		defer res.Body.Close()
+		ms.Equal(http.StatusBadRequest, res.StatusCode)
+	})
+}
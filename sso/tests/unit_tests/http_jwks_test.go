@@ -0,0 +1,45 @@
+package unit_tests
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"io"
+	"net/http"
+
+	"github.com/AlexBlackNn/authloyalty/sso/internal/dto"
+	jwtlib "github.com/AlexBlackNn/authloyalty/sso/internal/lib/jwt"
+)
+
+func (ms *AuthSuite) TestHttpServerJWKSHappyPath() {
+	// stop server when tests finished
+	defer ms.srv.Close()
+
+	ms.Run("jwks", func() {
+		res, err := ms.client.Get(ms.srv.URL + "/.well-known/jwks.json")
+		ms.NoError(err)
+		defer res.Body.Close()
+		ms.Equal(http.StatusOK, res.StatusCode)
+		ms.Equal("application/json", res.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(res.Body)
+		ms.NoError(err)
+		var jwks dto.JWKS
+		err = jwks.UnmarshalJSON(body)
+		ms.NoError(err)
+		ms.Len(jwks.Keys, 1)
+
+		signingKey, err := jwtlib.NewSigningKey(
+			ms.application.Cfg.JWT.KeyID,
+			ms.application.Cfg.JWT.Algorithm,
+			ms.application.Cfg.JWT.PrivateKey,
+		)
+		ms.NoError(err)
+		publicKey, ok := signingKey.PrivateKey.Public().(ed25519.PublicKey)
+		ms.True(ok)
+
+		ms.Equal("OKP", jwks.Keys[0].Kty)
+		ms.Equal("Ed25519", jwks.Keys[0].Crv)
+		ms.Equal(jwtlib.AlgorithmEdDSA, jwks.Keys[0].Alg)
+		ms.Equal(base64.RawURLEncoding.EncodeToString(publicKey), jwks.Keys[0].X)
+	})
+}
@@ -0,0 +1,40 @@
+package unit_tests
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/AlexBlackNn/authloyalty/sso/internal/dto"
+)
+
+func (ms *AuthSuite) TestHttpServerGrantRoleForbiddenForUser() {
+	// stop server when tests finished
+	defer ms.srv.Close()
+
+	ms.Run("grant role by not admin", func() {
+		loginBody := dto.Login{Email: "test@test.com", Password: "test"}
+		reqJSON, err := loginBody.MarshalJSON()
+		ms.NoError(err)
+		res, err := ms.client.Post(ms.srv.URL+"/auth/login", "application/json", bytes.NewBuffer(reqJSON))
+		ms.NoError(err)
+		body, err := io.ReadAll(res.Body)
+		ms.NoError(err)
+		res.Body.Close()
+		var loginResponse dto.Response
+		ms.NoError(loginResponse.UnmarshalJSON(body))
+
+		grantBody := dto.UserRole{UserID: "7c2ab9ec-bddf-43ff-96a5-ff1e0785c909", Role: "admin"}
+		reqJSON, err = grantBody.MarshalJSON()
+		ms.NoError(err)
+		request, err := http.NewRequest(
+			http.MethodPost, ms.srv.URL+"/auth/roles/grant", bytes.NewBuffer(reqJSON),
+		)
+		ms.NoError(err)
+		request.Header.Set("Authorization", "Bearer "+loginResponse.AccessToken)
+		res, err = ms.client.Do(request)
+		ms.NoError(err)
+		defer res.Body.Close()
+		ms.Equal(http.StatusForbidden, res.StatusCode)
+	})
+}
@@ -0,0 +1,67 @@
+package unit_tests
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/AlexBlackNn/authloyalty/sso/internal/dto"
+)
+
+func (ms *AuthSuite) TestHttpServerListSessions() {
+	// stop server when tests finished
+	defer ms.srv.Close()
+
+	ms.Run("list own sessions", func() {
+		loginBody := dto.Login{Email: "test@test.com", Password: "test"}
+		reqJSON, err := loginBody.MarshalJSON()
+		ms.NoError(err)
+		res, err := ms.client.Post(ms.srv.URL+"/auth/login", "application/json", bytes.NewBuffer(reqJSON))
+		ms.NoError(err)
+		body, err := io.ReadAll(res.Body)
+		ms.NoError(err)
+		res.Body.Close()
+		var loginResponse dto.Response
+		ms.NoError(loginResponse.UnmarshalJSON(body))
+
+		request, err := http.NewRequest(http.MethodGet, ms.srv.URL+"/auth/sessions", nil)
+		ms.NoError(err)
+		request.Header.Set("Authorization", "Bearer "+loginResponse.AccessToken)
+		res, err = ms.client.Do(request)
+		ms.NoError(err)
+		defer res.Body.Close()
+		ms.Equal(http.StatusOK, res.StatusCode)
+
+		body, err = io.ReadAll(res.Body)
+		ms.NoError(err)
+		var sessionsResponse dto.SessionsResponse
+		ms.NoError(sessionsResponse.UnmarshalJSON(body))
+		ms.Len(sessionsResponse.Sessions, 1)
+		ms.Equal("Go-http-client/1.1", sessionsResponse.Sessions[0].UserAgent)
+	})
+
+	ms.Run("list sessions of other user by not admin", func() {
+		loginBody := dto.Login{Email: "test@test.com", Password: "test"}
+		reqJSON, err := loginBody.MarshalJSON()
+		ms.NoError(err)
+		res, err := ms.client.Post(ms.srv.URL+"/auth/login", "application/json", bytes.NewBuffer(reqJSON))
+		ms.NoError(err)
+		body, err := io.ReadAll(res.Body)
+		ms.NoError(err)
+		res.Body.Close()
+		var loginResponse dto.Response
+		ms.NoError(loginResponse.UnmarshalJSON(body))
+
+		request, err := http.NewRequest(
+			http.MethodGet,
+			ms.srv.URL+"/auth/sessions?user_id=7c2ab9ec-bddf-43ff-96a5-ff1e0785c909",
+			nil,
+		)
+		ms.NoError(err)
+		request.Header.Set("Authorization", "Bearer "+loginResponse.AccessToken)
+		res, err = ms.client.Do(request)
+		ms.NoError(err)
+		defer res.Body.Close()
+		ms.Equal(http.StatusForbidden, res.StatusCode)
+	})
+}
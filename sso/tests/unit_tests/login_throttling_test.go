@@ -0,0 +1,178 @@
+package unit_tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/sso/internal/config"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/dto"
+	jwtlib "github.com/AlexBlackNn/authloyalty/sso/internal/lib/jwt"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/logger"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/services/authservice"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/storage"
+	"github.com/AlexBlackNn/authloyalty/sso/pkg/broker"
+	"github.com/AlexBlackNn/authloyalty/sso/tests/unit_tests/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestLoginLockoutAndUnlock(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	cfg.LoginThrottling.FreeAttempts = 1
+	cfg.LoginThrottling.LockoutAttempts = 3
+	cfg.MFA.RequiredForAdmins = false
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	passHash, err := bcrypt.GenerateFromPassword([]byte("test"), bcrypt.DefaultCost)
+	require.NoError(t, err)
+	user := domain.User{
+		ID:       "79d3ac44-5857-4185-ba92-1a224fbacb51",
+		Email:    "test@test.com",
+		PassHash: passHash,
+	}
+	admin := domain.User{
+		ID:          "7c2ab9ec-bddf-43ff-96a5-ff1e0785c909",
+		Email:       "admin@test.com",
+		PassHash:    passHash,
+		Roles:       []string{domain.RoleAdmin},
+		Permissions: []string{domain.PermissionUsersUnlock},
+	}
+
+	userStorageMock := mocks.NewMockuserStorage(ctrl)
+	for _, u := range []domain.User{user, admin} {
+		userStorageMock.EXPECT().
+			GetUserByEmail(gomock.Any(), u.Email).
+			Return(u, nil).
+			AnyTimes()
+		userStorageMock.EXPECT().
+			GetUser(gomock.Any(), u.ID).
+			Return(u, nil).
+			AnyTimes()
+	}
+	userStorageMock.EXPECT().
+		GetUserByEmail(gomock.Any(), gomock.Any()).
+		Return(domain.User{}, storage.ErrUserNotFound).
+		AnyTimes()
+
+	brokerMock := mocks.NewMockgetResponseChanSender(ctrl)
+	brokerMock.EXPECT().
+		GetResponseChan().
+		Return(make(chan *broker.Response)).
+		AnyTimes()
+
+	keyStorageMock := mocks.NewMockkeyStorage(ctrl)
+	keyStorageMock.EXPECT().
+		GetSigningKeys(gomock.Any()).
+		Return(nil, nil).
+		AnyTimes()
+	keyStorageMock.EXPECT().
+		DeleteExpiredSigningKeys(gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
+	// failures and locks are kept in memory instead of redis
+	failures := map[string]int64{}
+	locks := map[string]time.Duration{}
+	tokenStorageMock := mocks.NewMocktokenStorage(ctrl)
+	tokenStorageMock.EXPECT().
+		LoginLockTTL(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, subject string) (time.Duration, error) {
+			return locks[subject], nil
+		}).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		AddLoginFailure(gomock.Any(), gomock.Any(), cfg.LoginThrottling.Window).
+		DoAndReturn(func(_ context.Context, subject string, _ time.Duration) (int64, error) {
+			failures[subject]++
+			return failures[subject], nil
+		}).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		LockLogin(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, subject string, ttl time.Duration) error {
+			locks[subject] = ttl
+			return nil
+		}).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		ResetLoginFailures(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, subject string) error {
+			delete(failures, subject)
+			delete(locks, subject)
+			return nil
+		}).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		SaveTokenFamily(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		SaveSession(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		CheckTokenExists(gomock.Any(), gomock.Any()).
+		Return(int64(0), nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		CheckTokenFamilyRevoked(gomock.Any(), gomock.Any()).
+		Return(false, nil).
+		AnyTimes()
+
+	signingKey, err := jwtlib.NewSigningKey(cfg.JWT.KeyID, cfg.JWT.Algorithm, cfg.JWT.PrivateKey)
+	require.NoError(t, err)
+	authService := authservice.New(
+		cfg,
+		log,
+		userStorageMock,
+		tokenStorageMock,
+		brokerMock,
+		mocks.NewMockobjectStorage(ctrl),
+		keyStorageMock,
+		jwtlib.NewKeyring(signingKey),
+	)
+
+	ctx := context.Background()
+	userLogin, err := authService.Login(ctx, &dto.Login{Email: user.Email, Password: "test"})
+	require.NoError(t, err)
+	adminLogin, err := authService.Login(ctx, &dto.Login{Email: admin.Email, Password: "test"})
+	require.NoError(t, err)
+
+	wrongPassword := &dto.Login{Email: user.Email, Password: "wrong", Client: dto.Client{IP: "10.0.0.1"}}
+
+	// the first attempt is free
+	_, err = authService.Login(ctx, wrongPassword)
+	require.ErrorIs(t, err, authservice.ErrInvalidCredentials)
+
+	// the second attempt locks login with base delay
+	_, err = authService.Login(ctx, wrongPassword)
+	require.ErrorIs(t, err, authservice.ErrInvalidCredentials)
+	_, err = authService.Login(ctx, &dto.Login{Email: user.Email, Password: "test"})
+	var lockedErr *authservice.LoginLockedError
+	require.True(t, errors.As(err, &lockedErr))
+	require.ErrorIs(t, err, authservice.ErrLoginLocked)
+	require.Equal(t, cfg.LoginThrottling.BaseDelay, lockedErr.RetryAfter)
+
+	// lockout attempts lock login for lockout duration
+	delete(locks, "account:"+user.Email)
+	_, err = authService.Login(ctx, wrongPassword)
+	require.ErrorIs(t, err, authservice.ErrInvalidCredentials)
+	_, err = authService.Login(ctx, &dto.Login{Email: user.Email, Password: "test"})
+	require.True(t, errors.As(err, &lockedErr))
+	require.Equal(t, cfg.LoginThrottling.LockoutDuration, lockedErr.RetryAfter)
+
+	// users can't unlock themselves
+	err = authService.UnlockLogin(ctx, userLogin.AccessToken, &dto.UnlockLogin{UserID: user.ID})
+	require.ErrorIs(t, err, authservice.ErrPermissionDenied)
+
+	err = authService.UnlockLogin(ctx, adminLogin.AccessToken, &dto.UnlockLogin{UserID: user.ID})
+	require.NoError(t, err)
+	_, err = authService.Login(ctx, &dto.Login{Email: user.Email, Password: "test"})
+	require.NoError(t, err)
+}
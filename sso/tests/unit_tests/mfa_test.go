@@ -0,0 +1,161 @@
+package unit_tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/sso/internal/config"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/dto"
+	jwtlib "github.com/AlexBlackNn/authloyalty/sso/internal/lib/jwt"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/lib/totp"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/logger"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/services/authservice"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/storage"
+	"github.com/AlexBlackNn/authloyalty/sso/pkg/broker"
+	"github.com/AlexBlackNn/authloyalty/sso/tests/unit_tests/mocks"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestLoginWithMFA(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	passHash, err := bcrypt.GenerateFromPassword([]byte("test"), bcrypt.DefaultCost)
+	require.NoError(t, err)
+	user := domain.User{
+		ID:         "79d3ac44-5857-4185-ba92-1a224fbacb51",
+		Email:      "test@test.com",
+		PassHash:   passHash,
+		MFAEnabled: true,
+	}
+
+	userStorageMock := mocks.NewMockuserStorage(ctrl)
+	userStorageMock.EXPECT().
+		GetUserByEmail(gomock.Any(), user.Email).
+		Return(user, nil).
+		AnyTimes()
+	userStorageMock.EXPECT().
+		GetUser(gomock.Any(), user.ID).
+		Return(user, nil).
+		AnyTimes()
+	userStorageMock.EXPECT().
+		GetMFASecret(gomock.Any(), user.ID).
+		Return(secret, nil).
+		Times(2)
+	// code is accepted once, steps must grow
+	var lastStep int64
+	userStorageMock.EXPECT().
+		UseTOTPStep(gomock.Any(), user.ID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, step int64) error {
+			if step <= lastStep {
+				return storage.ErrTOTPStepUsed
+			}
+			lastStep = step
+			return nil
+		}).
+		Times(2)
+
+	brokerMock := mocks.NewMockgetResponseChanSender(ctrl)
+	brokerMock.EXPECT().
+		GetResponseChan().
+		Return(make(chan *broker.Response)).
+		AnyTimes()
+
+	keyStorageMock := mocks.NewMockkeyStorage(ctrl)
+	keyStorageMock.EXPECT().
+		GetSigningKeys(gomock.Any()).
+		Return(nil, nil).
+		AnyTimes()
+	keyStorageMock.EXPECT().
+		DeleteExpiredSigningKeys(gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
+	// challenges are single-use
+	challenges := map[string]bool{}
+	tokenStorageMock := mocks.NewMocktokenStorage(ctrl)
+	tokenStorageMock.EXPECT().
+		LoginLockTTL(gomock.Any(), gomock.Any()).
+		Return(time.Duration(0), nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		ResetLoginFailures(gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		SaveMFAChallenge(gomock.Any(), gomock.Any(), user.ID, cfg.MFA.ChallengeTokenTtl).
+		DoAndReturn(func(_ context.Context, tokenHash, _ string, _ any) error {
+			challenges[tokenHash] = true
+			return nil
+		}).
+		Times(2)
+	tokenStorageMock.EXPECT().
+		ConsumeMFAChallenge(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, tokenHash string) (string, error) {
+			if !challenges[tokenHash] {
+				return "", storage.ErrOneTimeTokenNotFound
+			}
+			delete(challenges, tokenHash)
+			return user.ID, nil
+		}).
+		Times(3)
+	// session is started only after the second factor is checked
+	tokenStorageMock.EXPECT().
+		SaveTokenFamily(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil)
+	tokenStorageMock.EXPECT().
+		SaveSession(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	signingKey, err := jwtlib.NewSigningKey(cfg.JWT.KeyID, cfg.JWT.Algorithm, cfg.JWT.PrivateKey)
+	require.NoError(t, err)
+	authService := authservice.New(
+		cfg,
+		log,
+		userStorageMock,
+		tokenStorageMock,
+		brokerMock,
+		mocks.NewMockobjectStorage(ctrl),
+		keyStorageMock,
+		jwtlib.NewKeyring(signingKey),
+	)
+
+	ctx := context.Background()
+	login, err := authService.Login(ctx, &dto.Login{Email: user.Email, Password: "test"})
+	require.NoError(t, err)
+	require.NotEmpty(t, login.MFAToken)
+	require.Empty(t, login.AccessToken)
+	require.Empty(t, login.RefreshToken)
+
+	code, err := totp.Code(secret, time.Now())
+	require.NoError(t, err)
+	verified, err := authService.VerifyMFA(ctx, &dto.VerifyMFA{MFAToken: login.MFAToken, Code: code})
+	require.NoError(t, err)
+	require.NotEmpty(t, verified.AccessToken)
+
+	tokenParsed, err := jwt.Parse(verified.AccessToken, func(token *jwt.Token) (any, error) {
+		return signingKey.PrivateKey.Public(), nil
+	})
+	require.NoError(t, err)
+	claims, ok := tokenParsed.Claims.(jwt.MapClaims)
+	require.True(t, ok)
+	require.ElementsMatch(t, []any{domain.AuthMethodPassword, domain.AuthMethodOTP}, claims["amr"])
+
+	_, err = authService.VerifyMFA(ctx, &dto.VerifyMFA{MFAToken: login.MFAToken, Code: code})
+	require.ErrorIs(t, err, authservice.ErrMFAInvalid)
+
+	// intercepted code can't be replayed with a new challenge while it's valid
+	login, err = authService.Login(ctx, &dto.Login{Email: user.Email, Password: "test"})
+	require.NoError(t, err)
+	_, err = authService.VerifyMFA(ctx, &dto.VerifyMFA{MFAToken: login.MFAToken, Code: code})
+	require.ErrorIs(t, err, authservice.ErrMFAInvalid)
+}
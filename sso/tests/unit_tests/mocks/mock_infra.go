@@ -10,6 +10,7 @@ import (
 	time "time"
 
 	domain "github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	dto "github.com/AlexBlackNn/authloyalty/sso/internal/dto"
 	broker "github.com/AlexBlackNn/authloyalty/sso/pkg/broker"
 	gomock "github.com/golang/mock/gomock"
 	proto "google.golang.org/protobuf/proto"
@@ -89,6 +90,103 @@ func (m *MockuserStorage) EXPECT() *MockuserStorageMockRecorder {
 	return m.recorder
 }
 
+// BlockUser mocks base method.
+func (m *MockuserStorage) BlockUser(ctx context.Context, uuid, reason string, until *time.Time, events ...domain.OutboxMessage) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, uuid, reason, until}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BlockUser", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockUser indicates an expected call of BlockUser.
+func (mr *MockuserStorageMockRecorder) BlockUser(ctx, uuid, reason, until interface{}, events ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, uuid, reason, until}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUser", reflect.TypeOf((*MockuserStorage)(nil).BlockUser), varargs...)
+}
+
+// CountExhaustedRegistrations mocks base method.
+func (m *MockuserStorage) CountExhaustedRegistrations(ctx context.Context, maxAttempts int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountExhaustedRegistrations", ctx, maxAttempts)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountExhaustedRegistrations indicates an expected call of CountExhaustedRegistrations.
+func (mr *MockuserStorageMockRecorder) CountExhaustedRegistrations(ctx, maxAttempts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountExhaustedRegistrations", reflect.TypeOf((*MockuserStorage)(nil).CountExhaustedRegistrations), ctx, maxAttempts)
+}
+
+// DeleteUser mocks base method.
+func (m *MockuserStorage) DeleteUser(ctx context.Context, uuid string, events ...domain.OutboxMessage) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, uuid}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteUser", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockuserStorageMockRecorder) DeleteUser(ctx, uuid interface{}, events ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, uuid}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockuserStorage)(nil).DeleteUser), varargs...)
+}
+
+// EnableMFA mocks base method.
+func (m *MockuserStorage) EnableMFA(ctx context.Context, uuid string, recoveryCodeHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableMFA", ctx, uuid, recoveryCodeHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableMFA indicates an expected call of EnableMFA.
+func (mr *MockuserStorageMockRecorder) EnableMFA(ctx, uuid, recoveryCodeHashes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableMFA", reflect.TypeOf((*MockuserStorage)(nil).EnableMFA), ctx, uuid, recoveryCodeHashes)
+}
+
+// GetMFASecret mocks base method.
+func (m *MockuserStorage) GetMFASecret(ctx context.Context, uuid string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMFASecret", ctx, uuid)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMFASecret indicates an expected call of GetMFASecret.
+func (mr *MockuserStorageMockRecorder) GetMFASecret(ctx, uuid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMFASecret", reflect.TypeOf((*MockuserStorage)(nil).GetMFASecret), ctx, uuid)
+}
+
+// GetUndeliveredRegistrations mocks base method.
+func (m *MockuserStorage) GetUndeliveredRegistrations(ctx context.Context, topic string, staleAfter time.Duration, maxAttempts int, verifiedOnly bool, limit int) ([]domain.UndeliveredRegistration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUndeliveredRegistrations", ctx, topic, staleAfter, maxAttempts, verifiedOnly, limit)
+	ret0, _ := ret[0].([]domain.UndeliveredRegistration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUndeliveredRegistrations indicates an expected call of GetUndeliveredRegistrations.
+func (mr *MockuserStorageMockRecorder) GetUndeliveredRegistrations(ctx, topic, staleAfter, maxAttempts, verifiedOnly, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUndeliveredRegistrations", reflect.TypeOf((*MockuserStorage)(nil).GetUndeliveredRegistrations), ctx, topic, staleAfter, maxAttempts, verifiedOnly, limit)
+}
+
 // GetUser mocks base method.
 func (m *MockuserStorage) GetUser(ctx context.Context, uuid string) (domain.User, error) {
 	m.ctrl.T.Helper()
@@ -119,6 +217,25 @@ func (mr *MockuserStorageMockRecorder) GetUserByEmail(ctx, email interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockuserStorage)(nil).GetUserByEmail), ctx, email)
 }
 
+// GrantRole mocks base method.
+func (m *MockuserStorage) GrantRole(ctx context.Context, uuid, role string, events ...domain.OutboxMessage) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, uuid, role}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GrantRole", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GrantRole indicates an expected call of GrantRole.
+func (mr *MockuserStorageMockRecorder) GrantRole(ctx, uuid, role interface{}, events ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, uuid, role}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantRole", reflect.TypeOf((*MockuserStorage)(nil).GrantRole), varargs...)
+}
+
 // HealthCheck mocks base method.
 func (m *MockuserStorage) HealthCheck(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -133,33 +250,166 @@ func (mr *MockuserStorageMockRecorder) HealthCheck(ctx interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealthCheck", reflect.TypeOf((*MockuserStorage)(nil).HealthCheck), ctx)
 }
 
+// RetryRegistration mocks base method.
+func (m *MockuserStorage) RetryRegistration(ctx context.Context, uuid string, attempts int, delay time.Duration, event domain.OutboxMessage) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryRegistration", ctx, uuid, attempts, delay, event)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetryRegistration indicates an expected call of RetryRegistration.
+func (mr *MockuserStorageMockRecorder) RetryRegistration(ctx, uuid, attempts, delay, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryRegistration", reflect.TypeOf((*MockuserStorage)(nil).RetryRegistration), ctx, uuid, attempts, delay, event)
+}
+
+// RevokeRole mocks base method.
+func (m *MockuserStorage) RevokeRole(ctx context.Context, uuid, role string, events ...domain.OutboxMessage) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, uuid, role}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RevokeRole", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRole indicates an expected call of RevokeRole.
+func (mr *MockuserStorageMockRecorder) RevokeRole(ctx, uuid, role interface{}, events ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, uuid, role}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRole", reflect.TypeOf((*MockuserStorage)(nil).RevokeRole), varargs...)
+}
+
+// SaveMFASecret mocks base method.
+func (m *MockuserStorage) SaveMFASecret(ctx context.Context, uuid, secret string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveMFASecret", ctx, uuid, secret)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveMFASecret indicates an expected call of SaveMFASecret.
+func (mr *MockuserStorageMockRecorder) SaveMFASecret(ctx, uuid, secret interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveMFASecret", reflect.TypeOf((*MockuserStorage)(nil).SaveMFASecret), ctx, uuid, secret)
+}
+
 // SaveUser mocks base method.
-func (m *MockuserStorage) SaveUser(ctx context.Context, email string, passHash []byte) (string, error) {
+func (m *MockuserStorage) SaveUser(ctx context.Context, email string, passHash []byte, newEvent func(string) (domain.OutboxMessage, error)) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveUser", ctx, email, passHash)
+	ret := m.ctrl.Call(m, "SaveUser", ctx, email, passHash, newEvent)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SaveUser indicates an expected call of SaveUser.
-func (mr *MockuserStorageMockRecorder) SaveUser(ctx, email, passHash interface{}) *gomock.Call {
+func (mr *MockuserStorageMockRecorder) SaveUser(ctx, email, passHash, newEvent interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUser", reflect.TypeOf((*MockuserStorage)(nil).SaveUser), ctx, email, passHash)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUser", reflect.TypeOf((*MockuserStorage)(nil).SaveUser), ctx, email, passHash, newEvent)
 }
 
-// UpdateSendStatus mocks base method.
-func (m *MockuserStorage) UpdateSendStatus(ctx context.Context, uuid, status string) error {
+// UnblockUser mocks base method.
+func (m *MockuserStorage) UnblockUser(ctx context.Context, uuid string, events ...domain.OutboxMessage) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateSendStatus", ctx, uuid, status)
+	varargs := []interface{}{ctx, uuid}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UnblockUser", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateSendStatus indicates an expected call of UpdateSendStatus.
-func (mr *MockuserStorageMockRecorder) UpdateSendStatus(ctx, uuid, status interface{}) *gomock.Call {
+// UnblockUser indicates an expected call of UnblockUser.
+func (mr *MockuserStorageMockRecorder) UnblockUser(ctx, uuid interface{}, events ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSendStatus", reflect.TypeOf((*MockuserStorage)(nil).UpdateSendStatus), ctx, uuid, status)
+	varargs := append([]interface{}{ctx, uuid}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnblockUser", reflect.TypeOf((*MockuserStorage)(nil).UnblockUser), varargs...)
+}
+
+// UpdateEmail mocks base method.
+func (m *MockuserStorage) UpdateEmail(ctx context.Context, uuid, email string, events ...domain.OutboxMessage) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, uuid, email}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateEmail", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEmail indicates an expected call of UpdateEmail.
+func (mr *MockuserStorageMockRecorder) UpdateEmail(ctx, uuid, email interface{}, events ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, uuid, email}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEmail", reflect.TypeOf((*MockuserStorage)(nil).UpdateEmail), varargs...)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockuserStorage) UpdatePassword(ctx context.Context, uuid string, passHash []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, uuid, passHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockuserStorageMockRecorder) UpdatePassword(ctx, uuid, passHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockuserStorage)(nil).UpdatePassword), ctx, uuid, passHash)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockuserStorage) UseRecoveryCode(ctx context.Context, uuid, codeHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", ctx, uuid, codeHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockuserStorageMockRecorder) UseRecoveryCode(ctx, uuid, codeHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockuserStorage)(nil).UseRecoveryCode), ctx, uuid, codeHash)
+}
+
+// UseTOTPStep mocks base method.
+func (m *MockuserStorage) UseTOTPStep(ctx context.Context, uuid string, step int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseTOTPStep", ctx, uuid, step)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseTOTPStep indicates an expected call of UseTOTPStep.
+func (mr *MockuserStorageMockRecorder) UseTOTPStep(ctx, uuid, step interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseTOTPStep", reflect.TypeOf((*MockuserStorage)(nil).UseTOTPStep), ctx, uuid, step)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockuserStorage) VerifyEmail(ctx context.Context, uuid string, events ...domain.OutboxMessage) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, uuid}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "VerifyEmail", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockuserStorageMockRecorder) VerifyEmail(ctx, uuid interface{}, events ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, uuid}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockuserStorage)(nil).VerifyEmail), varargs...)
 }
 
 // MocktokenStorage is a mock of tokenStorage interface.
@@ -185,6 +435,21 @@ func (m *MocktokenStorage) EXPECT() *MocktokenStorageMockRecorder {
 	return m.recorder
 }
 
+// AddLoginFailure mocks base method.
+func (m *MocktokenStorage) AddLoginFailure(ctx context.Context, subject string, window time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddLoginFailure", ctx, subject, window)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddLoginFailure indicates an expected call of AddLoginFailure.
+func (mr *MocktokenStorageMockRecorder) AddLoginFailure(ctx, subject, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLoginFailure", reflect.TypeOf((*MocktokenStorage)(nil).AddLoginFailure), ctx, subject, window)
+}
+
 // CheckTokenExists mocks base method.
 func (m *MocktokenStorage) CheckTokenExists(ctx context.Context, token string) (int64, error) {
 	m.ctrl.T.Helper()
@@ -200,6 +465,95 @@ func (mr *MocktokenStorageMockRecorder) CheckTokenExists(ctx, token interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckTokenExists", reflect.TypeOf((*MocktokenStorage)(nil).CheckTokenExists), ctx, token)
 }
 
+// CheckTokenFamilyRevoked mocks base method.
+func (m *MocktokenStorage) CheckTokenFamilyRevoked(ctx context.Context, familyID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckTokenFamilyRevoked", ctx, familyID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckTokenFamilyRevoked indicates an expected call of CheckTokenFamilyRevoked.
+func (mr *MocktokenStorageMockRecorder) CheckTokenFamilyRevoked(ctx, familyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckTokenFamilyRevoked", reflect.TypeOf((*MocktokenStorage)(nil).CheckTokenFamilyRevoked), ctx, familyID)
+}
+
+// ConsumeEmailVerificationToken mocks base method.
+func (m *MocktokenStorage) ConsumeEmailVerificationToken(ctx context.Context, tokenHash string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeEmailVerificationToken", ctx, tokenHash)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeEmailVerificationToken indicates an expected call of ConsumeEmailVerificationToken.
+func (mr *MocktokenStorageMockRecorder) ConsumeEmailVerificationToken(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeEmailVerificationToken", reflect.TypeOf((*MocktokenStorage)(nil).ConsumeEmailVerificationToken), ctx, tokenHash)
+}
+
+// ConsumeMFAChallenge mocks base method.
+func (m *MocktokenStorage) ConsumeMFAChallenge(ctx context.Context, tokenHash string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeMFAChallenge", ctx, tokenHash)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeMFAChallenge indicates an expected call of ConsumeMFAChallenge.
+func (mr *MocktokenStorageMockRecorder) ConsumeMFAChallenge(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeMFAChallenge", reflect.TypeOf((*MocktokenStorage)(nil).ConsumeMFAChallenge), ctx, tokenHash)
+}
+
+// ConsumePasswordResetToken mocks base method.
+func (m *MocktokenStorage) ConsumePasswordResetToken(ctx context.Context, tokenHash string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumePasswordResetToken", ctx, tokenHash)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumePasswordResetToken indicates an expected call of ConsumePasswordResetToken.
+func (mr *MocktokenStorageMockRecorder) ConsumePasswordResetToken(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumePasswordResetToken", reflect.TypeOf((*MocktokenStorage)(nil).ConsumePasswordResetToken), ctx, tokenHash)
+}
+
+// DeleteSession mocks base method.
+func (m *MocktokenStorage) DeleteSession(ctx context.Context, userID, sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSession", ctx, userID, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSession indicates an expected call of DeleteSession.
+func (mr *MocktokenStorageMockRecorder) DeleteSession(ctx, userID, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MocktokenStorage)(nil).DeleteSession), ctx, userID, sessionID)
+}
+
+// GetSessions mocks base method.
+func (m *MocktokenStorage) GetSessions(ctx context.Context, userID string) ([]domain.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessions", ctx, userID)
+	ret0, _ := ret[0].([]domain.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessions indicates an expected call of GetSessions.
+func (mr *MocktokenStorageMockRecorder) GetSessions(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessions", reflect.TypeOf((*MocktokenStorage)(nil).GetSessions), ctx, userID)
+}
+
 // GetToken mocks base method.
 func (m *MocktokenStorage) GetToken(ctx context.Context, token string) (string, error) {
 	m.ctrl.T.Helper()
@@ -215,6 +569,162 @@ func (mr *MocktokenStorageMockRecorder) GetToken(ctx, token interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetToken", reflect.TypeOf((*MocktokenStorage)(nil).GetToken), ctx, token)
 }
 
+// LockEmailVerificationResend mocks base method.
+func (m *MocktokenStorage) LockEmailVerificationResend(ctx context.Context, userID string, ttl time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockEmailVerificationResend", ctx, userID, ttl)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockEmailVerificationResend indicates an expected call of LockEmailVerificationResend.
+func (mr *MocktokenStorageMockRecorder) LockEmailVerificationResend(ctx, userID, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockEmailVerificationResend", reflect.TypeOf((*MocktokenStorage)(nil).LockEmailVerificationResend), ctx, userID, ttl)
+}
+
+// LockLogin mocks base method.
+func (m *MocktokenStorage) LockLogin(ctx context.Context, subject string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockLogin", ctx, subject, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockLogin indicates an expected call of LockLogin.
+func (mr *MocktokenStorageMockRecorder) LockLogin(ctx, subject, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockLogin", reflect.TypeOf((*MocktokenStorage)(nil).LockLogin), ctx, subject, ttl)
+}
+
+// LoginLockTTL mocks base method.
+func (m *MocktokenStorage) LoginLockTTL(ctx context.Context, subject string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginLockTTL", ctx, subject)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginLockTTL indicates an expected call of LoginLockTTL.
+func (mr *MocktokenStorageMockRecorder) LoginLockTTL(ctx, subject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginLockTTL", reflect.TypeOf((*MocktokenStorage)(nil).LoginLockTTL), ctx, subject)
+}
+
+// RefreshSession mocks base method.
+func (m *MocktokenStorage) RefreshSession(ctx context.Context, session domain.Session, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshSession", ctx, session, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshSession indicates an expected call of RefreshSession.
+func (mr *MocktokenStorageMockRecorder) RefreshSession(ctx, session, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshSession", reflect.TypeOf((*MocktokenStorage)(nil).RefreshSession), ctx, session, ttl)
+}
+
+// ResetLoginFailures mocks base method.
+func (m *MocktokenStorage) ResetLoginFailures(ctx context.Context, subject string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetLoginFailures", ctx, subject)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetLoginFailures indicates an expected call of ResetLoginFailures.
+func (mr *MocktokenStorageMockRecorder) ResetLoginFailures(ctx, subject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetLoginFailures", reflect.TypeOf((*MocktokenStorage)(nil).ResetLoginFailures), ctx, subject)
+}
+
+// RevokeTokenFamily mocks base method.
+func (m *MocktokenStorage) RevokeTokenFamily(ctx context.Context, familyID string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeTokenFamily", ctx, familyID, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeTokenFamily indicates an expected call of RevokeTokenFamily.
+func (mr *MocktokenStorageMockRecorder) RevokeTokenFamily(ctx, familyID, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeTokenFamily", reflect.TypeOf((*MocktokenStorage)(nil).RevokeTokenFamily), ctx, familyID, ttl)
+}
+
+// RotateTokenFamily mocks base method.
+func (m *MocktokenStorage) RotateTokenFamily(ctx context.Context, familyID, tokenID, nextTokenID string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateTokenFamily", ctx, familyID, tokenID, nextTokenID, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RotateTokenFamily indicates an expected call of RotateTokenFamily.
+func (mr *MocktokenStorageMockRecorder) RotateTokenFamily(ctx, familyID, tokenID, nextTokenID, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateTokenFamily", reflect.TypeOf((*MocktokenStorage)(nil).RotateTokenFamily), ctx, familyID, tokenID, nextTokenID, ttl)
+}
+
+// SaveEmailVerificationToken mocks base method.
+func (m *MocktokenStorage) SaveEmailVerificationToken(ctx context.Context, tokenHash, userID string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveEmailVerificationToken", ctx, tokenHash, userID, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveEmailVerificationToken indicates an expected call of SaveEmailVerificationToken.
+func (mr *MocktokenStorageMockRecorder) SaveEmailVerificationToken(ctx, tokenHash, userID, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveEmailVerificationToken", reflect.TypeOf((*MocktokenStorage)(nil).SaveEmailVerificationToken), ctx, tokenHash, userID, ttl)
+}
+
+// SaveMFAChallenge mocks base method.
+func (m *MocktokenStorage) SaveMFAChallenge(ctx context.Context, tokenHash, userID string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveMFAChallenge", ctx, tokenHash, userID, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveMFAChallenge indicates an expected call of SaveMFAChallenge.
+func (mr *MocktokenStorageMockRecorder) SaveMFAChallenge(ctx, tokenHash, userID, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveMFAChallenge", reflect.TypeOf((*MocktokenStorage)(nil).SaveMFAChallenge), ctx, tokenHash, userID, ttl)
+}
+
+// SavePasswordResetToken mocks base method.
+func (m *MocktokenStorage) SavePasswordResetToken(ctx context.Context, tokenHash, userID string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePasswordResetToken", ctx, tokenHash, userID, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePasswordResetToken indicates an expected call of SavePasswordResetToken.
+func (mr *MocktokenStorageMockRecorder) SavePasswordResetToken(ctx, tokenHash, userID, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePasswordResetToken", reflect.TypeOf((*MocktokenStorage)(nil).SavePasswordResetToken), ctx, tokenHash, userID, ttl)
+}
+
+// SaveSession mocks base method.
+func (m *MocktokenStorage) SaveSession(ctx context.Context, session domain.Session, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSession", ctx, session, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSession indicates an expected call of SaveSession.
+func (mr *MocktokenStorageMockRecorder) SaveSession(ctx, session, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSession", reflect.TypeOf((*MocktokenStorage)(nil).SaveSession), ctx, session, ttl)
+}
+
 // SaveToken mocks base method.
 func (m *MocktokenStorage) SaveToken(ctx context.Context, token string, ttl time.Duration) error {
 	m.ctrl.T.Helper()
@@ -228,3 +738,150 @@ func (mr *MocktokenStorageMockRecorder) SaveToken(ctx, token, ttl interface{}) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveToken", reflect.TypeOf((*MocktokenStorage)(nil).SaveToken), ctx, token, ttl)
 }
+
+// SaveTokenFamily mocks base method.
+func (m *MocktokenStorage) SaveTokenFamily(ctx context.Context, familyID, tokenID string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTokenFamily", ctx, familyID, tokenID, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveTokenFamily indicates an expected call of SaveTokenFamily.
+func (mr *MocktokenStorageMockRecorder) SaveTokenFamily(ctx, familyID, tokenID, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTokenFamily", reflect.TypeOf((*MocktokenStorage)(nil).SaveTokenFamily), ctx, familyID, tokenID, ttl)
+}
+
+// MockkeyStorage is a mock of keyStorage interface.
+type MockkeyStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockkeyStorageMockRecorder
+}
+
+// MockkeyStorageMockRecorder is the mock recorder for MockkeyStorage.
+type MockkeyStorageMockRecorder struct {
+	mock *MockkeyStorage
+}
+
+// NewMockkeyStorage creates a new mock instance.
+func NewMockkeyStorage(ctrl *gomock.Controller) *MockkeyStorage {
+	mock := &MockkeyStorage{ctrl: ctrl}
+	mock.recorder = &MockkeyStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockkeyStorage) EXPECT() *MockkeyStorageMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpiredSigningKeys mocks base method.
+func (m *MockkeyStorage) DeleteExpiredSigningKeys(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredSigningKeys", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredSigningKeys indicates an expected call of DeleteExpiredSigningKeys.
+func (mr *MockkeyStorageMockRecorder) DeleteExpiredSigningKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredSigningKeys", reflect.TypeOf((*MockkeyStorage)(nil).DeleteExpiredSigningKeys), ctx)
+}
+
+// GetSigningKeys mocks base method.
+func (m *MockkeyStorage) GetSigningKeys(ctx context.Context) ([]domain.SigningKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSigningKeys", ctx)
+	ret0, _ := ret[0].([]domain.SigningKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSigningKeys indicates an expected call of GetSigningKeys.
+func (mr *MockkeyStorageMockRecorder) GetSigningKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSigningKeys", reflect.TypeOf((*MockkeyStorage)(nil).GetSigningKeys), ctx)
+}
+
+// RotateSigningKey mocks base method.
+func (m *MockkeyStorage) RotateSigningKey(ctx context.Context, active, retiring domain.SigningKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateSigningKey", ctx, active, retiring)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RotateSigningKey indicates an expected call of RotateSigningKey.
+func (mr *MockkeyStorageMockRecorder) RotateSigningKey(ctx, active, retiring interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateSigningKey", reflect.TypeOf((*MockkeyStorage)(nil).RotateSigningKey), ctx, active, retiring)
+}
+
+// MockobjectStorage is a mock of objectStorage interface.
+type MockobjectStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockobjectStorageMockRecorder
+}
+
+// MockobjectStorageMockRecorder is the mock recorder for MockobjectStorage.
+type MockobjectStorageMockRecorder struct {
+	mock *MockobjectStorage
+}
+
+// NewMockobjectStorage creates a new mock instance.
+func NewMockobjectStorage(ctrl *gomock.Controller) *MockobjectStorage {
+	mock := &MockobjectStorage{ctrl: ctrl}
+	mock.recorder = &MockobjectStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockobjectStorage) EXPECT() *MockobjectStorageMockRecorder {
+	return m.recorder
+}
+
+// DownloadData mocks base method.
+func (m *MockobjectStorage) DownloadData(ctx context.Context, userInfo *dto.UserInfo) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadData", ctx, userInfo)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadData indicates an expected call of DownloadData.
+func (mr *MockobjectStorageMockRecorder) DownloadData(ctx, userInfo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadData", reflect.TypeOf((*MockobjectStorage)(nil).DownloadData), ctx, userInfo)
+}
+
+// RemoveObject mocks base method.
+func (m *MockobjectStorage) RemoveObject(ctx context.Context, fileName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveObject", ctx, fileName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveObject indicates an expected call of RemoveObject.
+func (mr *MockobjectStorageMockRecorder) RemoveObject(ctx, fileName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveObject", reflect.TypeOf((*MockobjectStorage)(nil).RemoveObject), ctx, fileName)
+}
+
+// UploadData mocks base method.
+func (m *MockobjectStorage) UploadData(ctx context.Context, register *dto.Register) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadData", ctx, register)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadData indicates an expected call of UploadData.
+func (mr *MockobjectStorageMockRecorder) UploadData(ctx, register interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadData", reflect.TypeOf((*MockobjectStorage)(nil).UploadData), ctx, register)
+}
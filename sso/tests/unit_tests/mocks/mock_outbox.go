@@ -0,0 +1,132 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../sso/internal/services/outboxrelay/relay.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	gomock "github.com/golang/mock/gomock"
+	proto "google.golang.org/protobuf/proto"
+)
+
+// MockoutboxStorage is a mock of outboxStorage interface.
+type MockoutboxStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockoutboxStorageMockRecorder
+}
+
+// MockoutboxStorageMockRecorder is the mock recorder for MockoutboxStorage.
+type MockoutboxStorageMockRecorder struct {
+	mock *MockoutboxStorage
+}
+
+// NewMockoutboxStorage creates a new mock instance.
+func NewMockoutboxStorage(ctrl *gomock.Controller) *MockoutboxStorage {
+	mock := &MockoutboxStorage{ctrl: ctrl}
+	mock.recorder = &MockoutboxStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockoutboxStorage) EXPECT() *MockoutboxStorageMockRecorder {
+	return m.recorder
+}
+
+// ClaimOutboxMessages mocks base method.
+func (m *MockoutboxStorage) ClaimOutboxMessages(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimOutboxMessages", ctx, limit, lease)
+	ret0, _ := ret[0].([]domain.OutboxMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimOutboxMessages indicates an expected call of ClaimOutboxMessages.
+func (mr *MockoutboxStorageMockRecorder) ClaimOutboxMessages(ctx, limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimOutboxMessages", reflect.TypeOf((*MockoutboxStorage)(nil).ClaimOutboxMessages), ctx, limit, lease)
+}
+
+// MarkOutboxMessageSent mocks base method.
+func (m *MockoutboxStorage) MarkOutboxMessageSent(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxMessageSent", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxMessageSent indicates an expected call of MarkOutboxMessageSent.
+func (mr *MockoutboxStorageMockRecorder) MarkOutboxMessageSent(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxMessageSent", reflect.TypeOf((*MockoutboxStorage)(nil).MarkOutboxMessageSent), ctx, id)
+}
+
+// RetryOutboxMessage mocks base method.
+func (m *MockoutboxStorage) RetryOutboxMessage(ctx context.Context, id int64, reason string, delay time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryOutboxMessage", ctx, id, reason, delay)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryOutboxMessage indicates an expected call of RetryOutboxMessage.
+func (mr *MockoutboxStorageMockRecorder) RetryOutboxMessage(ctx, id, reason, delay interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryOutboxMessage", reflect.TypeOf((*MockoutboxStorage)(nil).RetryOutboxMessage), ctx, id, reason, delay)
+}
+
+// UpdateSendStatus mocks base method.
+func (m *MockoutboxStorage) UpdateSendStatus(ctx context.Context, uuid, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSendStatus", ctx, uuid, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSendStatus indicates an expected call of UpdateSendStatus.
+func (mr *MockoutboxStorageMockRecorder) UpdateSendStatus(ctx, uuid, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSendStatus", reflect.TypeOf((*MockoutboxStorage)(nil).UpdateSendStatus), ctx, uuid, status)
+}
+
+// MocksyncSender is a mock of syncSender interface.
+type MocksyncSender struct {
+	ctrl     *gomock.Controller
+	recorder *MocksyncSenderMockRecorder
+}
+
+// MocksyncSenderMockRecorder is the mock recorder for MocksyncSender.
+type MocksyncSenderMockRecorder struct {
+	mock *MocksyncSender
+}
+
+// NewMocksyncSender creates a new mock instance.
+func NewMocksyncSender(ctrl *gomock.Controller) *MocksyncSender {
+	mock := &MocksyncSender{ctrl: ctrl}
+	mock.recorder = &MocksyncSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocksyncSender) EXPECT() *MocksyncSenderMockRecorder {
+	return m.recorder
+}
+
+// SendSync mocks base method.
+func (m *MocksyncSender) SendSync(ctx context.Context, msg proto.Message, topic, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendSync", ctx, msg, topic, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendSync indicates an expected call of SendSync.
+func (mr *MocksyncSenderMockRecorder) SendSync(ctx, msg, topic, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendSync", reflect.TypeOf((*MocksyncSender)(nil).SendSync), ctx, msg, topic, key)
+}
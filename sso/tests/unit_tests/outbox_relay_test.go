@@ -0,0 +1,78 @@
+package unit_tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	registrationv1 "github.com/AlexBlackNn/authloyalty/commands/proto/registration.v1/registration.v1"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/config"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/logger"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/services/outboxrelay"
+	"github.com/AlexBlackNn/authloyalty/sso/tests/unit_tests/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestOutboxRelayPublishPending(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newMessage := func(id int64, uuid string, attempts int) domain.OutboxMessage {
+		payload, err := proto.Marshal(&registrationv1.RegistrationMessage{Uuid: uuid, Type: "registration"})
+		require.NoError(t, err)
+		return domain.OutboxMessage{
+			ID:       id,
+			Topic:    cfg.Kafka.Topic,
+			Key:      uuid,
+			Type:     string((&registrationv1.RegistrationMessage{}).ProtoReflect().Descriptor().FullName()),
+			Payload:  payload,
+			Attempts: attempts,
+		}
+	}
+	delivered := newMessage(1, "79d3ac44-5857-4185-ba92-1a224fbacb51", 1)
+	failed := newMessage(2, "7c2ab9ec-bddf-43ff-96a5-ff1e0785c909", 2)
+
+	storageMock := mocks.NewMockoutboxStorage(ctrl)
+	storageMock.EXPECT().
+		ClaimOutboxMessages(
+			gomock.Any(),
+			cfg.Outbox.BatchSize,
+			// batch is leased for delivery of every message
+			time.Duration(cfg.Outbox.BatchSize)*cfg.Outbox.DeliveryTimeout,
+		).
+		Return([]domain.OutboxMessage{delivered, failed}, nil)
+	// delivered message is never published again
+	storageMock.EXPECT().
+		MarkOutboxMessageSent(gomock.Any(), delivered.ID).
+		Return(nil)
+	storageMock.EXPECT().
+		UpdateSendStatus(gomock.Any(), delivered.Key, "successful").
+		Return(nil)
+	// failed message is retried with backoff
+	storageMock.EXPECT().
+		RetryOutboxMessage(gomock.Any(), failed.ID, gomock.Any(), 2*cfg.Outbox.RetryDelay).
+		Return(nil)
+	storageMock.EXPECT().
+		UpdateSendStatus(gomock.Any(), failed.Key, "failed").
+		Return(nil)
+
+	producerMock := mocks.NewMocksyncSender(ctrl)
+	producerMock.EXPECT().
+		SendSync(gomock.Any(), gomock.Any(), cfg.Kafka.Topic, delivered.Key).
+		DoAndReturn(func(_ context.Context, msg proto.Message, _, _ string) error {
+			require.Equal(t, delivered.Key, msg.(*registrationv1.RegistrationMessage).GetUuid())
+			return nil
+		})
+	producerMock.EXPECT().
+		SendSync(gomock.Any(), gomock.Any(), cfg.Kafka.Topic, failed.Key).
+		Return(errors.New("kafka is not available"))
+
+	relay := outboxrelay.New(cfg, log, storageMock, producerMock)
+	require.Equal(t, 2, relay.PublishPending(context.Background()))
+}
@@ -0,0 +1,124 @@
+package unit_tests
+
+import (
+	"context"
+	"testing"
+
+	notificationv1 "github.com/AlexBlackNn/authloyalty/commands/proto/notification.v1/notification.v1"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/config"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/dto"
+	jwtlib "github.com/AlexBlackNn/authloyalty/sso/internal/lib/jwt"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/logger"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/services/authservice"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/storage"
+	"github.com/AlexBlackNn/authloyalty/sso/pkg/broker"
+	"github.com/AlexBlackNn/authloyalty/sso/tests/unit_tests/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPasswordReset(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := domain.User{
+		ID:    "79d3ac44-5857-4185-ba92-1a224fbacb51",
+		Email: "test@test.com",
+	}
+	sessions := []domain.Session{{ID: "3f0e7c1e-1a4b-4b8e-9d55-0d6c7d1c2c11"}}
+
+	userStorageMock := mocks.NewMockuserStorage(ctrl)
+	userStorageMock.EXPECT().
+		GetUserByEmail(gomock.Any(), user.Email).
+		Return(user, nil)
+	userStorageMock.EXPECT().
+		UpdatePassword(gomock.Any(), user.ID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, passHash []byte) error {
+			return bcrypt.CompareHashAndPassword(passHash, []byte("new-password"))
+		})
+
+	var resetToken string
+	brokerMock := mocks.NewMockgetResponseChanSender(ctrl)
+	brokerMock.EXPECT().
+		GetResponseChan().
+		Return(make(chan *broker.Response)).
+		AnyTimes()
+	brokerMock.EXPECT().
+		Send(gomock.Any(), gomock.Any(), cfg.Kafka.PasswordResetTopic, user.ID).
+		DoAndReturn(func(_ context.Context, msg proto.Message, _, _ string) error {
+			resetToken = msg.(*notificationv1.PasswordResetMessage).GetToken()
+			return nil
+		})
+
+	keyStorageMock := mocks.NewMockkeyStorage(ctrl)
+	keyStorageMock.EXPECT().
+		GetSigningKeys(gomock.Any()).
+		Return(nil, nil).
+		AnyTimes()
+	keyStorageMock.EXPECT().
+		DeleteExpiredSigningKeys(gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
+	// only hash of the token sent to the user is kept in storage
+	var savedHash string
+	tokenStorageMock := mocks.NewMocktokenStorage(ctrl)
+	tokenStorageMock.EXPECT().
+		SavePasswordResetToken(gomock.Any(), gomock.Any(), user.ID, cfg.PasswordResetTokenTtl).
+		DoAndReturn(func(_ context.Context, tokenHash, _ string, _ any) error {
+			savedHash = tokenHash
+			return nil
+		})
+	gomock.InOrder(
+		tokenStorageMock.EXPECT().
+			ConsumePasswordResetToken(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, tokenHash string) (string, error) {
+				require.Equal(t, savedHash, tokenHash)
+				return user.ID, nil
+			}),
+		tokenStorageMock.EXPECT().
+			ConsumePasswordResetToken(gomock.Any(), gomock.Any()).
+			Return("", storage.ErrOneTimeTokenNotFound),
+	)
+	tokenStorageMock.EXPECT().
+		GetSessions(gomock.Any(), user.ID).
+		Return(sessions, nil)
+	tokenStorageMock.EXPECT().
+		RevokeTokenFamily(gomock.Any(), sessions[0].ID, cfg.RefreshTokenTtl).
+		Return(nil)
+	tokenStorageMock.EXPECT().
+		DeleteSession(gomock.Any(), user.ID, sessions[0].ID).
+		Return(nil)
+
+	signingKey, err := jwtlib.NewSigningKey(cfg.JWT.KeyID, cfg.JWT.Algorithm, cfg.JWT.PrivateKey)
+	require.NoError(t, err)
+	authService := authservice.New(
+		cfg,
+		log,
+		userStorageMock,
+		tokenStorageMock,
+		brokerMock,
+		mocks.NewMockobjectStorage(ctrl),
+		keyStorageMock,
+		jwtlib.NewKeyring(signingKey),
+	)
+
+	ctx := context.Background()
+	_, err = authService.RequestPasswordReset(ctx, &dto.RequestPasswordReset{Email: user.Email})
+	require.NoError(t, err)
+	require.NotEmpty(t, resetToken)
+	require.NotEqual(t, resetToken, savedHash)
+
+	confirm := &dto.ConfirmPasswordReset{Token: resetToken, Password: "new-password"}
+	_, err = authService.ConfirmPasswordReset(ctx, confirm)
+	require.NoError(t, err)
+
+	// reset token is single-use
+	_, err = authService.ConfirmPasswordReset(ctx, confirm)
+	require.ErrorIs(t, err, authservice.ErrResetTokenInvalid)
+}
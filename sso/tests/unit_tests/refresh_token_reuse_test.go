@@ -0,0 +1,120 @@
+package unit_tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/sso/internal/config"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/dto"
+	jwtlib "github.com/AlexBlackNn/authloyalty/sso/internal/lib/jwt"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/logger"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/services/authservice"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/storage"
+	"github.com/AlexBlackNn/authloyalty/sso/pkg/broker"
+	"github.com/AlexBlackNn/authloyalty/sso/tests/unit_tests/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	passHash, err := bcrypt.GenerateFromPassword([]byte("test"), bcrypt.DefaultCost)
+	require.NoError(t, err)
+	user := domain.User{
+		ID:       "79d3ac44-5857-4185-ba92-1a224fbacb51",
+		Email:    "test@test.com",
+		PassHash: passHash,
+	}
+	userStorageMock := mocks.NewMockuserStorage(ctrl)
+	userStorageMock.EXPECT().
+		GetUserByEmail(gomock.Any(), gomock.Any()).
+		Return(user, nil).
+		AnyTimes()
+
+	brokerMock := mocks.NewMockgetResponseChanSender(ctrl)
+	brokerMock.EXPECT().
+		GetResponseChan().
+		Return(make(chan *broker.Response)).
+		AnyTimes()
+
+	keyStorageMock := mocks.NewMockkeyStorage(ctrl)
+	keyStorageMock.EXPECT().
+		GetSigningKeys(gomock.Any()).
+		Return(nil, nil).
+		AnyTimes()
+	keyStorageMock.EXPECT().
+		DeleteExpiredSigningKeys(gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
+	tokenStorageMock := mocks.NewMocktokenStorage(ctrl)
+	tokenStorageMock.EXPECT().
+		LoginLockTTL(gomock.Any(), gomock.Any()).
+		Return(time.Duration(0), nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		ResetLoginFailures(gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		CheckTokenExists(gomock.Any(), gomock.Any()).
+		Return(int64(0), nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		CheckTokenFamilyRevoked(gomock.Any(), gomock.Any()).
+		Return(false, nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		SaveTokenFamily(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil)
+	tokenStorageMock.EXPECT().
+		SaveSession(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil)
+	tokenStorageMock.EXPECT().
+		RefreshSession(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil)
+	// the first refresh rotates the family, the replayed token is not the last one
+	gomock.InOrder(
+		tokenStorageMock.EXPECT().
+			RotateTokenFamily(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil),
+		tokenStorageMock.EXPECT().
+			RotateTokenFamily(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(storage.ErrTokenReused),
+	)
+	tokenStorageMock.EXPECT().
+		RevokeTokenFamily(gomock.Any(), gomock.Any(), cfg.RefreshTokenTtl).
+		Return(nil).
+		Times(1)
+
+	signingKey, err := jwtlib.NewSigningKey(cfg.JWT.KeyID, cfg.JWT.Algorithm, cfg.JWT.PrivateKey)
+	require.NoError(t, err)
+	authService := authservice.New(
+		cfg,
+		log,
+		userStorageMock,
+		tokenStorageMock,
+		brokerMock,
+		mocks.NewMockobjectStorage(ctrl),
+		keyStorageMock,
+		jwtlib.NewKeyring(signingKey),
+	)
+
+	ctx := context.Background()
+	login, err := authService.Login(ctx, &dto.Login{Email: user.Email, Password: "test"})
+	require.NoError(t, err)
+
+	refreshed, err := authService.Refresh(ctx, &dto.Refresh{Token: login.RefreshToken})
+	require.NoError(t, err)
+	require.Equal(t, login.FamilyID, refreshed.FamilyID)
+
+	_, err = authService.Refresh(ctx, &dto.Refresh{Token: login.RefreshToken})
+	require.ErrorIs(t, err, authservice.ErrTokenReused)
+}
@@ -0,0 +1,100 @@
+package unit_tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	registrationv1 "github.com/AlexBlackNn/authloyalty/commands/proto/registration.v1/registration.v1"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/config"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	jwtlib "github.com/AlexBlackNn/authloyalty/sso/internal/lib/jwt"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/logger"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/services/authservice"
+	"github.com/AlexBlackNn/authloyalty/sso/pkg/broker"
+	"github.com/AlexBlackNn/authloyalty/sso/tests/unit_tests/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRetryUndeliveredRegistrations(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	cfg.EmailVerification.HoldRegistrationBonus = true
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	failed := domain.UndeliveredRegistration{UserID: "79d3ac44-5857-4185-ba92-1a224fbacb51", Attempts: 2}
+	concurrent := domain.UndeliveredRegistration{UserID: "7c2ab9ec-bddf-43ff-96a5-ff1e0785c909"}
+
+	userStorageMock := mocks.NewMockuserStorage(ctrl)
+	userStorageMock.EXPECT().
+		CountExhaustedRegistrations(gomock.Any(), cfg.RegistrationRetry.MaxAttempts).
+		Return(1, nil)
+	// unverified users keep registration bonus held
+	userStorageMock.EXPECT().
+		GetUndeliveredRegistrations(
+			gomock.Any(),
+			cfg.Kafka.Topic,
+			cfg.RegistrationRetry.StaleAfter,
+			cfg.RegistrationRetry.MaxAttempts,
+			true,
+			cfg.RegistrationRetry.BatchSize,
+		).
+		Return([]domain.UndeliveredRegistration{failed, concurrent}, nil)
+	// delay doubles with each re-send
+	userStorageMock.EXPECT().
+		RetryRegistration(gomock.Any(), failed.UserID, failed.Attempts, 4*cfg.RegistrationRetry.RetryDelay, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ int, _ any, event domain.OutboxMessage) (bool, error) {
+			require.Equal(t, cfg.Kafka.Topic, event.Topic)
+			require.Equal(t, failed.UserID, event.Key)
+			var msg registrationv1.RegistrationMessage
+			require.NoError(t, proto.Unmarshal(event.Payload, &msg))
+			require.Equal(t, failed.UserID, msg.GetUuid())
+			// re-sent event has the same id, so loyalty grants the bonus once
+			require.Equal(t, "registration/"+failed.UserID, msg.GetEventId())
+			require.Equal(t, cfg.RegistrationBonus.Amount, msg.GetBonus())
+			require.Equal(t, cfg.RegistrationBonus.Campaign, msg.GetCampaign())
+			require.EqualValues(t, authservice.RegistrationSchemaVersion, msg.GetSchemaVersion())
+			require.WithinDuration(t, time.Now(), msg.GetOccurredAt().AsTime(), time.Minute)
+			return true, nil
+		})
+	// already retried by another instance
+	userStorageMock.EXPECT().
+		RetryRegistration(gomock.Any(), concurrent.UserID, 0, cfg.RegistrationRetry.RetryDelay, gomock.Any()).
+		Return(false, nil)
+
+	brokerMock := mocks.NewMockgetResponseChanSender(ctrl)
+	brokerMock.EXPECT().
+		GetResponseChan().
+		Return(make(chan *broker.Response)).
+		AnyTimes()
+
+	keyStorageMock := mocks.NewMockkeyStorage(ctrl)
+	keyStorageMock.EXPECT().
+		GetSigningKeys(gomock.Any()).
+		Return(nil, nil).
+		AnyTimes()
+	keyStorageMock.EXPECT().
+		DeleteExpiredSigningKeys(gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
+	signingKey, err := jwtlib.NewSigningKey(cfg.JWT.KeyID, cfg.JWT.Algorithm, cfg.JWT.PrivateKey)
+	require.NoError(t, err)
+	authService := authservice.New(
+		cfg,
+		log,
+		userStorageMock,
+		mocks.NewMocktokenStorage(ctrl),
+		brokerMock,
+		mocks.NewMockobjectStorage(ctrl),
+		keyStorageMock,
+		jwtlib.NewKeyring(signingKey),
+	)
+
+	retried, err := authService.RetryUndeliveredRegistrations(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, retried)
+}
@@ -0,0 +1,102 @@
+package unit_tests
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/AlexBlackNn/authloyalty/sso/internal/config"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	jwtlib "github.com/AlexBlackNn/authloyalty/sso/internal/lib/jwt"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/logger"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/services/authservice"
+	"github.com/AlexBlackNn/authloyalty/sso/pkg/broker"
+	"github.com/AlexBlackNn/authloyalty/sso/tests/unit_tests/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnknownSigningKeyReloadsKeys(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	signingKey, err := jwtlib.NewSigningKey(cfg.JWT.KeyID, cfg.JWT.Algorithm, cfg.JWT.PrivateKey)
+	require.NoError(t, err)
+	// key rotated by other instance after this instance loaded keys
+	rotatedKey, err := jwtlib.GenerateSigningKey(cfg.JWT.Algorithm)
+	require.NoError(t, err)
+	active, err := rotatedKey.Export()
+	require.NoError(t, err)
+	active.Active = true
+	retiring, err := signingKey.Export()
+	require.NoError(t, err)
+
+	loaded := make(chan struct{})
+	var loads atomic.Int32
+	keyStorageMock := mocks.NewMockkeyStorage(ctrl)
+	keyStorageMock.EXPECT().
+		GetSigningKeys(gomock.Any()).
+		DoAndReturn(func(ctx context.Context) ([]domain.SigningKey, error) {
+			if loads.Add(1) == 1 {
+				close(loaded)
+				return nil, nil
+			}
+			return []domain.SigningKey{active, retiring}, nil
+		}).
+		AnyTimes()
+	keyStorageMock.EXPECT().
+		DeleteExpiredSigningKeys(gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
+	brokerMock := mocks.NewMockgetResponseChanSender(ctrl)
+	brokerMock.EXPECT().
+		GetResponseChan().
+		Return(make(chan *broker.Response)).
+		AnyTimes()
+
+	tokenStorageMock := mocks.NewMocktokenStorage(ctrl)
+	tokenStorageMock.EXPECT().
+		CheckTokenExists(gomock.Any(), gomock.Any()).
+		Return(int64(0), nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		CheckTokenFamilyRevoked(gomock.Any(), gomock.Any()).
+		Return(false, nil).
+		AnyTimes()
+
+	authService := authservice.New(
+		cfg,
+		log,
+		mocks.NewMockuserStorage(ctrl),
+		tokenStorageMock,
+		brokerMock,
+		mocks.NewMockobjectStorage(ctrl),
+		keyStorageMock,
+		jwtlib.NewKeyring(signingKey),
+	)
+	// wait for the first periodic load, the next one is in jwt.reloadInterval
+	<-loaded
+
+	user := domain.User{ID: "79d3ac44-5857-4185-ba92-1a224fbacb51", Email: "test@test.com"}
+	token, err := jwtlib.NewToken(user, cfg, "access", rotatedKey, "family", "token", nil)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	valid, err := authService.Validate(ctx, token)
+	require.NoError(t, err)
+	require.True(t, valid)
+	require.Equal(t, int32(2), loads.Load())
+
+	// token signed by unknown key doesn't load keys again until
+	// jwt.unknownKeyReloadInterval passes
+	unknownKey, err := jwtlib.GenerateSigningKey(cfg.JWT.Algorithm)
+	require.NoError(t, err)
+	token, err = jwtlib.NewToken(user, cfg, "access", unknownKey, "family", "token", nil)
+	require.NoError(t, err)
+	_, err = authService.Validate(ctx, token)
+	require.ErrorIs(t, err, authservice.ErrTokenParsing)
+	require.Equal(t, int32(2), loads.Load())
+}
@@ -0,0 +1,49 @@
+package unit_tests
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlexBlackNn/authloyalty/sso/internal/lib/totp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTP(t *testing.T) {
+	// RFC 6238 test vectors (SHA1), truncated to 6 digits
+	secret := strings.TrimRight(
+		base32.StdEncoding.EncodeToString([]byte("12345678901234567890")), "=",
+	)
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+	}
+	for _, tt := range tests {
+		code, err := totp.Code(secret, time.Unix(tt.unix, 0))
+		require.NoError(t, err)
+		require.Equal(t, tt.code, code)
+		step, ok := totp.Validate(secret, tt.code, time.Unix(tt.unix, 0))
+		require.True(t, ok)
+		require.Equal(t, tt.unix/30, step)
+	}
+
+	now := time.Now()
+	code, err := totp.Code(secret, now)
+	require.NoError(t, err)
+	step, ok := totp.Validate(secret, code, now)
+	require.True(t, ok)
+	// one period of clock drift is tolerated, step of the code is returned
+	driftStep, ok := totp.Validate(secret, code, now.Add(30*time.Second))
+	require.True(t, ok)
+	require.Equal(t, step, driftStep)
+	_, ok = totp.Validate(secret, code, now.Add(5*time.Minute))
+	require.False(t, ok)
+	_, ok = totp.Validate(secret, "", now)
+	require.False(t, ok)
+}
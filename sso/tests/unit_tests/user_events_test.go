@@ -0,0 +1,305 @@
+package unit_tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	eventsv1 "github.com/AlexBlackNn/authloyalty/commands/proto/events.v1/events.v1"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/config"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/domain"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/dto"
+	jwtlib "github.com/AlexBlackNn/authloyalty/sso/internal/lib/jwt"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/logger"
+	"github.com/AlexBlackNn/authloyalty/sso/internal/services/authservice"
+	"github.com/AlexBlackNn/authloyalty/sso/pkg/broker"
+	"github.com/AlexBlackNn/authloyalty/sso/tests/unit_tests/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestUserChangesSaveUserEvents(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	cfg.MFA.RequiredForAdmins = false
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	passHash, err := bcrypt.GenerateFromPassword([]byte("test"), bcrypt.DefaultCost)
+	require.NoError(t, err)
+	admin := domain.User{
+		ID:          "7c2ab9ec-bddf-43ff-96a5-ff1e0785c909",
+		Email:       "admin@test.com",
+		PassHash:    passHash,
+		Roles:       []string{domain.RoleAdmin},
+		Permissions: []string{domain.PermissionRolesManage, domain.PermissionUsersManage},
+	}
+	userID := "79d3ac44-5857-4185-ba92-1a224fbacb51"
+
+	userStorageMock := mocks.NewMockuserStorage(ctrl)
+	userStorageMock.EXPECT().
+		GetUserByEmail(gomock.Any(), admin.Email).
+		Return(admin, nil).
+		AnyTimes()
+	userStorageMock.EXPECT().
+		GetUser(gomock.Any(), admin.ID).
+		Return(admin, nil).
+		AnyTimes()
+	// events are saved to outbox together with the user change
+	var events []*eventsv1.UserEvent
+	saveEvent := func(event domain.OutboxMessage) error {
+		require.Equal(t, cfg.Kafka.UserEventsTopic, event.Topic)
+		require.Equal(t, userID, event.Key)
+		var userEvent eventsv1.UserEvent
+		require.NoError(t, proto.Unmarshal(event.Payload, &userEvent))
+		events = append(events, &userEvent)
+		return nil
+	}
+	userStorageMock.EXPECT().
+		GrantRole(gomock.Any(), userID, domain.RoleAdmin, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ string, events ...domain.OutboxMessage) error {
+			require.Len(t, events, 1)
+			return saveEvent(events[0])
+		})
+	userStorageMock.EXPECT().
+		RevokeRole(gomock.Any(), userID, domain.RoleAdmin, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ string, events ...domain.OutboxMessage) error {
+			require.Len(t, events, 1)
+			return saveEvent(events[0])
+		})
+	until := time.Now().Add(time.Hour).Truncate(time.Second)
+	userStorageMock.EXPECT().
+		BlockUser(gomock.Any(), userID, "fraud", &until, gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context, _ string, _ string, _ *time.Time, events ...domain.OutboxMessage,
+		) error {
+			require.Len(t, events, 1)
+			return saveEvent(events[0])
+		})
+	userStorageMock.EXPECT().
+		UnblockUser(gomock.Any(), userID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, events ...domain.OutboxMessage) error {
+			require.Len(t, events, 1)
+			return saveEvent(events[0])
+		})
+	userStorageMock.EXPECT().
+		DeleteUser(gomock.Any(), userID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, events ...domain.OutboxMessage) error {
+			require.Len(t, events, 1)
+			return saveEvent(events[0])
+		})
+
+	brokerMock := mocks.NewMockgetResponseChanSender(ctrl)
+	brokerMock.EXPECT().
+		GetResponseChan().
+		Return(make(chan *broker.Response)).
+		AnyTimes()
+
+	keyStorageMock := mocks.NewMockkeyStorage(ctrl)
+	keyStorageMock.EXPECT().
+		GetSigningKeys(gomock.Any()).
+		Return(nil, nil).
+		AnyTimes()
+	keyStorageMock.EXPECT().
+		DeleteExpiredSigningKeys(gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
+	tokenStorageMock := mocks.NewMocktokenStorage(ctrl)
+	tokenStorageMock.EXPECT().
+		LoginLockTTL(gomock.Any(), gomock.Any()).
+		Return(time.Duration(0), nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		ResetLoginFailures(gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		SaveTokenFamily(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		SaveSession(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		CheckTokenExists(gomock.Any(), gomock.Any()).
+		Return(int64(0), nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		CheckTokenFamilyRevoked(gomock.Any(), gomock.Any()).
+		Return(false, nil).
+		AnyTimes()
+	// sessions of blocked and deleted user are revoked
+	tokenStorageMock.EXPECT().
+		GetSessions(gomock.Any(), userID).
+		Return(nil, nil).
+		Times(2)
+
+	signingKey, err := jwtlib.NewSigningKey(cfg.JWT.KeyID, cfg.JWT.Algorithm, cfg.JWT.PrivateKey)
+	require.NoError(t, err)
+	authService := authservice.New(
+		cfg,
+		log,
+		userStorageMock,
+		tokenStorageMock,
+		brokerMock,
+		mocks.NewMockobjectStorage(ctrl),
+		keyStorageMock,
+		jwtlib.NewKeyring(signingKey),
+	)
+
+	ctx := context.Background()
+	adminLogin, err := authService.Login(ctx, &dto.Login{Email: admin.Email, Password: "test"})
+	require.NoError(t, err)
+
+	userRole := &dto.UserRole{UserID: userID, Role: domain.RoleAdmin}
+	require.NoError(t, authService.GrantRole(ctx, adminLogin.AccessToken, userRole))
+	require.NoError(t, authService.RevokeRole(ctx, adminLogin.AccessToken, userRole))
+	require.NoError(t, authService.BlockUser(ctx, adminLogin.AccessToken, &dto.BlockUser{
+		UserID: userID, Reason: "fraud", Until: &until,
+	}))
+	require.NoError(t, authService.UnblockUser(ctx, adminLogin.AccessToken, &dto.UserID{UserID: userID}))
+	require.NoError(t, authService.DeleteUser(ctx, adminLogin.AccessToken, &dto.UserID{UserID: userID}))
+
+	require.Len(t, events, 5)
+	eventIDs := make(map[string]bool)
+	for _, event := range events {
+		require.NotEmpty(t, event.GetEventId())
+		require.Equal(t, userID, event.GetUserId())
+		require.EqualValues(t, authservice.UserEventSchemaVersion, event.GetSchemaVersion())
+		require.NotNil(t, event.GetOccurredAt())
+		eventIDs[event.GetEventId()] = true
+	}
+	require.Len(t, eventIDs, len(events))
+	for i, granted := range []bool{true, false} {
+		require.Equal(t, domain.RoleAdmin, events[i].GetRoleChanged().GetRole())
+		require.Equal(t, granted, events[i].GetRoleChanged().GetGranted())
+	}
+	require.Equal(t, "fraud", events[2].GetUserBlocked().GetReason())
+	require.Equal(t, until, events[2].GetUserBlocked().GetUntil().AsTime().Local())
+	require.NotNil(t, events[3].GetUserUnblocked())
+	require.NotNil(t, events[4].GetUserDeleted())
+}
+
+func TestBlockedUserLoginAndEmailChange(t *testing.T) {
+	cfg := config.MustLoadByPath("../../config/local.yaml")
+	cfg.EmailVerification.AllowUnverifiedLogin = true
+	log := logger.New(cfg.Env)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	passHash, err := bcrypt.GenerateFromPassword([]byte("test"), bcrypt.DefaultCost)
+	require.NoError(t, err)
+	user := domain.User{
+		ID:       "79d3ac44-5857-4185-ba92-1a224fbacb51",
+		Email:    "user@test.com",
+		PassHash: passHash,
+	}
+	blocked := domain.User{
+		ID:       "1f6c2f4e-3b1a-4d7e-9c51-0a2b3c4d5e6f",
+		Email:    "blocked@test.com",
+		PassHash: passHash,
+		Blocked:  true,
+	}
+
+	userStorageMock := mocks.NewMockuserStorage(ctrl)
+	for _, u := range []domain.User{user, blocked} {
+		userStorageMock.EXPECT().
+			GetUserByEmail(gomock.Any(), u.Email).
+			Return(u, nil).
+			AnyTimes()
+		userStorageMock.EXPECT().
+			GetUser(gomock.Any(), u.ID).
+			Return(u, nil).
+			AnyTimes()
+	}
+	userStorageMock.EXPECT().
+		UpdateEmail(gomock.Any(), user.ID, "new@test.com", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ string, events ...domain.OutboxMessage) error {
+			require.Len(t, events, 1)
+			var event eventsv1.UserEvent
+			require.NoError(t, proto.Unmarshal(events[0].Payload, &event))
+			require.NotNil(t, event.GetEmailChanged())
+			require.False(t, event.GetEmailChanged().GetVerified())
+			return nil
+		})
+
+	brokerMock := mocks.NewMockgetResponseChanSender(ctrl)
+	brokerMock.EXPECT().
+		GetResponseChan().
+		Return(make(chan *broker.Response)).
+		AnyTimes()
+	// verification email is sent to the new email
+	brokerMock.EXPECT().
+		Send(gomock.Any(), gomock.Any(), cfg.Kafka.EmailVerificationTopic, user.ID).
+		Return(nil)
+
+	keyStorageMock := mocks.NewMockkeyStorage(ctrl)
+	keyStorageMock.EXPECT().
+		GetSigningKeys(gomock.Any()).
+		Return(nil, nil).
+		AnyTimes()
+	keyStorageMock.EXPECT().
+		DeleteExpiredSigningKeys(gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
+	tokenStorageMock := mocks.NewMocktokenStorage(ctrl)
+	tokenStorageMock.EXPECT().
+		LoginLockTTL(gomock.Any(), gomock.Any()).
+		Return(time.Duration(0), nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		ResetLoginFailures(gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		SaveTokenFamily(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		SaveSession(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		CheckTokenExists(gomock.Any(), gomock.Any()).
+		Return(int64(0), nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		CheckTokenFamilyRevoked(gomock.Any(), gomock.Any()).
+		Return(false, nil).
+		AnyTimes()
+	tokenStorageMock.EXPECT().
+		SaveEmailVerificationToken(gomock.Any(), gomock.Any(), user.ID, gomock.Any()).
+		Return(nil)
+
+	signingKey, err := jwtlib.NewSigningKey(cfg.JWT.KeyID, cfg.JWT.Algorithm, cfg.JWT.PrivateKey)
+	require.NoError(t, err)
+	authService := authservice.New(
+		cfg,
+		log,
+		userStorageMock,
+		tokenStorageMock,
+		brokerMock,
+		mocks.NewMockobjectStorage(ctrl),
+		keyStorageMock,
+		jwtlib.NewKeyring(signingKey),
+	)
+
+	ctx := context.Background()
+	_, err = authService.Login(ctx, &dto.Login{Email: blocked.Email, Password: "test"})
+	require.ErrorIs(t, err, authservice.ErrUserBlocked)
+
+	login, err := authService.Login(ctx, &dto.Login{Email: user.Email, Password: "test"})
+	require.NoError(t, err)
+	err = authService.ChangeEmail(ctx, login.AccessToken, &dto.ChangeEmail{
+		Email: "new@test.com", Password: "wrong",
+	})
+	require.ErrorIs(t, err, authservice.ErrInvalidCredentials)
+	require.NoError(t, authService.ChangeEmail(ctx, login.AccessToken, &dto.ChangeEmail{
+		Email: "new@test.com", Password: "test",
+	}))
+}